@@ -0,0 +1,481 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+
+	"github.com/aerospike/aerospike-client-go/pkg/bcrypt"
+)
+
+const (
+	// admin commands
+	_AUTHENTICATE    byte = 0
+	_CREATE_USER     byte = 1
+	_DROP_USER       byte = 2
+	_SET_PASSWORD    byte = 3
+	_CHANGE_PASSWORD byte = 4
+	_GRANT_ROLES     byte = 5
+	_REVOKE_ROLES    byte = 6
+	_QUERY_USERS     byte = 9
+	_LOGIN           byte = 20
+
+	// admin field IDs
+	_USER           byte = 0
+	_PASSWORD       byte = 1
+	_OLD_PASSWORD   byte = 2
+	_CREDENTIAL     byte = 3
+	_CLEAR_PASSWORD byte = 4
+	_SESSION_TOKEN  byte = 5
+	_SESSION_TTL    byte = 6
+	_ROLES          byte = 10
+
+	// proto message type of admin messages
+	_AS_MSG_TYPE_ADMIN int64 = 2
+
+	// size of the proto header and of the admin header which follows it
+	_ADMIN_PROTO_SIZE  = 8
+	_ADMIN_HEADER_SIZE = 16
+
+	// offset of the result code in the admin header
+	_ADMIN_RESULT_CODE = 1
+
+	// salt the server hashes passwords with
+	_PASSWORD_SALT = "$2a$10$7EqJtq98hPqEX7fNZaFWoO"
+
+	// sessions are renewed this long before the server expires them
+	_SESSION_RENEWAL_MARGIN = 60 * time.Second
+)
+
+// adminCommand builds and sends the messages of the security admin protocol.
+type adminCommand struct {
+	dataBuffer []byte
+}
+
+func newAdminCommand() *adminCommand {
+	return &adminCommand{
+		dataBuffer: make([]byte, _ADMIN_PROTO_SIZE, 256),
+	}
+}
+
+// hashPassword hashes the password the way the server stores it.
+func hashPassword(password string) ([]byte, error) {
+	hash, err := bcrypt.HashPassword(password, _PASSWORD_SALT)
+	if err != nil {
+		return nil, NewAerospikeError(INVALID_PASSWORD, err.Error())
+	}
+	return []byte(hash), nil
+}
+
+// login logs the user in on a newly opened connection, and returns the session token
+// to authenticate other connections with, and the time the token expires.
+// The token is nil if the server does not use sessions, and the expiration
+// is zero if the session does not expire.
+func (acmd *adminCommand) login(conn *Connection, authMode AuthMode, user string, password, clearPassword []byte) ([]byte, time.Time, error) {
+	if authMode == AuthModeInternal {
+		acmd.writeHeader(_LOGIN, 2)
+		acmd.writeField(_USER, []byte(user))
+		acmd.writeField(_CREDENTIAL, password)
+	} else {
+		acmd.writeHeader(_LOGIN, 3)
+		acmd.writeField(_USER, []byte(user))
+		acmd.writeField(_CREDENTIAL, password)
+		acmd.writeField(_CLEAR_PASSWORD, clearPassword)
+	}
+	acmd.writeSize()
+
+	if _, err := conn.Write(acmd.dataBuffer); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	buf, err := acmd.readResponse(conn)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	switch resultCode := ResultCode(buf[_ADMIN_RESULT_CODE]); resultCode {
+	case OK:
+	case SECURITY_NOT_ENABLED:
+		// servers without security accept the connection as is
+		return nil, time.Time{}, nil
+	case INVALID_COMMAND:
+		// servers without the login command only support internal users
+		if authMode != AuthModeInternal {
+			return nil, time.Time{}, NewAerospikeError(resultCode, "Server does not support external authentication")
+		}
+		return nil, time.Time{}, acmd.authenticate(conn, user, password)
+	default:
+		return nil, time.Time{}, NewAerospikeError(resultCode)
+	}
+
+	var token []byte
+	var expiration time.Time
+	fieldCount := int(buf[3])
+	offset := _ADMIN_HEADER_SIZE
+	for i := 0; i < fieldCount; i++ {
+		if offset+5 > len(buf) {
+			return nil, time.Time{}, NewAerospikeError(PARSE_ERROR, "Invalid login response")
+		}
+		size := int(Buffer.BytesToInt32(buf, offset)) - 1
+		id := buf[offset+4]
+		offset += 5
+		if size < 0 || offset+size > len(buf) {
+			return nil, time.Time{}, NewAerospikeError(PARSE_ERROR, "Invalid login response")
+		}
+
+		switch id {
+		case _SESSION_TOKEN:
+			token = append([]byte{}, buf[offset:offset+size]...)
+		case _SESSION_TTL:
+			if size == 4 {
+				// renew the session before the server expires it
+				ttl := time.Duration(Buffer.BytesToInt32(buf, offset)) * time.Second
+				if ttl > _SESSION_RENEWAL_MARGIN {
+					ttl -= _SESSION_RENEWAL_MARGIN
+				}
+				if ttl > 0 {
+					expiration = time.Now().Add(ttl)
+				}
+			}
+		}
+		offset += size
+	}
+
+	if token == nil {
+		return nil, time.Time{}, NewAerospikeError(PARSE_ERROR, "Login response did not contain a session token")
+	}
+	return token, expiration, nil
+}
+
+// authenticateSession authenticates a newly opened connection with the session token of a previous login.
+func (acmd *adminCommand) authenticateSession(conn *Connection, user string, token []byte) error {
+	acmd.writeHeader(_AUTHENTICATE, 2)
+	acmd.writeField(_USER, []byte(user))
+	acmd.writeField(_SESSION_TOKEN, token)
+
+	return acmd.executeOnConnection(conn)
+}
+
+// authenticate logs the user in on a newly opened connection,
+// for servers which do not support the login command.
+func (acmd *adminCommand) authenticate(conn *Connection, user string, password []byte) error {
+	acmd.writeHeader(_AUTHENTICATE, 2)
+	acmd.writeField(_USER, []byte(user))
+	acmd.writeField(_CREDENTIAL, password)
+
+	err := acmd.executeOnConnection(conn)
+	if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == SECURITY_NOT_ENABLED {
+		// servers without security accept the connection as is
+		return nil
+	}
+	return err
+}
+
+func (acmd *adminCommand) createUser(cluster *Cluster, policy *AdminPolicy, user string, password []byte, roles []string) error {
+	acmd.writeHeader(_CREATE_USER, 3)
+	acmd.writeField(_USER, []byte(user))
+	acmd.writeField(_PASSWORD, password)
+	acmd.writeRoles(roles)
+
+	return acmd.executeCommand(cluster, policy)
+}
+
+func (acmd *adminCommand) dropUser(cluster *Cluster, policy *AdminPolicy, user string) error {
+	acmd.writeHeader(_DROP_USER, 1)
+	acmd.writeField(_USER, []byte(user))
+
+	return acmd.executeCommand(cluster, policy)
+}
+
+func (acmd *adminCommand) setPassword(cluster *Cluster, policy *AdminPolicy, user string, password []byte) error {
+	acmd.writeHeader(_SET_PASSWORD, 2)
+	acmd.writeField(_USER, []byte(user))
+	acmd.writeField(_PASSWORD, password)
+
+	return acmd.executeCommand(cluster, policy)
+}
+
+func (acmd *adminCommand) changePassword(cluster *Cluster, policy *AdminPolicy, user string, oldPassword, password []byte) error {
+	acmd.writeHeader(_CHANGE_PASSWORD, 3)
+	acmd.writeField(_USER, []byte(user))
+	acmd.writeField(_OLD_PASSWORD, oldPassword)
+	acmd.writeField(_PASSWORD, password)
+
+	return acmd.executeCommand(cluster, policy)
+}
+
+func (acmd *adminCommand) grantRoles(cluster *Cluster, policy *AdminPolicy, user string, roles []string) error {
+	acmd.writeHeader(_GRANT_ROLES, 2)
+	acmd.writeField(_USER, []byte(user))
+	acmd.writeRoles(roles)
+
+	return acmd.executeCommand(cluster, policy)
+}
+
+func (acmd *adminCommand) revokeRoles(cluster *Cluster, policy *AdminPolicy, user string, roles []string) error {
+	acmd.writeHeader(_REVOKE_ROLES, 2)
+	acmd.writeField(_USER, []byte(user))
+	acmd.writeRoles(roles)
+
+	return acmd.executeCommand(cluster, policy)
+}
+
+// queryUsers retrieves the roles of the user, or of all users if user is empty.
+func (acmd *adminCommand) queryUsers(cluster *Cluster, policy *AdminPolicy, user string) ([]*UserRoles, error) {
+	if user != "" {
+		acmd.writeHeader(_QUERY_USERS, 1)
+		acmd.writeField(_USER, []byte(user))
+	} else {
+		acmd.writeHeader(_QUERY_USERS, 0)
+	}
+
+	conn, node, err := acmd.send(cluster, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := acmd.readUsers(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	node.PutConnection(conn)
+	return list, nil
+}
+
+func (acmd *adminCommand) writeHeader(command byte, fieldCount int) {
+	acmd.dataBuffer = acmd.dataBuffer[:_ADMIN_PROTO_SIZE]
+	header := make([]byte, _ADMIN_HEADER_SIZE)
+	header[2] = command
+	header[3] = byte(fieldCount)
+	acmd.dataBuffer = append(acmd.dataBuffer, header...)
+}
+
+func (acmd *adminCommand) writeFieldHeader(id byte, size int) {
+	var header [5]byte
+	Buffer.Int32ToBytes(int32(size+1), header[:], 0)
+	header[4] = id
+	acmd.dataBuffer = append(acmd.dataBuffer, header[:]...)
+}
+
+func (acmd *adminCommand) writeField(id byte, value []byte) {
+	acmd.writeFieldHeader(id, len(value))
+	acmd.dataBuffer = append(acmd.dataBuffer, value...)
+}
+
+func (acmd *adminCommand) writeRoles(roles []string) {
+	size := 1
+	for _, role := range roles {
+		size += len(role) + 1
+	}
+
+	acmd.writeFieldHeader(_ROLES, size)
+	acmd.dataBuffer = append(acmd.dataBuffer, byte(len(roles)))
+	for _, role := range roles {
+		acmd.dataBuffer = append(acmd.dataBuffer, byte(len(role)))
+		acmd.dataBuffer = append(acmd.dataBuffer, role...)
+	}
+}
+
+// writeSize sets the proto header, once the message is complete.
+func (acmd *adminCommand) writeSize() {
+	size := int64(len(acmd.dataBuffer)-_ADMIN_PROTO_SIZE) | (_CL_MSG_VERSION << 56) | (_AS_MSG_TYPE_ADMIN << 48)
+	Buffer.Int64ToBytes(size, acmd.dataBuffer, 0)
+}
+
+// send writes the command to a connection of a random node.
+func (acmd *adminCommand) send(cluster *Cluster, policy *AdminPolicy) (*Connection, *Node, error) {
+	acmd.writeSize()
+
+	node, err := cluster.GetRandomNode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var timeout time.Duration
+	if policy != nil {
+		timeout = policy.Timeout
+	}
+
+	conn, err := node.GetConnection(timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := conn.Write(acmd.dataBuffer); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, node, nil
+}
+
+func (acmd *adminCommand) executeCommand(cluster *Cluster, policy *AdminPolicy) error {
+	conn, node, err := acmd.send(cluster, policy)
+	if err != nil {
+		return err
+	}
+
+	if err := acmd.readResult(conn); err != nil {
+		// the connection is still usable after a server error
+		if _, ok := err.(AerospikeError); ok {
+			node.PutConnection(conn)
+		} else {
+			conn.Close()
+		}
+		return err
+	}
+
+	node.PutConnection(conn)
+	return nil
+}
+
+func (acmd *adminCommand) executeOnConnection(conn *Connection) error {
+	acmd.writeSize()
+
+	if _, err := conn.Write(acmd.dataBuffer); err != nil {
+		return err
+	}
+	return acmd.readResult(conn)
+}
+
+// readResponse reads a single message response, and returns it without the proto header.
+func (acmd *adminCommand) readResponse(conn *Connection) ([]byte, error) {
+	header := make([]byte, _ADMIN_PROTO_SIZE)
+	if _, err := conn.Read(header, _ADMIN_PROTO_SIZE); err != nil {
+		return nil, err
+	}
+
+	size := int(Buffer.BytesToInt64(header, 0) & 0xFFFFFFFFFFFF)
+	if size < _ADMIN_HEADER_SIZE {
+		return nil, NewAerospikeError(PARSE_ERROR, "Invalid admin response size")
+	}
+
+	buf := make([]byte, size)
+	if _, err := conn.Read(buf, size); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// readResult reads the response of a command which only returns a result code.
+func (acmd *adminCommand) readResult(conn *Connection) error {
+	buf, err := acmd.readResponse(conn)
+	if err != nil {
+		return err
+	}
+
+	if resultCode := ResultCode(buf[_ADMIN_RESULT_CODE]); resultCode != OK {
+		return NewAerospikeError(resultCode)
+	}
+	return nil
+}
+
+// readUsers reads the response blocks of a query, until the server sends QUERY_END.
+func (acmd *adminCommand) readUsers(conn *Connection) ([]*UserRoles, error) {
+	list := []*UserRoles{}
+	header := make([]byte, _ADMIN_PROTO_SIZE)
+	for {
+		if _, err := conn.Read(header, _ADMIN_PROTO_SIZE); err != nil {
+			return nil, err
+		}
+
+		size := int(Buffer.BytesToInt64(header, 0) & 0xFFFFFFFFFFFF)
+		if size <= 0 {
+			continue
+		}
+
+		buf := make([]byte, size)
+		if _, err := conn.Read(buf, size); err != nil {
+			return nil, err
+		}
+
+		done, err := acmd.parseUsers(buf, &list)
+		if err != nil || done {
+			return list, err
+		}
+	}
+}
+
+// parseUsers parses the users in a response block, and returns true
+// if it was the last block.
+func (acmd *adminCommand) parseUsers(buf []byte, list *[]*UserRoles) (bool, error) {
+	offset := 0
+	for offset+_ADMIN_HEADER_SIZE <= len(buf) {
+		resultCode := ResultCode(buf[offset+_ADMIN_RESULT_CODE])
+		if resultCode == QUERY_END {
+			return true, nil
+		} else if resultCode != OK {
+			return true, NewAerospikeError(resultCode)
+		}
+
+		fieldCount := int(buf[offset+3])
+		offset += _ADMIN_HEADER_SIZE
+
+		userRoles := &UserRoles{}
+		for i := 0; i < fieldCount; i++ {
+			if offset+5 > len(buf) {
+				return true, NewAerospikeError(PARSE_ERROR, "Invalid user query response")
+			}
+			size := int(Buffer.BytesToInt32(buf, offset)) - 1
+			id := buf[offset+4]
+			offset += 5
+			if size < 0 || offset+size > len(buf) {
+				return true, NewAerospikeError(PARSE_ERROR, "Invalid user query response")
+			}
+
+			switch id {
+			case _USER:
+				userRoles.User = string(buf[offset : offset+size])
+			case _ROLES:
+				userRoles.Roles = parseRoles(buf[offset : offset+size])
+			}
+			offset += size
+		}
+
+		if userRoles.User == "" && userRoles.Roles == nil {
+			continue
+		}
+		if userRoles.Roles == nil {
+			userRoles.Roles = []string{}
+		}
+		*list = append(*list, userRoles)
+	}
+	return false, nil
+}
+
+func parseRoles(buf []byte) []string {
+	roles := []string{}
+	if len(buf) == 0 {
+		return roles
+	}
+
+	count := int(buf[0])
+	offset := 1
+	for i := 0; i < count && offset < len(buf); i++ {
+		size := int(buf[offset])
+		offset++
+		if offset+size > len(buf) {
+			break
+		}
+		roles = append(roles, string(buf[offset:offset+size]))
+		offset += size
+	}
+	return roles
+}
@@ -0,0 +1,261 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"io"
+	"net"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// adminHeader returns the 16 byte header of an admin response message.
+func adminHeader(resultCode ResultCode, fieldCount int) []byte {
+	header := make([]byte, _ADMIN_HEADER_SIZE)
+	header[_ADMIN_RESULT_CODE] = byte(resultCode)
+	header[3] = byte(fieldCount)
+	return header
+}
+
+// adminField returns a field of an admin message.
+func adminField(id byte, value []byte) []byte {
+	field := make([]byte, 5, 5+len(value))
+	Buffer.Int32ToBytes(int32(len(value)+1), field, 0)
+	field[4] = id
+	return append(field, value...)
+}
+
+// adminRoles returns the value of a roles field.
+func adminRoles(roles ...string) []byte {
+	res := []byte{byte(len(roles))}
+	for _, role := range roles {
+		res = append(res, byte(len(role)))
+		res = append(res, role...)
+	}
+	return res
+}
+
+func adminMessage(parts ...[]byte) []byte {
+	var res []byte
+	for _, part := range parts {
+		res = append(res, part...)
+	}
+	return res
+}
+
+// serveAdmin reads a request from the connection, sends it to requests
+// and answers it with the response message.
+func serveAdmin(conn net.Conn, response []byte, requests chan<- []byte) {
+	defer conn.Close()
+
+	header := make([]byte, _ADMIN_PROTO_SIZE)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	request := make([]byte, Buffer.BytesToInt64(header, 0)&0xFFFFFFFFFFFF)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return
+	}
+	requests <- request
+
+	proto := make([]byte, _ADMIN_PROTO_SIZE)
+	Buffer.Int64ToBytes(int64(len(response))|(_CL_MSG_VERSION<<56)|(_AS_MSG_TYPE_ADMIN<<48), proto, 0)
+	conn.Write(append(proto, response...))
+}
+
+var _ = Describe("Admin Command Test", func() {
+
+	Context("Roles", func() {
+
+		It("must parse the roles of a user", func() {
+			Expect(parseRoles(nil)).To(Equal([]string{}))
+			Expect(parseRoles(adminRoles())).To(Equal([]string{}))
+			Expect(parseRoles(adminRoles("read", "read-write"))).To(Equal([]string{"read", "read-write"}))
+		})
+
+		It("must return the complete roles of a truncated field", func() {
+			buf := adminRoles("read", "read-write")
+			Expect(parseRoles(buf[:len(buf)-1])).To(Equal([]string{"read"}))
+
+			// the count is larger than the roles in the field
+			buf[0] = 5
+			Expect(parseRoles(buf)).To(Equal([]string{"read", "read-write"}))
+		})
+	})
+
+	Context("Users", func() {
+
+		It("must parse the users of a block", func() {
+			buf := adminMessage(
+				adminHeader(OK, 2), adminField(_USER, []byte("alice")), adminField(_ROLES, adminRoles("read", "sys-admin")),
+				adminHeader(OK, 1), adminField(_USER, []byte("bob")),
+			)
+
+			list := []*UserRoles{}
+			done, err := newAdminCommand().parseUsers(buf, &list)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(list).To(Equal([]*UserRoles{
+				{User: "alice", Roles: []string{"read", "sys-admin"}},
+				{User: "bob", Roles: []string{}},
+			}))
+		})
+
+		It("must stop at the end of the query", func() {
+			buf := adminMessage(
+				adminHeader(OK, 1), adminField(_USER, []byte("alice")),
+				adminHeader(QUERY_END, 0),
+				adminHeader(OK, 1), adminField(_USER, []byte("bob")),
+			)
+
+			list := []*UserRoles{}
+			done, err := newAdminCommand().parseUsers(buf, &list)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeTrue())
+			Expect(list).To(Equal([]*UserRoles{{User: "alice", Roles: []string{}}}))
+		})
+
+		It("must skip unknown fields and records without a user or roles", func() {
+			buf := adminMessage(
+				adminHeader(OK, 0),
+				adminHeader(OK, 2), adminField(_PASSWORD, []byte("secret")), adminField(_USER, []byte("alice")),
+			)
+
+			list := []*UserRoles{}
+			done, err := newAdminCommand().parseUsers(buf, &list)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).To(BeFalse())
+			Expect(list).To(Equal([]*UserRoles{{User: "alice", Roles: []string{}}}))
+		})
+
+		It("must return the result code of a failed query", func() {
+			list := []*UserRoles{}
+			done, err := newAdminCommand().parseUsers(adminHeader(INVALID_USER, 0), &list)
+			Expect(done).To(BeTrue())
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(INVALID_USER))
+			Expect(list).To(BeEmpty())
+		})
+
+		It("must reject truncated fields", func() {
+			buf := adminMessage(adminHeader(OK, 1), adminField(_USER, []byte("alice")))
+
+			for _, l := range []int{_ADMIN_HEADER_SIZE, _ADMIN_HEADER_SIZE + 3, len(buf) - 1} {
+				list := []*UserRoles{}
+				done, err := newAdminCommand().parseUsers(buf[:l], &list)
+				Expect(done).To(BeTrue())
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+			}
+		})
+	})
+
+	Context("Login", func() {
+
+		// login sends a login command to a server answering with the response,
+		// and returns the request the server received
+		login := func(authMode AuthMode, response []byte) ([]byte, []byte, time.Time, error) {
+			client, server := net.Pipe()
+			defer client.Close()
+
+			requests := make(chan []byte, 1)
+			go serveAdmin(server, response, requests)
+
+			token, expiration, err := newAdminCommand().login(&Connection{conn: client}, authMode, "alice", []byte("hash"), []byte("clear"))
+
+			var request []byte
+			select {
+			case request = <-requests:
+			default:
+			}
+			return request, token, expiration, err
+		}
+
+		It("must send the credentials and return the session token and its expiration", func() {
+			request, token, expiration, err := login(AuthModeInternal, adminMessage(
+				adminHeader(OK, 2),
+				adminField(_SESSION_TOKEN, []byte("token")),
+				adminField(_SESSION_TTL, []byte{0, 0, 0x0e, 0x10}),
+			))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal([]byte("token")))
+
+			// the session is renewed before the server expires it
+			ttl := expiration.Sub(time.Now())
+			Expect(ttl).To(BeNumerically("<=", time.Hour-_SESSION_RENEWAL_MARGIN))
+			Expect(ttl).To(BeNumerically(">", time.Hour-_SESSION_RENEWAL_MARGIN-time.Minute))
+
+			header := make([]byte, _ADMIN_HEADER_SIZE)
+			header[2], header[3] = _LOGIN, 2
+			Expect(request).To(Equal(adminMessage(
+				header,
+				adminField(_USER, []byte("alice")),
+				adminField(_CREDENTIAL, []byte("hash")),
+			)))
+		})
+
+		It("must send the clear password for external authentication", func() {
+			request, token, expiration, err := login(AuthModeExternal, adminMessage(
+				adminHeader(OK, 1),
+				adminField(_SESSION_TOKEN, []byte("token")),
+			))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal([]byte("token")))
+			// sessions without a TTL do not expire
+			Expect(expiration.IsZero()).To(BeTrue())
+
+			header := make([]byte, _ADMIN_HEADER_SIZE)
+			header[2], header[3] = _LOGIN, 3
+			Expect(request).To(Equal(adminMessage(
+				header,
+				adminField(_USER, []byte("alice")),
+				adminField(_CREDENTIAL, []byte("hash")),
+				adminField(_CLEAR_PASSWORD, []byte("clear")),
+			)))
+		})
+
+		It("must accept servers without security", func() {
+			_, token, _, err := login(AuthModeInternal, adminHeader(SECURITY_NOT_ENABLED, 0))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(BeNil())
+		})
+
+		It("must return the result code of a failed login", func() {
+			_, _, _, err := login(AuthModeInternal, adminHeader(INVALID_CREDENTIAL, 0))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(INVALID_CREDENTIAL))
+		})
+
+		It("must reject responses without a session token or with truncated fields", func() {
+			_, _, _, err := login(AuthModeInternal, adminMessage(adminHeader(OK, 1), adminField(_SESSION_TTL, []byte{0, 0, 0, 60})))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+
+			response := adminMessage(adminHeader(OK, 1), adminField(_SESSION_TOKEN, []byte("token")))
+			_, _, _, err = login(AuthModeInternal, response[:len(response)-1])
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+
+			_, _, _, err = login(AuthModeInternal, adminMessage(adminHeader(OK, 2), adminField(_SESSION_TOKEN, []byte("token"))))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+		})
+	})
+})
@@ -0,0 +1,34 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// AuthMode determines how the client authenticates with the cluster
+// when ClientPolicy.User is set.
+type AuthMode int
+
+const (
+	// AuthModeInternal uses the users defined on the server.
+	// The password is sent hashed. This is the default.
+	AuthModeInternal AuthMode = iota
+
+	// AuthModeExternal uses an external authentication service, like LDAP.
+	// The password is sent in clear text as well, so TLS is required.
+	AuthModeExternal
+
+	// AuthModeExternalInsecure uses an external authentication service, like LDAP,
+	// without requiring TLS. Only use this on trusted networks, since the
+	// password is sent in clear text.
+	AuthModeExternalInsecure
+)
@@ -0,0 +1,56 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// BackpressureError is returned when a node rejects a command because it is
+// overloaded, i.e. with the DEVICE_OVERLOAD or QUERY_QUEUEFULL result codes.
+// Such commands were not applied, and should be retried only after the load on
+// the node has dropped; see BasePolicy.BackpressureBackoff.
+// The AerospikeError returned by the node is embedded, so that ResultCode()
+// reports the result code the node rejected the command with.
+type BackpressureError struct {
+	AerospikeError
+
+	node *Node
+}
+
+func newBackpressureError(node *Node, err AerospikeError) *BackpressureError {
+	return &BackpressureError{
+		AerospikeError: err,
+		node:           node,
+	}
+}
+
+// Node returns the overloaded node.
+func (be *BackpressureError) Node() *Node { return be.node }
+
+// Unwrap returns the AerospikeError which was returned by the node.
+func (be *BackpressureError) Unwrap() error { return be.AerospikeError }
+
+// isBackpressure returns the AerospikeError of errors signalling
+// an overloaded node, and false for all other errors.
+func isBackpressure(err error) (AerospikeError, bool) {
+	if ae, ok := err.(AerospikeError); ok {
+		switch ae.ResultCode() {
+		case DEVICE_OVERLOAD, QUERY_QUEUEFULL:
+			return ae, true
+		}
+	}
+	return AerospikeError{}, false
+}
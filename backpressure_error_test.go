@@ -0,0 +1,180 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// scriptedCommand is a command whose attempts fail with the scripted errors, in order.
+// Once the script is exhausted, the attempts fail with its last error.
+type scriptedCommand struct {
+	baseCommand
+
+	policy   *BasePolicy
+	node     *Node
+	errs     []error
+	attempts int
+}
+
+func (cmd *scriptedCommand) getPolicy(ifc command) Policy {
+	return cmd.policy
+}
+
+func (cmd *scriptedCommand) writeBuffer(ifc command) error {
+	cmd.dataOffset = int(_MSG_TOTAL_HEADER_SIZE)
+	for i := range cmd.dataBuffer[:cmd.dataOffset] {
+		cmd.dataBuffer[i] = 0
+	}
+	return nil
+}
+
+func (cmd *scriptedCommand) getNode(ifc command) (*Node, error) {
+	return cmd.node, nil
+}
+
+func (cmd *scriptedCommand) parseResult(ifc command, conn *Connection) error {
+	i := cmd.attempts
+	if i >= len(cmd.errs) {
+		i = len(cmd.errs) - 1
+	}
+	cmd.attempts++
+	return cmd.errs[i]
+}
+
+func (cmd *scriptedCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
+	return false, nil
+}
+
+func (cmd *scriptedCommand) Execute() error {
+	return cmd.execute(cmd)
+}
+
+// newScriptedNode returns a node whose connections discard the commands sent to them.
+func newScriptedNode() *Node {
+	cluster := &Cluster{
+		connectionTimeout: time.Second,
+		dialer: func(ctx context.Context, network, address string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go io.Copy(ioutil.Discard, server)
+			return client, nil
+		},
+	}
+
+	return &Node{
+		cluster:     cluster,
+		name:        "BB9000000000000",
+		host:        NewHost("127.0.0.1", 3000),
+		connections: NewAtomicQueue(4),
+		health:      NewAtomicInt(_FULL_HEALTH),
+		stats:       newNodeStats(),
+		active:      NewAtomicBool(true),
+	}
+}
+
+var _ = Describe("Backpressure Test", func() {
+
+	overloadErr := NewAerospikeError(DEVICE_OVERLOAD)
+
+	It("must only detect the result codes of overloaded nodes", func() {
+		ae, ok := isBackpressure(overloadErr)
+		Expect(ok).To(BeTrue())
+		Expect(ae).To(Equal(overloadErr))
+
+		ae, ok = isBackpressure(NewAerospikeError(QUERY_QUEUEFULL))
+		Expect(ok).To(BeTrue())
+		Expect(ae.ResultCode()).To(Equal(QUERY_QUEUEFULL))
+
+		_, ok = isBackpressure(NewAerospikeError(TIMEOUT))
+		Expect(ok).To(BeFalse())
+
+		_, ok = isBackpressure(errors.New("device overload"))
+		Expect(ok).To(BeFalse())
+
+		_, ok = isBackpressure(nil)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("must not retry overloaded nodes without a backoff", func() {
+		policy := NewPolicy()
+		cmd := &scriptedCommand{policy: policy, node: newScriptedNode(), errs: []error{overloadErr}}
+
+		err := cmd.Execute()
+		Expect(cmd.attempts).To(Equal(1))
+		bpErr, ok := err.(*BackpressureError)
+		Expect(ok).To(BeTrue())
+		Expect(bpErr.ResultCode()).To(Equal(DEVICE_OVERLOAD))
+		Expect(bpErr.Node()).To(BeIdenticalTo(cmd.node))
+
+		// the error returned by the node is still available
+		Expect(bpErr.AerospikeError).To(Equal(overloadErr))
+		Expect(bpErr.Error()).To(Equal(overloadErr.Error()))
+		Expect(IsResultCode(err, DEVICE_OVERLOAD)).To(BeTrue())
+		Expect(IsInDoubt(err)).To(BeFalse())
+		Expect(isNetworkError(err)).To(BeFalse())
+	})
+
+	It("must back off before retrying overloaded nodes", func() {
+		policy := NewPolicy()
+		policy.SleepBetweenRetries = 0
+		policy.BackpressureBackoff = 50 * time.Millisecond
+		cmd := &scriptedCommand{policy: policy, node: newScriptedNode(), errs: []error{overloadErr, nil}}
+
+		start := time.Now()
+		Expect(cmd.Execute()).ToNot(HaveOccurred())
+		Expect(cmd.attempts).To(Equal(2))
+		Expect(time.Since(start)).To(BeNumerically(">=", policy.BackpressureBackoff))
+	})
+
+	It("must return the backpressure error once the retries are exhausted", func() {
+		policy := NewPolicy()
+		policy.SleepBetweenRetries = 0
+		policy.BackpressureBackoff = time.Millisecond
+		cmd := &scriptedCommand{policy: policy, node: newScriptedNode(), errs: []error{overloadErr}}
+
+		err := cmd.Execute()
+		Expect(cmd.attempts).To(Equal(policy.MaxRetries + 1))
+		_, ok := err.(*BackpressureError)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("must return the error of the last attempt if it was not a backpressure error", func() {
+		serverErr := NewAerospikeError(SERVER_ERROR)
+
+		// retry until the command times out
+		policy := NewPolicy()
+		policy.Timeout = 20 * time.Millisecond
+		policy.SleepBetweenRetries = time.Millisecond
+		policy.ShouldRetry = func(iteration int, err error) bool {
+			return true
+		}
+		cmd := &scriptedCommand{policy: policy, node: newScriptedNode(), errs: []error{overloadErr, serverErr}}
+
+		Expect(cmd.Execute()).To(Equal(serverErr))
+		Expect(cmd.attempts).To(BeNumerically(">", 1))
+	})
+
+})
@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"fmt"
 
 	. "github.com/aerospike/aerospike-client-go/types"
@@ -142,3 +143,11 @@ func (cmd *baseMultiCommand) Stop() {
 func (cmd *baseMultiCommand) IsValid() bool {
 	return cmd.valid.Get()
 }
+
+// reportTotalTimeout sends a timeout error on the Errors channel if the command
+// failed because the total timeout of its scan or query has elapsed.
+func (cmd *baseMultiCommand) reportTotalTimeout(err error) {
+	if err != nil && cmd.ctx != nil && cmd.ctx.Err() == context.DeadlineExceeded {
+		cmd.Errors <- newNodeError(cmd.node, NewAerospikeError(TIMEOUT, "Total timeout of the scan or query has elapsed."))
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/logger"
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+type batchCommandDelete struct {
+	*baseMultiCommand
+
+	batchNamespace *batchNamespace
+	policy         Policy
+	// positions of each key digest in the records array
+	positions map[string][]int
+	records   []*BatchRecord
+}
+
+func newBatchCommandDelete(
+	node *Node,
+	batchNamespace *batchNamespace,
+	policy Policy,
+	positions map[string][]int,
+	records []*BatchRecord,
+) *batchCommandDelete {
+	return &batchCommandDelete{
+		baseMultiCommand: newMultiCommand(node, nil, nil),
+		batchNamespace:   batchNamespace,
+		policy:           policy,
+		positions:        positions,
+		records:          records,
+	}
+}
+
+func (cmd *batchCommandDelete) getPolicy(ifc command) Policy {
+	return cmd.policy
+}
+
+func (cmd *batchCommandDelete) writeBuffer(ifc command) error {
+	return cmd.setBatchDelete(cmd.batchNamespace)
+}
+
+// Parse all results in the batch. Results are matched to their keys
+// by the batch index sent back by the server. Unlike batch reads,
+// errors are recorded per key and do not abort the batch.
+func (cmd *batchCommandDelete) parseRecordResults(ifc command, receiveSize int) (bool, error) {
+	cmd.dataOffset = 0
+
+	for cmd.dataOffset < receiveSize {
+		if err := cmd.readBytes(int(_MSG_REMAINING_HEADER_SIZE)); err != nil {
+			return false, err
+		}
+		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
+		info3 := int(cmd.dataBuffer[3])
+
+		// If cmd is the end marker of the response, do not proceed further
+		if (info3 & _INFO3_LAST) == _INFO3_LAST {
+			if resultCode != 0 {
+				return false, NewAerospikeError(resultCode)
+			}
+			return false, nil
+		}
+
+		batchIndex := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 14)))
+		fieldCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 18)))
+		opCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 20)))
+
+		// key fields are not needed; the batch index identifies the key
+		if _, err := cmd.parseKey(fieldCount); err != nil {
+			return false, err
+		}
+
+		// deletes do not return bins; skip them just in case
+		for i := 0; i < opCount; i++ {
+			if err := cmd.readBytes(4); err != nil {
+				return false, err
+			}
+			opSize := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 0)))
+			if err := cmd.readBytes(opSize); err != nil {
+				return false, err
+			}
+		}
+
+		if batchIndex >= len(cmd.batchNamespace.keys) {
+			Logger.Debug("Unexpected batch index returned: %d", batchIndex)
+			continue
+		}
+		key := cmd.batchNamespace.keys[batchIndex]
+
+		for _, index := range cmd.positions[string(key.digest)] {
+			cmd.records[index] = newBatchRecord(key, resultCode)
+		}
+	}
+	return true, nil
+}
+
+func (cmd *batchCommandDelete) Execute() error {
+	return cmd.execute(cmd)
+}
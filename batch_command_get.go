@@ -84,7 +84,7 @@ func (cmd *batchCommandGet) parseRecordResults(ifc command, receiveSize int) (bo
 		}
 
 		generation := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 6)))
-		expiration := TTL(int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 10))))
+		expiration := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 10)))
 		fieldCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 18)))
 		opCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 20)))
 		key, err := cmd.parseKey(fieldCount)
@@ -197,3 +197,17 @@ func (cmd *batchCommandGet) parseRecord(key *Key, opCount int, generation int, e
 func (cmd *batchCommandGet) Execute() error {
 	return cmd.execute(cmd)
 }
+
+// batchCommandGetStream reports the completion of the
+// batch request of a node as soon as the node responds.
+type batchCommandGetStream struct {
+	*batchCommandGet
+
+	onComplete func(err error)
+}
+
+func (cmd *batchCommandGetStream) Execute() error {
+	err := cmd.batchCommandGet.Execute()
+	cmd.onComplete(err)
+	return err
+}
@@ -0,0 +1,108 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/logger"
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+type batchCommandOperate struct {
+	*batchCommandGet
+
+	operations []*Operation
+	// positions of each key digest in the records array
+	positions map[string][]int
+}
+
+func newBatchCommandOperate(
+	node *Node,
+	batchNamespace *batchNamespace,
+	policy Policy,
+	positions map[string][]int,
+	operations []*Operation,
+	records []*Record,
+	readAttr int,
+) *batchCommandOperate {
+	return &batchCommandOperate{
+		batchCommandGet: newBatchCommandGet(node, batchNamespace, policy, nil, nil, records, readAttr),
+		operations:      operations,
+		positions:       positions,
+	}
+}
+
+func (cmd *batchCommandOperate) writeBuffer(ifc command) error {
+	return cmd.setBatchIndexRead(cmd.batchNamespace, cmd.operations, cmd.readAttr)
+}
+
+// Parse all results in the batch. Records are matched to their keys
+// by the batch index sent back by the server.
+func (cmd *batchCommandOperate) parseRecordResults(ifc command, receiveSize int) (bool, error) {
+	cmd.dataOffset = 0
+
+	for cmd.dataOffset < receiveSize {
+		if err := cmd.readBytes(int(_MSG_REMAINING_HEADER_SIZE)); err != nil {
+			return false, err
+		}
+		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
+
+		// The only valid server return codes are "ok" and "not found".
+		// If other return codes are received, then abort the batch.
+		if resultCode != 0 && resultCode != KEY_NOT_FOUND_ERROR {
+			return false, NewAerospikeError(resultCode)
+		}
+
+		info3 := int(cmd.dataBuffer[3])
+
+		// If cmd is the end marker of the response, do not proceed further
+		if (info3 & _INFO3_LAST) == _INFO3_LAST {
+			return false, nil
+		}
+
+		generation := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 6)))
+		expiration := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 10)))
+		batchIndex := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 14)))
+		fieldCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 18)))
+		opCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 20)))
+
+		// key fields are not needed; the batch index identifies the key
+		if _, err := cmd.parseKey(fieldCount); err != nil {
+			return false, err
+		}
+
+		if batchIndex >= len(cmd.batchNamespace.keys) {
+			Logger.Debug("Unexpected batch index returned: %d", batchIndex)
+			continue
+		}
+		key := cmd.batchNamespace.keys[batchIndex]
+
+		record, err := cmd.parseRecord(key, opCount, generation, expiration)
+		if err != nil {
+			return false, err
+		}
+
+		if resultCode == 0 {
+			for _, index := range cmd.positions[string(key.digest)] {
+				cmd.records[index] = record
+			}
+		}
+	}
+	return true, nil
+}
+
+func (cmd *batchCommandOperate) Execute() error {
+	return cmd.execute(cmd)
+}
@@ -37,7 +37,7 @@ func newBatchNodeList(cluster *Cluster, keys []*Key) ([]*batchNode, error) {
 	// Split keys by server node.
 	batchNodes := make([]*batchNode, 0, nodeCount+1)
 
-	for _, key := range keys {
+	for i, key := range keys {
 		partition := NewPartitionByKey(key)
 
 		// error not required
@@ -45,29 +45,30 @@ func newBatchNodeList(cluster *Cluster, keys []*Key) ([]*batchNode, error) {
 		batchNode := findBatchNode(batchNodes, node)
 
 		if batchNode == nil {
-			batchNodes = append(batchNodes, newBatchNode(node, keysPerNode, key))
+			batchNodes = append(batchNodes, newBatchNode(node, keysPerNode, key, i))
 		} else {
-			batchNode.AddKey(key)
+			batchNode.AddKey(key, i)
 		}
 	}
 	return batchNodes, nil
 }
 
-func newBatchNode(node *Node, keyCapacity int, key *Key) *batchNode {
+func newBatchNode(node *Node, keyCapacity int, key *Key, offset int) *batchNode {
 	return &batchNode{
 		Node:            node,
 		KeyCapacity:     keyCapacity,
-		BatchNamespaces: []*batchNamespace{newBatchNamespace(&key.namespace, keyCapacity, key)},
+		BatchNamespaces: []*batchNamespace{newBatchNamespace(&key.namespace, keyCapacity, key, offset)},
 	}
 }
 
-func (bn *batchNode) AddKey(key *Key) {
+func (bn *batchNode) AddKey(key *Key, offset int) {
 	batchNamespace := bn.findNamespace(&key.namespace)
 
 	if batchNamespace == nil {
-		bn.BatchNamespaces = append(bn.BatchNamespaces, newBatchNamespace(&key.namespace, bn.KeyCapacity, key))
+		bn.BatchNamespaces = append(bn.BatchNamespaces, newBatchNamespace(&key.namespace, bn.KeyCapacity, key, offset))
 	} else {
 		batchNamespace.keys = append(batchNamespace.keys, key)
+		batchNamespace.offsets = append(batchNamespace.offsets, offset)
 	}
 }
 
@@ -94,11 +95,14 @@ func findBatchNode(nodes []*batchNode, node *Node) *batchNode {
 type batchNamespace struct {
 	namespace *string
 	keys      []*Key
+	// positions of the keys in the original keys array
+	offsets []int
 }
 
-func newBatchNamespace(namespace *string, capacity int, key *Key) *batchNamespace {
+func newBatchNamespace(namespace *string, capacity int, key *Key, offset int) *batchNamespace {
 	return &batchNamespace{
 		namespace: namespace,
 		keys:      []*Key{key},
+		offsets:   []int{offset},
 	}
 }
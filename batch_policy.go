@@ -0,0 +1,33 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// BatchPolicy encapsulates parameters for batch commands.
+type BatchPolicy struct {
+	*BasePolicy
+
+	// MaxConcurrentNodes determines the maximum number of node requests of a batch
+	// which are issued in parallel. The requests of the other nodes wait until
+	// one of them completes.
+	// Zero issues the requests to all nodes in parallel; 1 issues them sequentially.
+	MaxConcurrentNodes int //= 0
+}
+
+// NewBatchPolicy generates a new BatchPolicy with default values.
+func NewBatchPolicy() *BatchPolicy {
+	return &BatchPolicy{
+		BasePolicy: NewPolicy(),
+	}
+}
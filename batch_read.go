@@ -0,0 +1,135 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// BatchRead specifies the key and the bins or operations
+// to read for a single record in BatchGetComplex.
+type BatchRead struct {
+	// Key is the key of the record to read.
+	Key *Key
+
+	// BinNames are the bins to read. Ignored if Ops is set.
+	BinNames []string
+
+	// ReadAllBins reads all bins of the record. Ignored if BinNames or Ops is set.
+	// If BinNames and Ops are empty and ReadAllBins is false,
+	// only the record header is read.
+	ReadAllBins bool
+
+	// Ops are the read operations to apply to the record.
+	Ops []*Operation
+
+	// Record is set by BatchGetComplex to the record read.
+	// It is nil if the record does not exist.
+	Record *Record
+}
+
+// NewBatchRead creates a BatchRead which reads the specified bins of the key.
+// If no bins are specified, all bins are read.
+func NewBatchRead(key *Key, binNames ...string) *BatchRead {
+	return &BatchRead{
+		Key:         key,
+		BinNames:    binNames,
+		ReadAllBins: len(binNames) == 0,
+	}
+}
+
+// NewBatchReadHeader creates a BatchRead which only reads the record header of the key.
+func NewBatchReadHeader(key *Key) *BatchRead {
+	return &BatchRead{
+		Key: key,
+	}
+}
+
+// NewBatchReadOps creates a BatchRead which applies the read operations to the key.
+func NewBatchReadOps(key *Key, ops ...*Operation) *BatchRead {
+	return &BatchRead{
+		Key: key,
+		Ops: ops,
+	}
+}
+
+// readAttr returns the read attributes of the request.
+func (br *BatchRead) readAttr() int {
+	readAttr := _INFO1_READ
+
+	switch {
+	case len(br.Ops) > 0:
+		for _, op := range br.Ops {
+			// Read all bins if no bin is specified.
+			if op.OpType == READ && op.BinName == nil {
+				readAttr |= _INFO1_GET_ALL
+			}
+		}
+	case len(br.BinNames) > 0:
+	case br.ReadAllBins:
+		readAttr |= _INFO1_GET_ALL
+	default:
+		readAttr |= _INFO1_NOBINDATA
+	}
+	return readAttr
+}
+
+// validate checks that only read operations were requested.
+func (br *BatchRead) validate() error {
+	if br.Key == nil {
+		return NewAerospikeError(PARAMETER_ERROR, "BatchRead key is nil.")
+	}
+
+	for _, op := range br.Ops {
+		switch op.OpType {
+		case READ, CDT_READ, BIT_READ, HLL_READ:
+		default:
+			return NewAerospikeError(PARAMETER_ERROR, "BatchGetComplex only supports read operations.")
+		}
+	}
+	return nil
+}
+
+// sameRead returns true if the request reads the same namespace, set,
+// bins and operations as the other request, so the server can repeat it.
+func (br *BatchRead) sameRead(other *BatchRead) bool {
+	if br.Key.namespace != other.Key.namespace || br.Key.setName != other.Key.setName {
+		return false
+	}
+
+	if br.ReadAllBins != other.ReadAllBins || len(br.BinNames) != len(other.BinNames) || len(br.Ops) != len(other.Ops) {
+		return false
+	}
+
+	for i := range br.BinNames {
+		if br.BinNames[i] != other.BinNames[i] {
+			return false
+		}
+	}
+
+	for i := range br.Ops {
+		if br.Ops[i] != other.Ops[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the Stringer interface.
+func (br *BatchRead) String() string {
+	return fmt.Sprintf("%v: %v", br.Key, br.Record)
+}
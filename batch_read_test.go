@@ -0,0 +1,90 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BatchRead Test", func() {
+
+	It("must encode the reads of a batch in the batch index format of 3.6 servers", func() {
+		ns := "test"
+		records := make([]*BatchRead, 3)
+		for i := range records {
+			key, err := NewKey(ns, "", i)
+			Expect(err).ToNot(HaveOccurred())
+			records[i] = NewBatchRead(key, "a")
+		}
+		// the second key repeats the read of the first one, but not the third
+		records[2].BinNames = []string{"b"}
+
+		batchNamespace := &batchNamespace{namespace: &ns, offsets: []int{0, 1, 2}}
+		for _, record := range records {
+			batchNamespace.keys = append(batchNamespace.keys, record.Key)
+		}
+
+		cmd := &baseCommand{}
+		Expect(cmd.setBatchRead(batchNamespace, records)).ToNot(HaveOccurred())
+		buf := cmd.dataBuffer[:cmd.dataOffset]
+
+		// field header, key count and inline flag
+		offset := int(_MSG_TOTAL_HEADER_SIZE)
+		Expect(Buffer.BytesToInt32(buf, offset)).To(Equal(int32(len(buf) - offset - 4)))
+		Expect(buf[offset+4]).To(Equal(byte(BATCH_INDEX)))
+		Expect(Buffer.BytesToInt32(buf, offset+5)).To(Equal(int32(3)))
+		Expect(buf[offset+9]).To(Equal(byte(1)))
+		offset += 10
+
+		// the read of a key is followed by the read attributes, the field and
+		// operation counts, the namespace and the bins; or a repeat flag
+		readKey := func(index int, repeat bool, binName string) {
+			Expect(Buffer.BytesToInt32(buf, offset)).To(Equal(int32(index)))
+			Expect(buf[offset+4 : offset+24]).To(Equal(records[index].Key.Digest()))
+			offset += 24
+
+			if repeat {
+				Expect(buf[offset]).To(Equal(byte(1)))
+				offset++
+				return
+			}
+
+			Expect(buf[offset]).To(Equal(byte(0)))
+			Expect(buf[offset+1]).To(Equal(byte(_INFO1_READ)))
+			Expect(Buffer.BytesToInt16(buf, offset+2)).To(Equal(int16(1)))
+			Expect(Buffer.BytesToInt16(buf, offset+4)).To(Equal(int16(1)))
+			offset += 6
+
+			// namespace field
+			Expect(Buffer.BytesToInt32(buf, offset)).To(Equal(int32(1 + len(ns))))
+			Expect(string(buf[offset+5 : offset+5+len(ns)])).To(Equal(ns))
+			offset += 5 + len(ns)
+
+			// bin read operation
+			Expect(Buffer.BytesToInt32(buf, offset)).To(Equal(int32(4 + len(binName))))
+			Expect(string(buf[offset+8 : offset+8+len(binName)])).To(Equal(binName))
+			offset += 8 + len(binName)
+		}
+
+		readKey(0, false, "a")
+		readKey(1, true, "a")
+		readKey(2, false, "b")
+		Expect(offset).To(Equal(len(buf)))
+	})
+
+})
@@ -0,0 +1,82 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// BatchRecord holds the result of a batch command for a single key.
+type BatchRecord struct {
+	// Key is the key the command was applied to.
+	Key *Key
+
+	// Record is the record read for the key by BatchGetChannel.
+	// It is nil if the record does not exist, or the command failed.
+	Record *Record
+
+	// ResultCode is the result of the command for the key.
+	// For deletes, OK means the record was found and deleted, and
+	// KEY_NOT_FOUND_ERROR means the record did not exist.
+	ResultCode ResultCode
+
+	// Err is set if the command failed for the key, e.g. because
+	// the node holding the key could not be reached.
+	Err error
+
+	// InDoubt is true if the command may have been applied on the
+	// server, even though it failed.
+	InDoubt bool
+}
+
+func newBatchRecord(key *Key, resultCode ResultCode) *BatchRecord {
+	res := &BatchRecord{
+		Key:        key,
+		ResultCode: resultCode,
+	}
+
+	if resultCode != OK && resultCode != KEY_NOT_FOUND_ERROR {
+		res.Err = NewAerospikeError(resultCode)
+	}
+	return res
+}
+
+// newBatchRecordError returns the result of a key whose command
+// failed before the server returned a result for it.
+func newBatchRecordError(key *Key, err error) *BatchRecord {
+	res := &BatchRecord{
+		Key:        key,
+		ResultCode: SERVER_ERROR,
+		Err:        err,
+	}
+
+	var ae AerospikeError
+	if errors.As(err, &ae) {
+		res.ResultCode = ae.ResultCode()
+	}
+	res.InDoubt = isNetworkError(err)
+	return res
+}
+
+// String implements the Stringer interface.
+func (br *BatchRecord) String() string {
+	if br.Err != nil {
+		return fmt.Sprintf("%v: %v", br.Key, br.Err)
+	}
+	return fmt.Sprintf("%v: %s", br.Key, ResultCodeToString(br.ResultCode))
+}
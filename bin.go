@@ -14,12 +14,24 @@
 
 package aerospike
 
+import (
+	"strconv"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// _MAX_BIN_NAME_SIZE is the maximum length of bin names, in bytes.
+const _MAX_BIN_NAME_SIZE = 15
+
 // BinMap is used to define a map of bin names to values.
+// Values are converted with NewValue. Bins set to nil,
+// or to a nil pointer, are removed from the record on write.
 type BinMap map[string]interface{}
 
 // Bin encapsulates a field name/value pair.
 type Bin struct {
-	// Bin name. Current limit is 14 characters.
+	// Bin name. Current limit is 15 bytes; longer names are rejected
+	// by the client unless ClientPolicy.DisableBinNameValidation is set.
 	Name string
 
 	// Bin value.
@@ -43,6 +55,15 @@ func binMapToBins(bins BinMap) []*Bin {
 	return binList
 }
 
+// validateBinName returns a BIN_NAME_TOO_LONG error if the name is longer
+// than the server accepts.
+func validateBinName(name string) error {
+	if len(name) > _MAX_BIN_NAME_SIZE {
+		return NewAerospikeError(BIN_NAME_TOO_LONG, "Bin name '"+name+"' exceeds "+strconv.Itoa(_MAX_BIN_NAME_SIZE)+" bytes")
+	}
+	return nil
+}
+
 // String implements Stringer interface.
 func (bn *Bin) String() string {
 	return bn.Name + ":" + bn.Value.String()
@@ -0,0 +1,72 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// Bit operation codes.
+const (
+	_BIT_SET    = 3
+	_BIT_OR     = 4
+	_BIT_AND    = 6
+	_BIT_LSHIFT = 8
+	_BIT_GET    = 50
+	_BIT_COUNT  = 51
+)
+
+// Bit operations address the bits of a blob bin by bitOffset, the offset of
+// the first bit from the beginning of the blob, and bitSize, the number of bits.
+// Negative offsets are counted from the end of the blob.
+// Bit operations are supported by Aerospike 4.6 servers and later.
+
+// BitSetOp creates an operation which sets the bitSize bits at bitOffset
+// to the first bitSize bits of value.
+// The operation does not return a result.
+func BitSetOp(binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return &Operation{OpType: BIT_MODIFY, BinName: &binName, BinValue: newPackedOpValue(_BIT_SET, NewIntegerValue(bitOffset), NewIntegerValue(bitSize), NewBytesValue(value), NewIntegerValue(0))}
+}
+
+// BitOrOp creates an operation which sets the bitSize bits at bitOffset
+// to their bitwise or with the first bitSize bits of value.
+// The operation does not return a result.
+func BitOrOp(binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return &Operation{OpType: BIT_MODIFY, BinName: &binName, BinValue: newPackedOpValue(_BIT_OR, NewIntegerValue(bitOffset), NewIntegerValue(bitSize), NewBytesValue(value), NewIntegerValue(0))}
+}
+
+// BitAndOp creates an operation which sets the bitSize bits at bitOffset
+// to their bitwise and with the first bitSize bits of value.
+// The operation does not return a result.
+func BitAndOp(binName string, bitOffset int, bitSize int, value []byte) *Operation {
+	return &Operation{OpType: BIT_MODIFY, BinName: &binName, BinValue: newPackedOpValue(_BIT_AND, NewIntegerValue(bitOffset), NewIntegerValue(bitSize), NewBytesValue(value), NewIntegerValue(0))}
+}
+
+// BitLShiftOp creates an operation which shifts the bitSize bits at bitOffset
+// left by shift bits. Bits shifted out of the range are dropped,
+// and zeros are shifted in.
+// The operation does not return a result.
+func BitLShiftOp(binName string, bitOffset int, bitSize int, shift int) *Operation {
+	return &Operation{OpType: BIT_MODIFY, BinName: &binName, BinValue: newPackedOpValue(_BIT_LSHIFT, NewIntegerValue(bitOffset), NewIntegerValue(bitSize), NewIntegerValue(shift), NewIntegerValue(0))}
+}
+
+// BitGetOp creates an operation which returns the bitSize bits at bitOffset,
+// as a []byte. The last byte is padded with zeros when bitSize is not
+// a multiple of 8.
+func BitGetOp(binName string, bitOffset int, bitSize int) *Operation {
+	return &Operation{OpType: BIT_READ, BinName: &binName, BinValue: newPackedOpValue(_BIT_GET, NewIntegerValue(bitOffset), NewIntegerValue(bitSize))}
+}
+
+// BitCountOp creates an operation which returns the number of bits
+// set to 1 in the bitSize bits at bitOffset, as an int.
+func BitCountOp(binName string, bitOffset int, bitSize int) *Operation {
+	return &Operation{OpType: BIT_READ, BinName: &binName, BinValue: newPackedOpValue(_BIT_COUNT, NewIntegerValue(bitOffset), NewIntegerValue(bitSize))}
+}
@@ -0,0 +1,255 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
+)
+
+// CDT list operation codes.
+const (
+	_CDT_LIST_APPEND                = 1
+	_CDT_LIST_SORT                  = 13
+	_CDT_LIST_SIZE                  = 16
+	_CDT_LIST_GET_RANGE             = 18
+	_CDT_LIST_GET_BY_RANK           = 21
+	_CDT_LIST_GET_BY_VALUE          = 22
+	_CDT_LIST_GET_BY_VALUE_INTERVAL = 25
+)
+
+// CDT map operation codes.
+const (
+	_CDT_MAP_SET_TYPE            = 64
+	_CDT_MAP_PUT                 = 67
+	_CDT_MAP_GET_BY_KEY          = 97
+	_CDT_MAP_GET_BY_KEY_INTERVAL = 103
+	_CDT_MAP_GET_BY_INDEX_RANGE  = 104
+	_CDT_MAP_GET_BY_KEY_LIST     = 107
+)
+
+const (
+	// map is stored unordered on the server.
+	_CDT_MAP_UNORDERED = 0
+)
+
+// cdtOpValue encodes a collection data type operation
+// with its arguments into the wire protocol.
+type cdtOpValue struct {
+	opCode int16
+	args   []Value
+	bytes  []byte
+
+	// the result is a map which must be returned as ordered key/value pairs
+	keyValuePairs bool
+
+	// the result is an integer which must be returned as an int64
+	int64Result bool
+}
+
+func newCDTOpValue(opCode int16, args ...Value) *cdtOpValue {
+	res := &cdtOpValue{
+		opCode: opCode,
+		args:   args,
+	}
+
+	packer := newPacker()
+	packer.PackRawShort(opCode)
+	if len(args) > 0 {
+		if err := packer.packValueArray(args); err != nil {
+			panic(err)
+		}
+	}
+	res.bytes = packer.buffer.Bytes()
+
+	return res
+}
+
+// newPackedOpValue encodes an operation whose op code is sent as the first
+// item of the packed argument list, like the HLL and bit operations.
+func newPackedOpValue(opCode int, args ...Value) *cdtOpValue {
+	res := &cdtOpValue{
+		opCode: int16(opCode),
+		args:   args,
+	}
+
+	packer := newPacker()
+	if err := packer.packValueArray(append([]Value{NewIntegerValue(opCode)}, args...)); err != nil {
+		panic(err)
+	}
+	res.bytes = packer.buffer.Bytes()
+
+	return res
+}
+
+func (vl *cdtOpValue) estimateSize() int {
+	return len(vl.bytes)
+}
+
+func (vl *cdtOpValue) write(buffer []byte, offset int) (int, error) {
+	l := copy(buffer[offset:], vl.bytes)
+	return l, nil
+}
+
+func (vl *cdtOpValue) pack(packer *packer) error {
+	_, err := packer.buffer.Write(vl.bytes)
+	return err
+}
+
+// GetType returns wire protocol value type.
+func (vl *cdtOpValue) GetType() int {
+	return ParticleType.BLOB
+}
+
+// GetObject returns original value as an interface{}.
+func (vl *cdtOpValue) GetObject() interface{} {
+	return vl.args
+}
+
+func (vl *cdtOpValue) reader() io.Reader {
+	return bytes.NewReader(vl.bytes)
+}
+
+// String implements Stringer interface.
+func (vl *cdtOpValue) String() string {
+	return fmt.Sprintf("cdt op %d %v", vl.opCode, vl.args)
+}
+
+// ListAppendOp creates a list append operation.
+// The value is appended to the end of the list in the bin.
+// If the bin does not exist, a new list is created.
+func ListAppendOp(binName string, value interface{}) *Operation {
+	return &Operation{OpType: CDT_MODIFY, BinName: &binName, BinValue: newCDTOpValue(_CDT_LIST_APPEND, NewValue(value))}
+}
+
+// ListGetRangeOp creates a list get range operation.
+// It returns count items starting at the specified index of the list in the bin.
+func ListGetRangeOp(binName string, index int, count int) *Operation {
+	return &Operation{OpType: CDT_READ, BinName: &binName, BinValue: newCDTOpValue(_CDT_LIST_GET_RANGE, NewIntegerValue(index), NewIntegerValue(count))}
+}
+
+// ListSizeOp creates a list size operation.
+// It returns the number of items of the list in the bin.
+func ListSizeOp(binName string) *Operation {
+	return &Operation{OpType: CDT_READ, BinName: &binName, BinValue: newCDTOpValue(_CDT_LIST_SIZE)}
+}
+
+// ListSortOp creates a list sort operation.
+// The list in the bin is sorted in place, as determined by sortFlags.
+// The operation does not return a result; combine it with ListSizeOp
+// in the same Operate call to get the size of the resulting list.
+func ListSortOp(binName string, sortFlags ListSortFlags) *Operation {
+	return &Operation{OpType: CDT_MODIFY, BinName: &binName, BinValue: newCDTOpValue(_CDT_LIST_SORT, NewIntegerValue(int(sortFlags)))}
+}
+
+// ListRemoveDuplicatesOp creates an operation which removes the duplicate values
+// of the list in the bin. The list is sorted in ascending order as well.
+// The operation does not return a result; combine it with ListSizeOp
+// in the same Operate call to get the size of the resulting list.
+func ListRemoveDuplicatesOp(binName string) *Operation {
+	return ListSortOp(binName, LIST_SORT_DROP_DUPLICATES)
+}
+
+// ListGetByRankOp creates a list get by rank operation.
+// It selects the item with the specified rank, i.e. the position of its value in
+// the sorted list, from the list in the bin, and returns the data specified by
+// returnType. Rank 0 is the lowest value, and rank -1 the highest.
+func ListGetByRankOp(binName string, rank int, returnType ListReturnType) *Operation {
+	return newListReadOp(binName, _CDT_LIST_GET_BY_RANK, returnType, NewIntegerValue(rank))
+}
+
+// ListGetByValueOp creates a list get by value operation.
+// It selects the items equal to value from the list in the bin,
+// and returns the data specified by returnType.
+func ListGetByValueOp(binName string, value interface{}, returnType ListReturnType) *Operation {
+	return newListReadOp(binName, _CDT_LIST_GET_BY_VALUE, returnType, NewValue(value))
+}
+
+// ListGetByValueRangeOp creates a list get by value range operation.
+// It selects the items with values from valueBegin (inclusive) to valueEnd (exclusive)
+// from the list in the bin, and returns the data specified by returnType.
+// If valueEnd is nil, the range is extended to the highest value.
+func ListGetByValueRangeOp(binName string, valueBegin interface{}, valueEnd interface{}, returnType ListReturnType) *Operation {
+	if valueEnd == nil {
+		return newListReadOp(binName, _CDT_LIST_GET_BY_VALUE_INTERVAL, returnType, NewValue(valueBegin))
+	}
+	return newListReadOp(binName, _CDT_LIST_GET_BY_VALUE_INTERVAL, returnType, NewValue(valueBegin), NewValue(valueEnd))
+}
+
+func newListReadOp(binName string, opCode int16, returnType ListReturnType, args ...Value) *Operation {
+	return &Operation{OpType: CDT_READ, BinName: &binName, BinValue: newCDTOpValue(opCode, append([]Value{NewIntegerValue(int(returnType))}, args...)...)}
+}
+
+// MapPutOp creates a map put operation.
+// The key/value item is written to the map in the bin.
+// If the bin does not exist, a new map is created.
+func MapPutOp(binName string, key interface{}, value interface{}) *Operation {
+	return &Operation{OpType: CDT_MODIFY, BinName: &binName, BinValue: newCDTOpValue(_CDT_MAP_PUT, NewValue(key), NewValue(value), NewIntegerValue(_CDT_MAP_UNORDERED))}
+}
+
+// MapGetByKeyOp creates a map get by key operation.
+// It selects the item with the specified key from the map in the bin,
+// and returns the data specified by returnType.
+func MapGetByKeyOp(binName string, key interface{}, returnType MapReturnType) *Operation {
+	return newMapReadOp(binName, _CDT_MAP_GET_BY_KEY, returnType, NewValue(key))
+}
+
+// MapGetByKeyRangeOp creates a map get by key range operation.
+// It selects the items with keys from keyBegin (inclusive) to keyEnd (exclusive)
+// from the map in the bin, and returns the data specified by returnType.
+// If keyBegin is nil, the range starts at the first key; if keyEnd is nil,
+// the range is extended to the last key.
+func MapGetByKeyRangeOp(binName string, keyBegin interface{}, keyEnd interface{}, returnType MapReturnType) *Operation {
+	if keyEnd == nil {
+		return newMapReadOp(binName, _CDT_MAP_GET_BY_KEY_INTERVAL, returnType, NewValue(keyBegin))
+	}
+	return newMapReadOp(binName, _CDT_MAP_GET_BY_KEY_INTERVAL, returnType, NewValue(keyBegin), NewValue(keyEnd))
+}
+
+// MapGetByKeyListOp creates a map get by key list operation.
+// It selects the items with the specified keys from the map in the bin,
+// and returns the data specified by returnType. Keys which are not in the
+// map are ignored.
+func MapGetByKeyListOp(binName string, keys []Value, returnType MapReturnType) *Operation {
+	return newMapReadOp(binName, _CDT_MAP_GET_BY_KEY_LIST, returnType, NewValueArray(keys))
+}
+
+// MapSetOrderOp creates a map set order operation.
+// It changes the order the map in the bin is stored with on the server.
+// Key ordered maps can be read in order with MapGetByIndexRangeOp.
+func MapSetOrderOp(binName string, order MapOrder) *Operation {
+	return &Operation{OpType: CDT_MODIFY, BinName: &binName, BinValue: newCDTOpValue(_CDT_MAP_SET_TYPE, NewIntegerValue(int(order)))}
+}
+
+// MapGetByIndexRangeOp creates a map get by index range operation.
+// It selects count items starting at index from the map in the bin, in the order
+// of the map, and returns the data specified by returnType. If count is zero or
+// negative, all items from index to the end of the map are selected.
+// Use MAP_RETURN_KEY_VALUE to get the items of a key ordered map as an ordered []MapPair.
+func MapGetByIndexRangeOp(binName string, index int, count int, returnType MapReturnType) *Operation {
+	if count <= 0 {
+		return newMapReadOp(binName, _CDT_MAP_GET_BY_INDEX_RANGE, returnType, NewIntegerValue(index))
+	}
+	return newMapReadOp(binName, _CDT_MAP_GET_BY_INDEX_RANGE, returnType, NewIntegerValue(index), NewIntegerValue(count))
+}
+
+func newMapReadOp(binName string, opCode int16, returnType MapReturnType, args ...Value) *Operation {
+	opValue := newCDTOpValue(opCode, append([]Value{NewIntegerValue(int(returnType))}, args...)...)
+	opValue.keyValuePairs = returnType == MAP_RETURN_KEY_VALUE
+	return &Operation{OpType: CDT_READ, BinName: &binName, BinValue: opValue}
+}
@@ -16,16 +16,20 @@ package aerospike
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha1"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	. "github.com/aerospike/aerospike-client-go/logger"
 	. "github.com/aerospike/aerospike-client-go/types"
 )
 
@@ -38,10 +42,18 @@ type Client struct {
 	DefaultPolicy *BasePolicy
 	// DefaultWritePolicy is used for all write commands without a specific policy.
 	DefaultWritePolicy *WritePolicy
+	// DefaultBatchPolicy is used for all batch commands without a specific policy.
+	DefaultBatchPolicy *BatchPolicy
 	// DefaultScanPolicy is used for all quey commands without a specific policy.
 	DefaultScanPolicy *ScanPolicy
 	// DefaultQueryPolicy is used for all scan commands without a specific policy.
 	DefaultQueryPolicy *QueryPolicy
+	// DefaultAdminPolicy is used for all user administration commands without a specific policy.
+	DefaultAdminPolicy *AdminPolicy
+
+	// UDF modules registered by the client, by server path and content hash
+	udfMutex       sync.Mutex
+	registeredUDFs map[string]bool
 }
 
 //-------------------------------------------------------
@@ -69,37 +81,113 @@ func NewClientWithPolicyAndHost(policy *ClientPolicy, hosts ...*Host) (*Client,
 
 	cluster, err := NewCluster(policy, hosts)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to connect to host(s): %v", hosts)
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, fmt.Sprintf("Failed to connect to host(s): %v", hosts))
 	}
-	return &Client{
+	clnt := &Client{
 		cluster:            cluster,
-		DefaultPolicy:      NewPolicy(),
-		DefaultWritePolicy: NewWritePolicy(0, 0),
-		DefaultScanPolicy:  NewScanPolicy(),
-		DefaultQueryPolicy: NewQueryPolicy(),
-	}, nil
+		DefaultPolicy:      policy.DefaultPolicy,
+		DefaultWritePolicy: policy.DefaultWritePolicy,
+		DefaultBatchPolicy: policy.DefaultBatchPolicy,
+		DefaultScanPolicy:  policy.DefaultScanPolicy,
+		DefaultQueryPolicy: policy.DefaultQueryPolicy,
+		DefaultAdminPolicy: policy.DefaultAdminPolicy,
+	}
+
+	if clnt.DefaultPolicy == nil {
+		clnt.DefaultPolicy = NewPolicy()
+	}
+	if clnt.DefaultWritePolicy == nil {
+		clnt.DefaultWritePolicy = NewWritePolicy(0, 0)
+	}
+	if clnt.DefaultBatchPolicy == nil {
+		clnt.DefaultBatchPolicy = NewBatchPolicy()
+	}
+	if clnt.DefaultScanPolicy == nil {
+		clnt.DefaultScanPolicy = NewScanPolicy()
+	}
+	if clnt.DefaultQueryPolicy == nil {
+		clnt.DefaultQueryPolicy = NewQueryPolicy()
+	}
+	if clnt.DefaultAdminPolicy == nil {
+		clnt.DefaultAdminPolicy = NewAdminPolicy()
+	}
 
+	return clnt, nil
 }
 
 //-------------------------------------------------------
 // Cluster Connection Management
 //-------------------------------------------------------
 
-// Close closes all client connections to database server nodes.
+// Close closes all client connections to database server nodes immediately.
+// Commands still in flight will fail; use CloseWithTimeout to let them complete.
 func (clnt *Client) Close() {
 	clnt.cluster.Close()
 }
 
+// CloseWithTimeout stops the cluster tend goroutine, waits up to timeout for
+// the commands in flight to complete, and then closes all connections.
+// New commands should not be issued once the close has started.
+// If timeout is zero or negative, it waits until all commands are complete.
+// A TIMEOUT error is returned if some commands were still in flight when the
+// connections were closed.
+func (clnt *Client) CloseWithTimeout(timeout time.Duration) error {
+	return clnt.cluster.CloseWithTimeout(timeout)
+}
+
 // IsConnected determines if the client is ready to talk to the database server cluster.
 func (clnt *Client) IsConnected() bool {
 	return clnt.cluster.IsConnected()
 }
 
+// SetNodeListener sets fn to be called whenever nodes join or leave the cluster,
+// with the nodes which were added and removed, and all the nodes of the cluster
+// before and after the change. fn is called from its own goroutine, in the order
+// of the changes, so a slow listener does not delay the cluster tend.
+// Pass nil to remove the listener.
+func (clnt *Client) SetNodeListener(fn NodeListener) {
+	clnt.cluster.SetNodeListener(fn)
+}
+
+// SetLogger routes the client's internal log messages, like cluster tend failures,
+// node changes, connection errors and command retries, to l.
+// l receives all messages regardless of the log level. Pass nil to restore
+// the default logger, which is quiet unless a log level is set.
+// Logging is shared by the package, so this affects all clients.
+func (clnt *Client) SetLogger(l GenericLogger) {
+	Logger.SetCustomLogger(l)
+}
+
+// WaitUntilConnected blocks until the client is connected to the cluster.
+// After all nodes are lost, the client keeps seeding the cluster from the
+// original seed hosts, and this method can be used to wait until it recovers.
+// If timeout is zero or negative, it waits indefinitely.
+// It returns an error if the timeout expires or the client is closed first.
+func (clnt *Client) WaitUntilConnected(timeout time.Duration) error {
+	return clnt.cluster.WaitUntilConnected(timeout)
+}
+
+// WaitUntilReady blocks until the client is connected to the cluster and knows
+// the master node of every partition of all namespaces, so that the first commands
+// after NewClient do not fail while the cluster is still being discovered.
+// If timeout is zero or negative, it waits indefinitely.
+// It returns an error describing the missing partitions if the timeout expires,
+// or an error if the client is closed first.
+func (clnt *Client) WaitUntilReady(timeout time.Duration) error {
+	return clnt.cluster.WaitUntilReady(timeout)
+}
+
 // GetNodes returns an array of active server nodes in the cluster.
 func (clnt *Client) GetNodes() []*Node {
 	return clnt.cluster.GetNodes()
 }
 
+// Stats returns a snapshot of the connection pool and command
+// counters of all active nodes in the cluster, and their totals.
+func (clnt *Client) Stats() *ClientStats {
+	return newClientStats(clnt.cluster.GetNodes())
+}
+
 // GetNodeNames returns a list of active server node names in the cluster.
 func (clnt *Client) GetNodeNames() []string {
 	nodes := clnt.cluster.GetNodes()
@@ -111,6 +199,70 @@ func (clnt *Client) GetNodeNames() []string {
 	return names
 }
 
+// SupportsFeature returns true if all the nodes of the cluster advertise the
+// feature in the response of the "features" info command, e.g. "pscans",
+// "batch-any" or "compression". It returns false if the cluster has no nodes.
+// Use it to check the capabilities of the servers before using commands which
+// depend on their version.
+func (clnt *Client) SupportsFeature(feature string) bool {
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return false
+	}
+
+	for _, node := range nodes {
+		if !node.SupportsFeature(feature) {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestInfoAll sends the info commands to all active nodes in the cluster concurrently,
+// and returns the responses of each node keyed by node name.
+// The responses of the nodes which failed are left out, and the first error is returned.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) RequestInfoAll(policy *BasePolicy, commands ...string) (map[string]map[string]string, error) {
+	if policy == nil {
+		if clnt.DefaultPolicy != nil {
+			policy = clnt.DefaultPolicy
+		} else {
+			policy = NewPolicy()
+		}
+	}
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Info request failed because cluster is empty.")
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	res := make(map[string]map[string]string, len(nodes))
+
+	wg.Add(len(nodes))
+	for _, node := range nodes {
+		go func(node *Node) {
+			defer wg.Done()
+			response, err := node.requestInfo(policy.Timeout, commands...)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = newNodeError(node, err)
+				}
+				return
+			}
+			res[node.GetName()] = response
+		}(node)
+	}
+	wg.Wait()
+
+	return res, firstErr
+}
+
 //-------------------------------------------------------
 // Write Record Operations
 //-------------------------------------------------------
@@ -118,17 +270,30 @@ func (clnt *Client) GetNodeNames() []string {
 // Put writes record bin(s) to the server.
 // The policy specifies the transaction timeout, record expiration and how the transaction is
 // handled when the record already exists.
+// Bins set to nil are removed from the record.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) Put(policy *WritePolicy, key *Key, bins BinMap) error {
 	return clnt.PutBins(policy, key, binMapToBins(bins)...)
 }
 
+// PutWithContext works the same as Put, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) PutWithContext(ctx context.Context, policy *WritePolicy, key *Key, bins BinMap) error {
+	return clnt.PutBinsWithContext(ctx, policy, key, binMapToBins(bins)...)
+}
+
 // PutBins writes record bin(s) to the server.
 // The policy specifies the transaction timeout, record expiration and how the transaction is
 // handled when the record already exists.
 // This method avoids using the BinMap allocation and iteration and is lighter on GC.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
+	return clnt.PutBinsWithContext(context.Background(), policy, key, bins...)
+}
+
+// PutBinsWithContext works the same as PutBins, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) PutBinsWithContext(ctx context.Context, policy *WritePolicy, key *Key, bins ...*Bin) error {
 	if policy == nil {
 		if clnt.DefaultWritePolicy != nil {
 			policy = clnt.DefaultWritePolicy
@@ -137,9 +302,23 @@ func (clnt *Client) PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 		}
 	}
 	command := newWriteCommand(clnt.cluster, policy, key, bins, WRITE)
+	command.ctx = ctx
 	return command.Execute()
 }
 
+// PutObject writes the exported fields of obj as record bins to the server.
+// Bin names are taken from the `as:"binname"` field tag, or the field name if not tagged.
+// Fields tagged with `as:"-"` are skipped. Nested structs are stored as maps,
+// and slices and arrays as lists.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) PutObject(policy *WritePolicy, key *Key, obj interface{}) error {
+	bins, err := marshalObject(obj)
+	if err != nil {
+		return err
+	}
+	return clnt.Put(policy, key, bins)
+}
+
 //-------------------------------------------------------
 // Operations string
 //-------------------------------------------------------
@@ -188,6 +367,18 @@ func (clnt *Client) PrependBins(policy *WritePolicy, key *Key, bins ...*Bin) err
 	return command.Execute()
 }
 
+// AppendString appends value to the string in the bin of the record.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) AppendString(policy *WritePolicy, key *Key, binName string, value string) error {
+	return clnt.AppendBins(policy, key, NewBin(binName, NewStringValue(value)))
+}
+
+// PrependString prepends value to the string in the bin of the record.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) PrependString(policy *WritePolicy, key *Key, binName string, value string) error {
+	return clnt.PrependBins(policy, key, NewBin(binName, NewStringValue(value)))
+}
+
 //-------------------------------------------------------
 // Arithmetic Operations
 //-------------------------------------------------------
@@ -214,6 +405,13 @@ func (clnt *Client) AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 	return command.Execute()
 }
 
+// AddInt adds delta to the integer in the bin of the record.
+// If the bin does not exist, it is created with the value of delta.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) AddInt(policy *WritePolicy, key *Key, binName string, delta int64) error {
+	return clnt.AddBins(policy, key, NewBin(binName, NewLongValue(delta)))
+}
+
 //-------------------------------------------------------
 // Delete Operations
 //-------------------------------------------------------
@@ -222,6 +420,12 @@ func (clnt *Client) AddBins(policy *WritePolicy, key *Key, bins ...*Bin) error {
 // The policy specifies the transaction timeout.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) Delete(policy *WritePolicy, key *Key) (bool, error) {
+	return clnt.DeleteWithContext(context.Background(), policy, key)
+}
+
+// DeleteWithContext works the same as Delete, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) DeleteWithContext(ctx context.Context, policy *WritePolicy, key *Key) (bool, error) {
 	if policy == nil {
 		if clnt.DefaultWritePolicy != nil {
 			policy = clnt.DefaultWritePolicy
@@ -230,6 +434,7 @@ func (clnt *Client) Delete(policy *WritePolicy, key *Key) (bool, error) {
 		}
 	}
 	command := newDeleteCommand(clnt.cluster, policy, key)
+	command.ctx = ctx
 	err := command.Execute()
 	return command.Existed(), err
 }
@@ -243,6 +448,12 @@ func (clnt *Client) Delete(policy *WritePolicy, key *Key) (bool, error) {
 // policy's expiration.
 // If the record doesn't exist, it will return an error.
 func (clnt *Client) Touch(policy *WritePolicy, key *Key) error {
+	return clnt.TouchWithContext(context.Background(), policy, key)
+}
+
+// TouchWithContext works the same as Touch, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) TouchWithContext(ctx context.Context, policy *WritePolicy, key *Key) error {
 	if policy == nil {
 		if clnt.DefaultWritePolicy != nil {
 			policy = clnt.DefaultWritePolicy
@@ -251,9 +462,40 @@ func (clnt *Client) Touch(policy *WritePolicy, key *Key) error {
 		}
 	}
 	command := newTouchCommand(clnt.cluster, policy, key)
+	command.ctx = ctx
 	return command.Execute()
 }
 
+// GetAndTouch reads the record for the specified key, and resets its expiration
+// to the specified one in the same command, e.g. for caches with sliding expiration.
+// The expiration of the policy is ignored. If no bin names are passed, all bins are read.
+// The returned record carries the generation and expiration after the touch.
+// If the record does not exist, a KEY_NOT_FOUND_ERROR is returned.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) GetAndTouch(policy *WritePolicy, key *Key, expiration int32, binNames ...string) (*Record, error) {
+	if policy == nil {
+		if clnt.DefaultWritePolicy != nil {
+			policy = clnt.DefaultWritePolicy
+		} else {
+			policy = NewWritePolicy(0, 0)
+		}
+	}
+
+	// do not change the expiration of a shared policy
+	touchPolicy := *policy
+	touchPolicy.Expiration = expiration
+
+	operations := []*Operation{TouchOp()}
+	if len(binNames) == 0 {
+		operations = append(operations, GetOp())
+	} else {
+		for _, binName := range binNames {
+			operations = append(operations, GetOpForBin(binName))
+		}
+	}
+	return clnt.Operate(&touchPolicy, key, operations...)
+}
+
 //-------------------------------------------------------
 // Existence-Check Operations
 //-------------------------------------------------------
@@ -262,6 +504,12 @@ func (clnt *Client) Touch(policy *WritePolicy, key *Key) error {
 // The policy can be used to specify timeouts.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) Exists(policy *BasePolicy, key *Key) (bool, error) {
+	return clnt.ExistsWithContext(context.Background(), policy, key)
+}
+
+// ExistsWithContext works the same as Exists, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) ExistsWithContext(ctx context.Context, policy *BasePolicy, key *Key) (bool, error) {
 	if policy == nil {
 		if clnt.DefaultPolicy != nil {
 			policy = clnt.DefaultPolicy
@@ -270,20 +518,24 @@ func (clnt *Client) Exists(policy *BasePolicy, key *Key) (bool, error) {
 		}
 	}
 	command := newExistsCommand(clnt.cluster, policy, key)
+	command.ctx = ctx
 	err := command.Execute()
 	return command.Exists(), err
 }
 
 // BatchExists determines if multiple record keys exist in one batch request.
 // The returned array bool is in positional order with the original key array order.
+// Keys on the same node are sent to the server in a single request.
+// If some of the nodes could not be reached, a *PartialResultError is returned
+// that carries the results resolved from the other nodes.
 // The policy can be used to specify timeouts.
 // If the policy is nil, a default policy will be generated.
-func (clnt *Client) BatchExists(policy *BasePolicy, keys []*Key) ([]bool, error) {
+func (clnt *Client) BatchExists(policy *BatchPolicy, keys []*Key) ([]bool, error) {
 	if policy == nil {
-		if clnt.DefaultPolicy != nil {
-			policy = clnt.DefaultPolicy
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
 		} else {
-			policy = NewPolicy()
+			policy = NewBatchPolicy()
 		}
 	}
 
@@ -293,10 +545,34 @@ func (clnt *Client) BatchExists(policy *BasePolicy, keys []*Key) ([]bool, error)
 
 	keyMap := newBatchItemList(keys)
 
-	if err := clnt.batchExecute(keys, func(node *Node, bns *batchNamespace) command {
-		return newBatchCommandExists(node, bns, policy, keyMap, existsArray)
-	}); err != nil {
-		return nil, err
+	failed, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+		return newBatchCommandExists(node, bns, policy.BasePolicy, keyMap, existsArray)
+	})
+	if err != nil {
+		if len(failed) == 0 {
+			return nil, err
+		}
+
+		failedDigests := map[string]struct{}{}
+		for _, bns := range failed {
+			for _, key := range bns.keys {
+				failedDigests[string(key.digest)] = struct{}{}
+			}
+		}
+
+		failedIndexes := []int{}
+		for i, key := range keys {
+			if _, exists := failedDigests[string(key.digest)]; exists {
+				existsArray[i] = false
+				failedIndexes = append(failedIndexes, i)
+			}
+		}
+
+		return existsArray, &PartialResultError{
+			error:         err,
+			Exists:        existsArray,
+			FailedIndexes: failedIndexes,
+		}
 	}
 
 	return existsArray, nil
@@ -310,6 +586,12 @@ func (clnt *Client) BatchExists(policy *BasePolicy, keys []*Key) ([]bool, error)
 // The policy can be used to specify timeouts.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) Get(policy *BasePolicy, key *Key, binNames ...string) (*Record, error) {
+	return clnt.GetWithContext(context.Background(), policy, key, binNames...)
+}
+
+// GetWithContext works the same as Get, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) GetWithContext(ctx context.Context, policy *BasePolicy, key *Key, binNames ...string) (*Record, error) {
 	if policy == nil {
 		if clnt.DefaultPolicy != nil {
 			policy = clnt.DefaultPolicy
@@ -318,6 +600,7 @@ func (clnt *Client) Get(policy *BasePolicy, key *Key, binNames ...string) (*Reco
 		}
 	}
 	command := newReadCommand(clnt.cluster, policy, key, binNames)
+	command.ctx = ctx
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
@@ -329,6 +612,12 @@ func (clnt *Client) Get(policy *BasePolicy, key *Key, binNames ...string) (*Reco
 // The policy can be used to specify timeouts.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) GetHeader(policy *BasePolicy, key *Key) (*Record, error) {
+	return clnt.GetHeaderWithContext(context.Background(), policy, key)
+}
+
+// GetHeaderWithContext works the same as GetHeader, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) GetHeaderWithContext(ctx context.Context, policy *BasePolicy, key *Key) (*Record, error) {
 	if policy == nil {
 		if clnt.DefaultPolicy != nil {
 			policy = clnt.DefaultPolicy
@@ -337,12 +626,29 @@ func (clnt *Client) GetHeader(policy *BasePolicy, key *Key) (*Record, error) {
 		}
 	}
 	command := newReadHeaderCommand(clnt.cluster, policy, key)
+	command.ctx = ctx
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
 	return command.GetRecord(), nil
 }
 
+// GetObject reads a record for specified key and populates the struct obj points to.
+// Fields are matched to bins using the same rules as PutObject.
+// If the record does not exist, a KEY_NOT_FOUND_ERROR is returned.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) GetObject(policy *BasePolicy, key *Key, obj interface{}) error {
+	rec, err := clnt.Get(policy, key)
+	if err != nil {
+		return err
+	}
+
+	if rec == nil {
+		return NewAerospikeError(KEY_NOT_FOUND_ERROR)
+	}
+	return unmarshalObject(rec.Bins, obj)
+}
+
 //-------------------------------------------------------
 // Batch Read Operations
 //-------------------------------------------------------
@@ -350,14 +656,23 @@ func (clnt *Client) GetHeader(policy *BasePolicy, key *Key) (*Record, error) {
 // BatchGet reads multiple record headers and bins for specified keys in one batch request.
 // The returned records are in positional order with the original key array order.
 // If a key is not found, the positional record will be nil.
-// The policy can be used to specify timeouts.
+// The policy can be used to specify timeouts. The number of nodes queried in parallel
+// is limited by the MaxConcurrentNodes of the client's DefaultBatchPolicy; use
+// BatchGetWithPolicy to set it per call.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) BatchGet(policy *BasePolicy, keys []*Key, binNames ...string) ([]*Record, error) {
+	return clnt.BatchGetWithPolicy(clnt.batchPolicyOf(policy), keys, binNames...)
+}
+
+// BatchGetWithPolicy works like BatchGet, but takes a BatchPolicy, which also
+// limits the number of nodes queried in parallel.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) BatchGetWithPolicy(policy *BatchPolicy, keys []*Key, binNames ...string) ([]*Record, error) {
 	if policy == nil {
-		if clnt.DefaultPolicy != nil {
-			policy = clnt.DefaultPolicy
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
 		} else {
-			policy = NewPolicy()
+			policy = NewBatchPolicy()
 		}
 	}
 
@@ -371,8 +686,8 @@ func (clnt *Client) BatchGet(policy *BasePolicy, keys []*Key, binNames ...string
 		binSet[binNames[idx]] = struct{}{}
 	}
 
-	err := clnt.batchExecute(keys, func(node *Node, bns *batchNamespace) command {
-		return newBatchCommandGet(node, bns, policy, keyMap, binSet, records, _INFO1_READ)
+	_, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+		return newBatchCommandGet(node, bns, policy.BasePolicy, keyMap, binSet, records, _INFO1_READ)
 	})
 	if err != nil {
 		return nil, err
@@ -384,14 +699,23 @@ func (clnt *Client) BatchGet(policy *BasePolicy, keys []*Key, binNames ...string
 // BatchGetHeader reads multiple record header data for specified keys in one batch request.
 // The returned records are in positional order with the original key array order.
 // If a key is not found, the positional record will be nil.
-// The policy can be used to specify timeouts.
+// The policy can be used to specify timeouts. The number of nodes queried in parallel
+// is limited by the MaxConcurrentNodes of the client's DefaultBatchPolicy; use
+// BatchGetHeaderWithPolicy to set it per call.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record, error) {
+	return clnt.BatchGetHeaderWithPolicy(clnt.batchPolicyOf(policy), keys)
+}
+
+// BatchGetHeaderWithPolicy works like BatchGetHeader, but takes a BatchPolicy,
+// which also limits the number of nodes queried in parallel.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) BatchGetHeaderWithPolicy(policy *BatchPolicy, keys []*Key) ([]*Record, error) {
 	if policy == nil {
-		if clnt.DefaultPolicy != nil {
-			policy = clnt.DefaultPolicy
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
 		} else {
-			policy = NewPolicy()
+			policy = NewBatchPolicy()
 		}
 	}
 
@@ -400,8 +724,8 @@ func (clnt *Client) BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record,
 	records := make([]*Record, len(keys))
 
 	keyMap := newBatchItemList(keys)
-	err := clnt.batchExecute(keys, func(node *Node, bns *batchNamespace) command {
-		return newBatchCommandGet(node, bns, policy, keyMap, nil, records, _INFO1_READ|_INFO1_NOBINDATA)
+	_, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+		return newBatchCommandGet(node, bns, policy.BasePolicy, keyMap, nil, records, _INFO1_READ|_INFO1_NOBINDATA)
 	})
 	if err != nil {
 		return nil, err
@@ -410,154 +734,777 @@ func (clnt *Client) BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record,
 	return records, nil
 }
 
-//-------------------------------------------------------
-// Generic Database Operations
-//-------------------------------------------------------
-
-// Operate performs multiple read/write operations on a single key in one batch request.
-// An example would be to add an integer value to an existing record and then
-// read the result, all in one database call.
-//
-// Write operations are always performed first, regardless of operation order
-// relative to read operations.
-// If the policy is nil, a default policy will be generated.
-func (clnt *Client) Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error) {
+// batchPolicyOf returns a batch policy for batch commands which take a BasePolicy,
+// with the MaxConcurrentNodes of the DefaultBatchPolicy. A nil policy is replaced
+// by the DefaultPolicy of the client.
+func (clnt *Client) batchPolicyOf(policy *BasePolicy) *BatchPolicy {
 	if policy == nil {
-		if clnt.DefaultWritePolicy != nil {
-			policy = clnt.DefaultWritePolicy
+		if clnt.DefaultPolicy != nil {
+			policy = clnt.DefaultPolicy
 		} else {
-			policy = NewWritePolicy(0, 0)
+			policy = NewPolicy()
 		}
 	}
-	command := newOperateCommand(clnt.cluster, policy, key, operations)
-	if err := command.Execute(); err != nil {
-		return nil, err
+
+	res := &BatchPolicy{BasePolicy: policy}
+	if clnt.DefaultBatchPolicy != nil {
+		res.MaxConcurrentNodes = clnt.DefaultBatchPolicy.MaxConcurrentNodes
 	}
-	return command.GetRecord(), nil
+	return res
 }
 
-//-------------------------------------------------------
-// Scan Operations
-//-------------------------------------------------------
-
-// ScanAll reads all records in specified namespace and set from all nodes.
-// If the policy's concurrentNodes is specified, each server node will be read in
-// parallel. Otherwise, server nodes are read sequentially.
+// BatchGetOperate applies the read operations to multiple keys in one batch request,
+// grouping the keys by node. Only read operations are allowed.
+// The returned records are in positional order with the original key array order.
+// If a key is not found, the positional record will be nil.
+// This method requires batch index support on all nodes (Aerospike 3.6+ servers).
 // If the policy is nil, a default policy will be generated.
-func (clnt *Client) ScanAll(policy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error) {
+func (clnt *Client) BatchGetOperate(policy *BatchPolicy, keys []*Key, operations ...*Operation) ([]*Record, error) {
 	if policy == nil {
-		if clnt.DefaultScanPolicy != nil {
-			policy = clnt.DefaultScanPolicy
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
 		} else {
-			policy = NewScanPolicy()
+			policy = NewBatchPolicy()
 		}
 	}
 
-	nodes := clnt.cluster.GetNodes()
-	if len(nodes) == 0 {
-		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Scan failed because cluster is empty.")
-	}
-
-	if policy.WaitUntilMigrationsAreOver {
-		// wait until all migrations are finished
-		if err := clnt.cluster.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
-			return nil, err
+	readAttr := _INFO1_READ
+	for _, operation := range operations {
+		switch operation.OpType {
+		case READ:
+			// Read all bins if no bin is specified.
+			if operation.BinName == nil {
+				readAttr |= _INFO1_GET_ALL
+			}
+		case CDT_READ, BIT_READ, HLL_READ:
+		default:
+			return nil, NewAerospikeError(PARAMETER_ERROR, "BatchGetOperate only supports read operations.")
 		}
 	}
 
-	// result recordset
-	res := NewRecordset(policy.RecordQueueSize)
-
-	// the whole call should be wrapped in a goroutine
-	if policy.ConcurrentNodes {
-		// results channel must be async for performance
-		recChans := []chan *Record{}
-		errChans := []chan error{}
-		recCmds := []multiCommand{}
-		for _, node := range nodes {
-			res, err := clnt.ScanNode(policy, node, namespace, setName, binNames...)
-			if err != nil {
-				return nil, err
-			}
-			recChans = append(recChans, res.Records)
-			errChans = append(errChans, res.Errors)
-			recCmds = append(recCmds, res.commands...)
+	for _, node := range clnt.cluster.GetNodes() {
+		if !node.supportsBatchIndex {
+			return nil, NewAerospikeError(UNSUPPORTED_FEATURE, "Node "+node.String()+" does not support batch operations.")
 		}
+	}
 
-		res.chans = recChans
-		res.errs = errChans
-		res.commands = recCmds
-		res.Records, res.Errors = clnt.mergeResultChannels(policy.RecordQueueSize, recChans, errChans)
-	} else {
-		// drain nodes one by one
-		go func() {
-			defer close(res.Records)
-			defer close(res.Errors)
-
-			for _, node := range nodes {
-				if recSet, err := clnt.ScanNode(policy, node, namespace, setName, binNames...); err != nil {
-					res.Errors <- err
-					continue
-				} else {
-					// Here be concurrent dragons
-					// Don't wait for err channels to close; only record chans
-				L:
-					for {
-						select {
-						case err := <-recSet.Errors:
-							res.drainRecords(recSet.Records)
-							res.Errors <- err
+	// same array can be used without sychronization;
+	// when a key exists, the corresponding indexes will be set to record
+	records := make([]*Record, len(keys))
 
-							// this break will move on to the next node
-							break L
-						case rec, open := <-recSet.Records:
-							if open && rec != nil {
-								res.Records <- rec
-							} else if !open {
-								// channel has been closed
-								res.drainErrors(recSet.Errors)
+	positions := make(map[string][]int, len(keys))
+	for i, key := range keys {
+		positions[string(key.digest)] = append(positions[string(key.digest)], i)
+	}
 
-								// this break will move on to the next node
-								break L
-							}
-						}
-					}
-				}
-			}
-		}()
+	_, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+		return newBatchCommandOperate(node, bns, policy.BasePolicy, positions, operations, records, readAttr)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return res, nil
+	return records, nil
 }
 
-// ScanNode reads all records in specified namespace and set for one node only.
+// BatchGetComplex reads multiple records in one batch request, where each key
+// specifies its own bins or read operations. The Record field of each BatchRead
+// is set to the record read, or to nil if the record does not exist.
+// The same key can be requested more than once with different bins.
+// This method requires batch index support on all nodes (Aerospike 3.6+ servers).
 // If the policy is nil, a default policy will be generated.
-func (clnt *Client) ScanNode(policy *ScanPolicy, node *Node, namespace string, setName string, binNames ...string) (*Recordset, error) {
+func (clnt *Client) BatchGetComplex(policy *BatchPolicy, records []*BatchRead) error {
 	if policy == nil {
-		if clnt.DefaultScanPolicy != nil {
-			policy = clnt.DefaultScanPolicy
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
 		} else {
-			policy = NewScanPolicy()
+			policy = NewBatchPolicy()
 		}
 	}
 
-	if policy.WaitUntilMigrationsAreOver {
-		// wait until migrations on node are finished
-		if err := node.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
-			return nil, err
+	keys := make([]*Key, len(records))
+	for i, record := range records {
+		if err := record.validate(); err != nil {
+			return err
 		}
+		record.Record = nil
+		keys[i] = record.Key
+	}
+
+	for _, node := range clnt.cluster.GetNodes() {
+		if !node.supportsBatchIndex {
+			return NewAerospikeError(UNSUPPORTED_FEATURE, "Node "+node.String()+" does not support batch operations.")
+		}
+	}
+
+	_, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+		return newBatchCommandComplex(node, bns, policy.BasePolicy, records)
+	})
+	return err
+}
+
+// BatchGetChannel reads multiple records for specified keys, grouping the keys by node,
+// and sends the results of each node to the returned records channel as soon as
+// the node responds, instead of waiting for all nodes like BatchGet.
+// Each BatchRecord carries the original key and the record read, which is nil if
+// the record does not exist. If a node fails, the results of its keys carry the error,
+// which is also sent to the errors channel.
+// Both channels are closed after all nodes have finished. They are buffered to hold
+// the results of all keys, so neither has to be read for the command to complete.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) BatchGetChannel(policy *BatchPolicy, keys []*Key) (<-chan *BatchRecord, <-chan error) {
+	if policy == nil {
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
+		} else {
+			policy = NewBatchPolicy()
+		}
+	}
+
+	records := make(chan *BatchRecord, len(keys))
+	errs := make(chan error, len(keys)+1)
+
+	// same array can be used without sychronization;
+	// when a key exists, the corresponding index will be set to record
+	results := make([]*Record, len(keys))
+
+	keyMap := newBatchItemList(keys)
+	positions := make(map[string][]int, len(keys))
+	for i, key := range keys {
+		positions[string(key.digest)] = append(positions[string(key.digest)], i)
+	}
+
+	// sends the results of the keys of a node once it responded or failed
+	var mutex sync.Mutex
+	reported := map[*batchNamespace]struct{}{}
+	report := func(bns *batchNamespace, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if _, exists := reported[bns]; exists {
+			return
+		}
+		reported[bns] = struct{}{}
+
+		if err != nil {
+			errs <- err
+		}
+
+		for _, key := range bns.keys {
+			indexes := positions[string(key.digest)]
+			// duplicate keys are sent once for all their indexes
+			delete(positions, string(key.digest))
+
+			for _, index := range indexes {
+				switch {
+				case err != nil:
+					records <- newBatchRecordError(keys[index], err)
+				case results[index] != nil:
+					res := newBatchRecord(keys[index], OK)
+					res.Record = results[index]
+					records <- res
+				default:
+					records <- newBatchRecord(keys[index], KEY_NOT_FOUND_ERROR)
+				}
+			}
+		}
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(records)
+
+		failed, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+			return &batchCommandGetStream{
+				batchCommandGet: newBatchCommandGet(node, bns, policy.BasePolicy, keyMap, nil, results, _INFO1_READ),
+				onComplete:      func(err error) { report(bns, err) },
+			}
+		})
+		if err != nil && len(failed) == 0 {
+			// the keys could not be assigned to nodes
+			errs <- err
+			return
+		}
+
+		// namespaces without a node did not issue a command
+		for _, bns := range failed {
+			report(bns, NewAerospikeError(SERVER_NOT_AVAILABLE, "No node available for namespace "+*bns.namespace))
+		}
+	}()
+
+	return records, errs
+}
+
+//-------------------------------------------------------
+// Batch Write Operations
+//-------------------------------------------------------
+
+// BatchDelete deletes multiple records in one batch request, grouping the keys by node.
+// The returned results are in positional order with the original key array order.
+// Each result reports whether its record was found and deleted (OK) or did not exist
+// (KEY_NOT_FOUND_ERROR). Failures are reported per key in the results, e.g. when the
+// node holding some of the keys could not be reached, and do not fail the whole batch.
+// This method requires batch write support on all nodes (Aerospike 6.0+ servers).
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) BatchDelete(policy *BatchPolicy, keys []*Key) ([]*BatchRecord, error) {
+	if policy == nil {
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
+		} else {
+			policy = NewBatchPolicy()
+		}
+	}
+
+	for _, node := range clnt.cluster.GetNodes() {
+		if !node.supportsBatchAny {
+			return nil, NewAerospikeError(UNSUPPORTED_FEATURE, "Node "+node.String()+" does not support batch writes.")
+		}
+	}
+
+	// same array can be used without sychronization;
+	// each key's result will be set at its indexes
+	records := make([]*BatchRecord, len(keys))
+
+	positions := make(map[string][]int, len(keys))
+	for i, key := range keys {
+		positions[string(key.digest)] = append(positions[string(key.digest)], i)
+	}
+
+	failed, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+		return newBatchCommandDelete(node, bns, policy.BasePolicy, positions, records)
+	})
+	if err != nil && len(failed) == 0 {
+		return nil, err
+	}
+
+	// report the failures of the keys which did not receive a result
+	for _, bns := range failed {
+		for _, key := range bns.keys {
+			for _, index := range positions[string(key.digest)] {
+				if records[index] == nil {
+					records[index] = newBatchRecordError(keys[index], err)
+				}
+			}
+		}
+	}
+
+	for i := range records {
+		if records[i] == nil {
+			records[i] = newBatchRecordError(keys[i], NewAerospikeError(PARSE_ERROR, "No result returned for the key."))
+		} else {
+			records[i].Key = keys[i]
+		}
+	}
+
+	return records, nil
+}
+
+// OperateBatch applies the same read/write operations to multiple keys in one batch request
+// per node, instead of one Operate command per key.
+// The returned results are in positional order with the original key array order.
+// The Record of each result holds the bins returned by the read operations.
+// Failures are reported per key in the results, e.g. when the node holding some
+// of the keys could not be reached, and do not fail the whole batch.
+// This method requires batch write support on all nodes (Aerospike 6.0+ servers).
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) OperateBatch(policy *BatchPolicy, keys []*Key, operations ...*Operation) ([]*BatchRecord, error) {
+	if policy == nil {
+		if clnt.DefaultBatchPolicy != nil {
+			policy = clnt.DefaultBatchPolicy
+		} else {
+			policy = NewBatchPolicy()
+		}
+	}
+
+	if len(operations) == 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "No operations were passed.")
+	}
+
+	if err := clnt.cluster.validateOperations(operations); err != nil {
+		return nil, err
+	}
+
+	readAttr := 0
+	writeAttr := 0
+	for _, operation := range operations {
+		switch operation.OpType {
+		case READ:
+			readAttr |= _INFO1_READ
+
+			// Read all bins if no bin is specified.
+			if operation.BinName == nil {
+				readAttr |= _INFO1_GET_ALL
+			}
+		case READ_HEADER, CDT_READ, BIT_READ, HLL_READ:
+			// the record header is returned with every batch result
+			readAttr |= _INFO1_READ
+		default:
+			writeAttr = _INFO2_WRITE
+		}
+	}
+
+	for _, node := range clnt.cluster.GetNodes() {
+		if !node.supportsBatchAny {
+			return nil, NewAerospikeError(UNSUPPORTED_FEATURE, "Node "+node.String()+" does not support batch writes.")
+		}
+	}
+
+	// same array can be used without sychronization;
+	// each key's result will be set at its indexes
+	records := make([]*BatchRecord, len(keys))
+
+	positions := make(map[string][]int, len(keys))
+	for i, key := range keys {
+		positions[string(key.digest)] = append(positions[string(key.digest)], i)
+	}
+
+	failed, err := clnt.batchExecute(policy.MaxConcurrentNodes, keys, func(node *Node, bns *batchNamespace) command {
+		return newBatchCommandWrite(node, bns, policy.BasePolicy, positions, operations, records, readAttr, writeAttr)
+	})
+	if err != nil && len(failed) == 0 {
+		return nil, err
+	}
+
+	// report the failures of the keys which did not receive a result
+	for _, bns := range failed {
+		for _, key := range bns.keys {
+			for _, index := range positions[string(key.digest)] {
+				if records[index] == nil {
+					records[index] = newBatchRecordError(keys[index], err)
+				}
+			}
+		}
+	}
+
+	for i := range records {
+		if records[i] == nil {
+			records[i] = newBatchRecordError(keys[i], NewAerospikeError(PARSE_ERROR, "No result returned for the key."))
+		} else {
+			records[i].Key = keys[i]
+		}
+	}
+
+	return records, nil
+}
+
+//-------------------------------------------------------
+// Generic Database Operations
+//-------------------------------------------------------
+
+// Operate performs multiple read/write operations on a single key in one batch request.
+// An example would be to add an integer value to an existing record and then
+// read the result, all in one database call.
+//
+// Write operations are always performed first, regardless of operation order
+// relative to read operations.
+// The returned record always carries the generation and expiration of the record
+// after the operations were applied, even if only write operations were requested.
+// This can be used with GenerationPolicy for compare-and-set loops.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error) {
+	return clnt.OperateWithContext(context.Background(), policy, key, operations...)
+}
+
+// OperateWithContext works the same as Operate, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) OperateWithContext(ctx context.Context, policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error) {
+	if policy == nil {
+		if clnt.DefaultWritePolicy != nil {
+			policy = clnt.DefaultWritePolicy
+		} else {
+			policy = NewWritePolicy(0, 0)
+		}
+	}
+	command := newOperateCommand(clnt.cluster, policy, key, operations)
+	command.ctx = ctx
+	if err := command.Execute(); err != nil {
+		return nil, err
+	}
+	return command.GetRecord(), nil
+}
+
+// OperateWithResults works like Operate, but also returns the result of each operation
+// at the index of the operation, so that the results of several operations on the
+// same bin can be told apart without relying on the order of OpResults.
+// Writes which return no value, like PutOp, and operations which are not applied
+// to a bin like TouchOp, have a nil result; ListAppendOp returns the size of the list.
+// GetOp is not supported, since it returns a result per bin.
+// RespondPerEachOp is always set on a copy of the policy.
+// If the policy is nil, the default write policy will be used.
+func (clnt *Client) OperateWithResults(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, OperateResults, error) {
+	if policy == nil {
+		if clnt.DefaultWritePolicy != nil {
+			policy = clnt.DefaultWritePolicy
+		} else {
+			policy = NewWritePolicy(0, 0)
+		}
+	}
+
+	for _, op := range operations {
+		if op.OpType == READ && op.BinName == nil {
+			return nil, nil, NewAerospikeError(PARAMETER_ERROR, "GetOp is not supported by OperateWithResults.")
+		}
+	}
+
+	respondPolicy := *policy
+	respondPolicy.RespondPerEachOp = true
+
+	command := newOperateCommand(clnt.cluster, &respondPolicy, key, operations)
+	command.collectOpValues = true
+	if err := command.Execute(); err != nil {
+		return nil, nil, err
+	}
+	return command.GetRecord(), command.operateResults(), nil
+}
+
+//-------------------------------------------------------
+// Scan Operations
+//-------------------------------------------------------
+
+// ScanAll reads all records in specified namespace and set from all nodes.
+// If the policy's concurrentNodes is specified, each server node will be read in
+// parallel. Otherwise, server nodes are read sequentially.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) ScanAll(policy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	if policy == nil {
+		if clnt.DefaultScanPolicy != nil {
+			policy = clnt.DefaultScanPolicy
+		} else {
+			policy = NewScanPolicy()
+		}
+	}
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Scan failed because cluster is empty.")
+	}
+
+	if policy.WaitUntilMigrationsAreOver {
+		// wait until all migrations are finished
+		if err := clnt.cluster.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	// result recordset
+	res := NewRecordset(policy.RecordQueueSize)
+
+	// the total timeout is shared by the scans of all nodes
+	ctx := res.totalTimeoutContext(policy.TotalTimeout)
+
+	// the whole call should be wrapped in a goroutine
+	if policy.ConcurrentNodes {
+		// limits the nodes scanned at the same time; the other scans wait for a slot
+		var slots chan struct{}
+		if policy.MaxConcurrentNodes > 0 && policy.MaxConcurrentNodes < len(nodes) {
+			slots = make(chan struct{}, policy.MaxConcurrentNodes)
+		}
+
+		// results channel must be async for performance
+		recChans := []chan *Record{}
+		errChans := []chan error{}
+		recCmds := []multiCommand{}
+		for _, node := range nodes {
+			res, err := clnt.scanNode(ctx, slots, policy, node, namespace, setName, binNames...)
+			if err != nil {
+				return nil, err
+			}
+			recChans = append(recChans, res.Records)
+			errChans = append(errChans, res.Errors)
+			recCmds = append(recCmds, res.commands...)
+		}
+
+		res.chans = recChans
+		res.errs = errChans
+		res.commands = recCmds
+		res.Records, res.Errors = clnt.mergeResultChannels(policy.RecordQueueSize, recChans, errChans, res.releaseTotalTimeout)
+	} else {
+		// drain nodes one by one
+		go func() {
+			defer close(res.Records)
+			defer close(res.Errors)
+			defer res.releaseTotalTimeout()
+
+			for _, node := range nodes {
+				if recSet, err := clnt.scanNode(ctx, nil, policy, node, namespace, setName, binNames...); err != nil {
+					res.Errors <- err
+					continue
+				} else {
+					// Here be concurrent dragons
+					// Don't wait for err channels to close; only record chans
+				L:
+					for {
+						select {
+						case err := <-recSet.Errors:
+							res.drainRecords(recSet.Records)
+							res.Errors <- err
+
+							// this break will move on to the next node
+							break L
+						case rec, open := <-recSet.Records:
+							if open && rec != nil {
+								res.Records <- rec
+							} else if !open {
+								// channel has been closed
+								res.drainErrors(recSet.Errors)
+
+								// this break will move on to the next node
+								break L
+							}
+						}
+					}
+				}
+			}
+		}()
+	}
+
+	return res, nil
+}
+
+// ScanNamespaces reads all records in the specified set of each of the namespaces,
+// and merges the results into a single recordset. The namespaces are scanned in
+// parallel, each as by ScanAll with the same policy; the total timeout applies to the
+// scan of each namespace. The source namespace of a record is available from
+// Record.Key.Namespace(). Errors of all the scans are sent on the Errors channel.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) ScanNamespaces(policy *ScanPolicy, namespaces []string, setName string, binNames ...string) (*Recordset, error) {
+	if policy == nil {
+		if clnt.DefaultScanPolicy != nil {
+			policy = clnt.DefaultScanPolicy
+		} else {
+			policy = NewScanPolicy()
+		}
+	}
+
+	if len(namespaces) == 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "No namespaces to scan")
+	}
+
+	recordsets := make([]*Recordset, 0, len(namespaces))
+	closeAll := func() {
+		for _, rs := range recordsets {
+			rs.Close()
+		}
+	}
+
+	recChans := []chan *Record{}
+	errChans := []chan error{}
+	for _, namespace := range namespaces {
+		rs, err := clnt.ScanAll(policy, namespace, setName, binNames...)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		recordsets = append(recordsets, rs)
+		recChans = append(recChans, rs.Records)
+		errChans = append(errChans, rs.Errors)
+	}
+
+	res := NewRecordset(policy.RecordQueueSize)
+	res.chans = recChans
+	res.errs = errChans
+	res.cancel = closeAll
+	res.Records, res.Errors = clnt.mergeResultChannels(policy.RecordQueueSize, recChans, errChans, nil)
+	return res, nil
+}
+
+// ScanAllObjects reads all records in specified namespace and set from all nodes,
+// and sends them on objChan as structs populated using the same rules as GetObject.
+// objChan must be a channel of structs or of pointers to structs, e.g. chan *MyStruct.
+// objChan is closed when the scan is finished.
+// Scan errors, and errors for records that could not be mapped to the struct,
+// are sent on the returned errors channel instead of aborting the scan. It must be
+// consumed along with objChan, and is closed when the scan is finished.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) ScanAllObjects(policy *ScanPolicy, namespace string, setName string, objChan interface{}) (<-chan error, error) {
+	chanValue := reflect.ValueOf(objChan)
+	if chanValue.Kind() != reflect.Chan || chanValue.Type().ChanDir()&reflect.SendDir == 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "objChan must be a sendable channel of structs")
+	}
+
+	objType := chanValue.Type().Elem()
+	isPtr := objType.Kind() == reflect.Ptr
+	if isPtr {
+		objType = objType.Elem()
+	}
+
+	if objType.Kind() != reflect.Struct {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "objChan must be a sendable channel of structs")
+	}
+
+	recordset, err := clnt.ScanAll(policy, namespace, setName)
+	if err != nil {
+		return nil, err
+	}
+
+	errChan := make(chan error, cap(recordset.Errors))
+	go func() {
+		defer close(errChan)
+		defer chanValue.Close()
+
+		records, errs := recordset.Records, recordset.Errors
+		for records != nil || errs != nil {
+			select {
+			case rec, open := <-records:
+				if !open {
+					records = nil
+					continue
+				}
+
+				obj := reflect.New(objType)
+				if err := unmarshalObject(rec.Bins, obj.Interface()); err != nil {
+					errChan <- err
+					continue
+				}
+
+				if isPtr {
+					chanValue.Send(obj)
+				} else {
+					chanValue.Send(obj.Elem())
+				}
+
+			case err, open := <-errs:
+				if !open {
+					errs = nil
+					continue
+				}
+				errChan <- err
+			}
+		}
+	}()
+
+	return errChan, nil
+}
+
+// ScanNode reads all records in specified namespace and set for one node only.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) ScanNode(policy *ScanPolicy, node *Node, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	if policy == nil {
+		if clnt.DefaultScanPolicy != nil {
+			policy = clnt.DefaultScanPolicy
+		} else {
+			policy = NewScanPolicy()
+		}
+	}
+
+	return clnt.scanNode(nil, nil, policy, node, namespace, setName, binNames...)
+}
+
+// scanNode starts the scan of one node in a goroutine. If slots is not nil,
+// the scan waits for a free slot before it is sent to the node. The scan is
+// aborted once ctx is done; if ctx is nil, the total timeout of the policy applies.
+func (clnt *Client) scanNode(ctx context.Context, slots chan struct{}, policy *ScanPolicy, node *Node, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	if policy.WaitUntilMigrationsAreOver {
+		// wait until migrations on node are finished
+		if err := node.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	// results channel must be async for performance
+	res := NewRecordset(policy.RecordQueueSize)
+	if ctx == nil {
+		ctx = res.totalTimeoutContext(policy.TotalTimeout)
 	}
 
-	// results channel must be async for performance
-	res := NewRecordset(policy.RecordQueueSize)
-
 	// Retry policy must be one-shot for scans.
 	// copy on write for policy
 	newPolicy := *policy
 
 	command := newScanCommand(node, &newPolicy, namespace, setName, binNames, res.Records, res.Errors)
+	command.ctx = ctx
 	res.commands = append(res.commands, command)
-	go command.Execute()
+
+	go func() {
+		if slots != nil {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+		}
+		command.Execute()
+
+		// the scan of the node has its own total timeout if ctx was nil
+		res.releaseTotalTimeout()
+	}()
+
+	return res, nil
+}
+
+// ScanPartitions reads all records in the partitions of the filter in the specified
+// namespace and set. Each node is sent a scan for the partitions it holds, and the
+// nodes are scanned concurrently.
+// Partitions which have been scanned completely are recorded in the filter.
+// If the scan is interrupted, it can be resumed with partitionFilter.Remaining().
+// Partitions which were not completed may be sent again when resumed.
+// This method requires servers which support partition scans.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) ScanPartitions(policy *ScanPolicy, partitionFilter *PartitionFilter, namespace string, setName string, binNames ...string) (*Recordset, error) {
+	if policy == nil {
+		if clnt.DefaultScanPolicy != nil {
+			policy = clnt.DefaultScanPolicy
+		} else {
+			policy = NewScanPolicy()
+		}
+	}
+
+	if err := partitionFilter.validate(); err != nil {
+		return nil, err
+	}
+
+	// group the partitions which are not done yet by their master node
+	partitions := clnt.cluster.getPartitions()[namespace]
+	if partitions == nil {
+		return nil, NewAerospikeError(INVALID_NAMESPACE, fmt.Sprintf("Namespace `%s` not found in the partition map", namespace))
+	}
+
+	nodePartitions := map[*Node][]int{}
+	nodes := []*Node{}
+	for id := partitionFilter.Begin; id < partitionFilter.Begin+partitionFilter.Count; id++ {
+		if partitionFilter.IsDone(id) {
+			continue
+		}
+
+		node := partitions[id]
+		if node == nil || !node.IsActive() {
+			return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, fmt.Sprintf("No active node for partition %d", id))
+		}
+
+		if !node.supportsPartitionScan {
+			return nil, NewAerospikeError(UNSUPPORTED_FEATURE, fmt.Sprintf("Node %s does not support partition scans", node.String()))
+		}
+
+		if _, exists := nodePartitions[node]; !exists {
+			nodes = append(nodes, node)
+		}
+		nodePartitions[node] = append(nodePartitions[node], id)
+	}
+
+	res := NewRecordset(policy.RecordQueueSize)
+	ctx := res.totalTimeoutContext(policy.TotalTimeout)
+
+	// Retry policy must be one-shot for scans.
+	// copy on write for policy
+	newPolicy := *policy
+
+	recChans := []chan *Record{}
+	errChans := []chan error{}
+	for _, node := range nodes {
+		recChan := make(chan *Record, policy.RecordQueueSize)
+		errChan := make(chan error, policy.RecordQueueSize)
+
+		command := newScanCommand(node, &newPolicy, namespace, setName, binNames, recChan, errChan)
+		command.partitions = nodePartitions[node]
+		command.filter = partitionFilter
+		command.ctx = ctx
+
+		recChans = append(recChans, recChan)
+		errChans = append(errChans, errChan)
+		res.commands = append(res.commands, command)
+		go command.Execute()
+	}
+
+	res.chans = recChans
+	res.errs = errChans
+	res.Records, res.Errors = clnt.mergeResultChannels(policy.RecordQueueSize, recChans, errChans, res.releaseTotalTimeout)
 
 	return res, nil
 }
@@ -728,6 +1675,7 @@ func (clnt *Client) RegisterUDF(policy *WritePolicy, udfBody []byte, serverPath
 	}
 
 	if _, exists := res["error"]; exists {
+		node.PutConnection(conn)
 		return nil, NewAerospikeError(COMMAND_REJECTED, fmt.Sprintf("Registration failed: %s\nFile: %s\nLine: %s\nMessage: %s",
 			res["error"], res["file"], res["line"], res["message"]))
 	}
@@ -782,6 +1730,8 @@ func (clnt *Client) RemoveUDF(policy *WritePolicy, udfName string) (*RemoveTask,
 		}
 	}
 
+	node.PutConnection(conn)
+
 	if response == "ok" {
 		return NewRemoveTask(clnt.cluster, udfName), nil
 	}
@@ -868,6 +1818,12 @@ func (clnt *Client) ListUDF(policy *BasePolicy) ([]*UDF, error) {
 // This method is only supported by Aerospike 3 servers.
 // If the policy is nil, a default policy will be generated.
 func (clnt *Client) Execute(policy *WritePolicy, key *Key, packageName string, functionName string, args ...Value) (interface{}, error) {
+	return clnt.ExecuteWithContext(context.Background(), policy, key, packageName, functionName, args...)
+}
+
+// ExecuteWithContext works the same as Execute, but aborts the command
+// as soon as ctx is cancelled or its deadline passes.
+func (clnt *Client) ExecuteWithContext(ctx context.Context, policy *WritePolicy, key *Key, packageName string, functionName string, args ...Value) (interface{}, error) {
 	if policy == nil {
 		if clnt.DefaultWritePolicy != nil {
 			policy = clnt.DefaultWritePolicy
@@ -876,6 +1832,7 @@ func (clnt *Client) Execute(policy *WritePolicy, key *Key, packageName string, f
 		}
 	}
 	command := newExecuteCommand(clnt.cluster, policy, key, packageName, functionName, args)
+	command.ctx = ctx
 	if err := command.Execute(); err != nil {
 		return nil, err
 	}
@@ -894,12 +1851,108 @@ func (clnt *Client) Execute(policy *WritePolicy, key *Key, packageName string, f
 	}
 
 	if _, obj := mapContainsKeyPartial(resultMap, "FAILURE"); obj != nil {
-		return nil, fmt.Errorf("%v", obj)
+		return nil, NewAerospikeError(UDF_BAD_RESPONSE, fmt.Sprintf("%v", obj))
 	}
 
 	return nil, NewAerospikeError(UDF_BAD_RESPONSE, "Invalid UDF return value")
 }
 
+// the UDF module used to read the device size of records
+const (
+	_RECORD_SIZE_PACKAGE  = "as_record_size"
+	_RECORD_SIZE_FUNCTION = "device_size"
+	_RECORD_SIZE_UDF      = `
+function device_size(rec)
+  if not aerospike:exists(rec) then
+    return nil
+  end
+  return record.device_size(rec)
+end
+`
+)
+
+// RecordSize returns the size of the record on the storage device, including the
+// overhead of the record, as reported by the server.
+// The size is read by a UDF, which is registered on the cluster on first use.
+// Records of namespaces stored only in memory have no device size.
+// An UNSUPPORTED_FEATURE error is returned if the server can not report the size.
+// If the policy is nil, the default relevant policy will be used.
+func (clnt *Client) RecordSize(policy *WritePolicy, key *Key) (int, error) {
+	if policy == nil {
+		if clnt.DefaultWritePolicy != nil {
+			policy = clnt.DefaultWritePolicy
+		} else {
+			policy = NewWritePolicy(0, 0)
+		}
+	}
+
+	if err := clnt.registerUDFIfAbsent(policy, []byte(_RECORD_SIZE_UDF), _RECORD_SIZE_PACKAGE+".lua", LUA); err != nil {
+		return 0, err
+	}
+
+	res, err := clnt.Execute(policy, key, _RECORD_SIZE_PACKAGE, _RECORD_SIZE_FUNCTION)
+	if err != nil {
+		if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == UDF_BAD_RESPONSE {
+			return 0, NewAerospikeError(UNSUPPORTED_FEATURE, "Record size is not supported by the server: "+ae.Error())
+		}
+		return 0, err
+	}
+
+	switch size := res.(type) {
+	case nil:
+		return 0, NewAerospikeError(KEY_NOT_FOUND_ERROR)
+	case int:
+		return size, nil
+	case int64:
+		return int(size), nil
+	}
+	return 0, NewAerospikeError(UNSUPPORTED_FEATURE, fmt.Sprintf("Record size is not supported by the server: unexpected result %v", res))
+}
+
+// registerUDFIfAbsent registers the UDF module on the cluster, unless a module
+// with the same content is already registered at serverPath, and waits until
+// the registration is complete. Modules are checked once per client and content.
+func (clnt *Client) registerUDFIfAbsent(policy *WritePolicy, udfBody []byte, serverPath string, language Language) error {
+	hash := fmt.Sprintf("%x", sha1.Sum(udfBody))
+	cacheKey := serverPath + ":" + hash
+
+	clnt.udfMutex.Lock()
+	defer clnt.udfMutex.Unlock()
+
+	if clnt.registeredUDFs[cacheKey] {
+		return nil
+	}
+
+	udfs, err := clnt.ListUDF(nil)
+	if err != nil {
+		return err
+	}
+
+	registered := false
+	for _, udf := range udfs {
+		if udf.Filename == serverPath && udf.Hash == hash {
+			registered = true
+			break
+		}
+	}
+
+	if !registered {
+		task, err := clnt.RegisterUDF(policy, udfBody, serverPath, language)
+		if err != nil {
+			return err
+		}
+		if err := <-task.OnComplete(); err != nil {
+			return err
+		}
+	}
+
+	if clnt.registeredUDFs == nil {
+		clnt.registeredUDFs = map[string]bool{}
+	}
+	clnt.registeredUDFs[cacheKey] = true
+	return nil
+}
+
 func mapContainsKeyPartial(theMap map[string]interface{}, key string) (bool, interface{}) {
 	for k, v := range theMap {
 		if strings.Index(k, key) >= 0 {
@@ -960,21 +2013,148 @@ func (clnt *Client) ExecuteUDF(policy *QueryPolicy,
 		}
 	}
 
-	return NewExecuteTask(clnt.cluster, statement), mergeErrors(errs)
+	return NewExecuteTask(clnt.cluster, statement), mergeErrors(errs)
+}
+
+// ExecuteUDFOnScan applies user defined function on all records in the namespace
+// and set specified by the statement, in a background scan on all nodes.
+// The statement must not have any filters.
+// This method is only supported by Aerospike 3 servers.
+// The returned ExecuteTask can be used to poll the server for the scan job completion.
+func (clnt *Client) ExecuteUDFOnScan(policy *ScanPolicy,
+	statement *Statement,
+	packageName string,
+	functionName string,
+	functionArgs ...Value,
+) (*ExecuteTask, error) {
+	if policy == nil {
+		if clnt.DefaultScanPolicy != nil {
+			policy = clnt.DefaultScanPolicy
+		} else {
+			policy = NewScanPolicy()
+		}
+	}
+
+	if !statement.IsScan() {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "ExecuteUDFOnScan does not support statements with filters.")
+	}
+
+	// Always set a taskId
+	if statement.TaskId == 0 {
+		statement.TaskId = time.Now().UnixNano()
+	}
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "ExecuteUDFOnScan failed because cluster is empty.")
+	}
+
+	if policy.WaitUntilMigrationsAreOver {
+		// wait until all migrations are finished
+		if err := clnt.cluster.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	statement.SetAggregateFunction(packageName, functionName, functionArgs, false)
+
+	errs := []error{}
+	for i := range nodes {
+		command := newServerScanCommand(nodes[i], policy, statement)
+		if err := command.Execute(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return NewExecuteTask(clnt.cluster, statement), mergeErrors(errs)
+}
+
+//--------------------------------------------------------
+// Query functions (Supported by Aerospike 3 servers only)
+//--------------------------------------------------------
+
+// Query executes a query and returns a recordset.
+// The query executor puts records on a channel from separate goroutines.
+// The caller can concurrently pops records off the channel through the
+// record channel.
+//
+// This method is only supported by Aerospike 3 servers.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) Query(policy *QueryPolicy, statement *Statement) (*Recordset, error) {
+	if policy == nil {
+		if clnt.DefaultQueryPolicy != nil {
+			policy = clnt.DefaultQueryPolicy
+		} else {
+			policy = NewQueryPolicy()
+		}
+	}
+
+	// Always set a taskId
+	if statement.TaskId == 0 {
+		statement.TaskId = time.Now().UnixNano()
+	}
+
+	nodes := clnt.cluster.GetNodes()
+	if len(nodes) == 0 {
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Query failed because cluster is empty.")
+	}
+
+	if policy.WaitUntilMigrationsAreOver {
+		// wait until all migrations are finished
+		if err := clnt.cluster.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	if policy.FailOnPredExpUnsupported && len(statement.predExps) > 0 {
+		for _, node := range nodes {
+			if !node.supportsPredExp {
+				return nil, NewAerospikeError(UNSUPPORTED_FEATURE, "Node "+node.String()+" does not support predicate expressions. Aerospike server 3.12 or later is required.")
+			}
+		}
+	}
+
+	if policy.MaxRecords > 0 || statement.partitionFilter != nil {
+		return clnt.queryPartitions(policy, statement)
+	}
+
+	// results channel must be async for performance
+	recSet := NewRecordset(policy.RecordQueueSize)
+	ctx := recSet.totalTimeoutContext(policy.TotalTimeout)
+
+	// results channel must be async for performance
+	recChans := []chan *Record{}
+	errChans := []chan error{}
+	recCmds := []multiCommand{}
+	for _, node := range nodes {
+		recChan := make(chan *Record, policy.RecordQueueSize)
+		errChan := make(chan error, policy.RecordQueueSize)
+
+		// copy policies to avoid race conditions
+		newPolicy := *policy
+		command := newQueryRecordCommand(node, &newPolicy, statement, recChan, errChan)
+		command.ctx = ctx
+		recCmds = append(recCmds, command)
+		go command.Execute()
+
+		recChans = append(recChans, recChan)
+		errChans = append(errChans, errChan)
+	}
+
+	recSet.commands = recCmds
+	recSet.chans = recChans
+	recSet.errs = errChans
+	recSet.Records, recSet.Errors = clnt.mergeResultChannels(policy.RecordQueueSize, recChans, errChans, recSet.releaseTotalTimeout)
+
+	return recSet, nil
 }
 
-//--------------------------------------------------------
-// Query functions (Supported by Aerospike 3 servers only)
-//--------------------------------------------------------
-
-// Query executes a query and returns a recordset.
-// The query executor puts records on a channel from separate goroutines.
-// The caller can concurrently pops records off the channel through the
-// record channel.
-//
+// QueryNode executes a query on one node only, e.g. to diagnose the distribution
+// of the data between the nodes. The recordset only returns the records of the node.
+// Paginated queries, with MaxRecords or a partition filter, are not supported.
 // This method is only supported by Aerospike 3 servers.
 // If the policy is nil, a default policy will be generated.
-func (clnt *Client) Query(policy *QueryPolicy, statement *Statement) (*Recordset, error) {
+func (clnt *Client) QueryNode(policy *QueryPolicy, node *Node, statement *Statement) (*Recordset, error) {
 	if policy == nil {
 		if clnt.DefaultQueryPolicy != nil {
 			policy = clnt.DefaultQueryPolicy
@@ -983,71 +2163,304 @@ func (clnt *Client) Query(policy *QueryPolicy, statement *Statement) (*Recordset
 		}
 	}
 
+	if node == nil {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Query node is nil.")
+	}
+
+	if policy.MaxRecords > 0 || statement.partitionFilter != nil {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Paginated queries can not be sent to a single node")
+	}
+
 	// Always set a taskId
 	if statement.TaskId == 0 {
 		statement.TaskId = time.Now().UnixNano()
 	}
 
-	nodes := clnt.cluster.GetNodes()
-	if len(nodes) == 0 {
-		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, "Query failed because cluster is empty.")
-	}
-
 	if policy.WaitUntilMigrationsAreOver {
-		// wait until all migrations are finished
-		if err := clnt.cluster.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
+		// wait until migrations on node are finished
+		if err := node.WaitUntillMigrationIsFinished(policy.Timeout); err != nil {
 			return nil, err
 		}
 	}
 
+	if policy.FailOnPredExpUnsupported && len(statement.predExps) > 0 && !node.supportsPredExp {
+		return nil, NewAerospikeError(UNSUPPORTED_FEATURE, "Node "+node.String()+" does not support predicate expressions. Aerospike server 3.12 or later is required.")
+	}
+
 	// results channel must be async for performance
 	recSet := NewRecordset(policy.RecordQueueSize)
+	ctx := recSet.totalTimeoutContext(policy.TotalTimeout)
+
+	// copy policies to avoid race conditions
+	newPolicy := *policy
+	command := newQueryRecordCommand(node, &newPolicy, statement, recSet.Records, recSet.Errors)
+	command.ctx = ctx
+	recSet.commands = append(recSet.commands, command)
+	go func() {
+		command.Execute()
+		recSet.releaseTotalTimeout()
+	}()
+
+	return recSet, nil
+}
+
+// queryPartitions executes a paginated query. Each node is sent the partitions
+// it holds which have not been completed yet, and returns its share of at most
+// policy.MaxRecords records. The progress of each partition is recorded in the
+// filter of the recordset, from which the continuation token is created.
+func (clnt *Client) queryPartitions(policy *QueryPolicy, statement *Statement) (*Recordset, error) {
+	if statement.functionName != "" {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "Aggregation queries can not be paginated")
+	}
+
+	filter := NewPartitionFilterAll()
+	if statement.partitionFilter != nil {
+		if filter = statement.partitionFilter.Remaining(); filter == nil {
+			return nil, NewAerospikeError(PARAMETER_ERROR, "All the partitions of the query have been completed")
+		}
+	}
+
+	// group the partitions which are not done yet by their master node
+	partitions := clnt.cluster.getPartitions()[statement.Namespace]
+	if partitions == nil {
+		return nil, NewAerospikeError(INVALID_NAMESPACE, fmt.Sprintf("Namespace `%s` not found in the partition map", statement.Namespace))
+	}
+
+	nodePartitions := map[*Node][]int{}
+	nodes := []*Node{}
+	for id := filter.Begin; id < filter.Begin+filter.Count; id++ {
+		if filter.IsDone(id) {
+			continue
+		}
+
+		node := partitions[id]
+		if node == nil || !node.IsActive() {
+			return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, fmt.Sprintf("No active node for partition %d", id))
+		}
+
+		// queries without filters are scans
+		if (statement.IsScan() && !node.supportsPartitionScan) || (!statement.IsScan() && !node.supportsPQuery) {
+			return nil, NewAerospikeError(UNSUPPORTED_FEATURE, fmt.Sprintf("Node %s does not support partition queries", node.String()))
+		}
+
+		if _, exists := nodePartitions[node]; !exists {
+			nodes = append(nodes, node)
+		}
+		nodePartitions[node] = append(nodePartitions[node], id)
+	}
+
+	recSet := NewRecordset(policy.RecordQueueSize)
+	recSet.filter = filter
+	ctx := recSet.totalTimeoutContext(policy.TotalTimeout)
 
-	// results channel must be async for performance
 	recChans := []chan *Record{}
 	errChans := []chan error{}
-	recCmds := []multiCommand{}
-	for _, node := range nodes {
+	for i, node := range nodes {
+		// split the records of the page between the nodes;
+		// nodes without a share are not queried for this page
+		var maxRecords int64
+		if policy.MaxRecords > 0 {
+			maxRecords = policy.MaxRecords / int64(len(nodes))
+			if int64(i) < policy.MaxRecords%int64(len(nodes)) {
+				maxRecords++
+			}
+			if maxRecords == 0 {
+				continue
+			}
+		}
+
 		recChan := make(chan *Record, policy.RecordQueueSize)
 		errChan := make(chan error, policy.RecordQueueSize)
 
 		// copy policies to avoid race conditions
 		newPolicy := *policy
 		command := newQueryRecordCommand(node, &newPolicy, statement, recChan, errChan)
-		recCmds = append(recCmds, command)
+		command.partitions = nodePartitions[node]
+		command.filter = filter
+		command.maxRecords = maxRecords
+		command.ctx = ctx
+		recSet.commands = append(recSet.commands, command)
 		go command.Execute()
 
 		recChans = append(recChans, recChan)
 		errChans = append(errChans, errChan)
 	}
 
-	recSet.commands = recCmds
 	recSet.chans = recChans
 	recSet.errs = errChans
-	recSet.Records, recSet.Errors = clnt.mergeResultChannels(policy.RecordQueueSize, recChans, errChans)
+	recSet.Records, recSet.Errors = clnt.mergeResultChannels(policy.RecordQueueSize, recChans, errChans, recSet.releaseTotalTimeout)
 
 	return recSet, nil
 }
 
-// // Execute query, apply statement's aggregation function, and return result iterator. The query
-// // executor puts results on a channel in separate goroutines.  The calling goroutine concurrently pops
-// // results off the queue through the result iterator.
-// //
-// // The aggregation function is called on both server and client (reduce).  Therefore,
-// // the Lua script files must also reside on both server and client.
-// // The package name is used to locate the udf file location:
-// // <p>
-// // udf file = <udf dir>/<package name>.lua
-// // <p>
-// // This method is only supported by Aerospike 3 servers.
-// func (clnt *Client) QueryAggregate(
-// 	policy QueryPolicy,
-// 	statement Statement,
-// 	packageName string,
-// 	functionName string,
-// 	functionArgs ...Value,
-// ) (ResultSet, error) {
-// }
+// QueryAggregate executes a query and applies the aggregation function of the package
+// to the query results on each server node. The value reduced on each node is sent
+// on the Results channel of the returned ResultSet, in separate goroutines.
+// The client does not run the final reduce phase of the aggregation, so the caller
+// must combine the values of the nodes, e.g. add up the counts of each node.
+// Aggregation errors reported by the server are sent on the Errors channel.
+// The statement is not modified.
+// This method is only supported by Aerospike 3 servers.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) QueryAggregate(policy *QueryPolicy,
+	statement *Statement,
+	packageName string,
+	functionName string,
+	functionArgs ...Value,
+) (*ResultSet, error) {
+	stmt := *statement
+	stmt.SetAggregateFunction(packageName, functionName, functionArgs, true)
+
+	recordset, err := clnt.Query(policy, &stmt)
+	if err != nil {
+		return nil, err
+	}
+	return newResultSet(recordset), nil
+}
+
+// QueryAggregateInto works like QueryAggregate, and so returns one partial result per node,
+// but decodes the value reduced on each node into the element type of resChan using
+// the same rules as GetObject, and sends it on resChan, e.g. a chan *MyStruct for
+// aggregations which return a map.
+// A list result is decoded and sent element by element, unless resChan is a channel
+// of slices, arrays or interfaces, so that group-by aggregations returning a list
+// of maps can be consumed as a stream of structs.
+// resChan is closed when the query is finished.
+// Query errors, and errors for results that could not be decoded, are sent on the
+// returned errors channel. It must be consumed along with resChan, and is closed
+// when the query is finished. To stop the query early, use
+// QueryAggregateIntoWithContext and cancel its context.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) QueryAggregateInto(policy *QueryPolicy,
+	statement *Statement,
+	resChan interface{},
+	packageName string,
+	functionName string,
+	functionArgs ...Value,
+) (<-chan error, error) {
+	return clnt.QueryAggregateIntoWithContext(context.Background(), policy, statement, resChan, packageName, functionName, functionArgs...)
+}
+
+// QueryAggregateIntoWithContext works like QueryAggregateInto, but stops the query
+// once ctx is done. A COMMAND_CANCELLED error, or a TIMEOUT error if the deadline of
+// ctx was exceeded, is then sent on the errors channel if it has room, and both
+// channels are closed, even if the caller has stopped consuming resChan.
+func (clnt *Client) QueryAggregateIntoWithContext(ctx context.Context,
+	policy *QueryPolicy,
+	statement *Statement,
+	resChan interface{},
+	packageName string,
+	functionName string,
+	functionArgs ...Value,
+) (<-chan error, error) {
+	chanValue := reflect.ValueOf(resChan)
+	if chanValue.Kind() != reflect.Chan || chanValue.Type().ChanDir()&reflect.SendDir == 0 {
+		return nil, NewAerospikeError(PARAMETER_ERROR, "resChan must be a sendable channel")
+	}
+
+	resType := chanValue.Type().Elem()
+	splitLists := true
+	switch resType.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Interface:
+		splitLists = false
+	}
+
+	resultSet, err := clnt.QueryAggregate(policy, statement, packageName, functionName, functionArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	errChan := make(chan error, cap(resultSet.Errors))
+	go func() {
+		defer close(errChan)
+		defer chanValue.Close()
+
+		// send blocks until the value is received, or ctx is done
+		done := reflect.ValueOf(ctx.Done())
+		send := func(ch, value reflect.Value) bool {
+			chosen, _, _ := reflect.Select([]reflect.SelectCase{
+				{Dir: reflect.SelectSend, Chan: ch, Send: value},
+				{Dir: reflect.SelectRecv, Chan: done},
+			})
+			return chosen == 0
+		}
+		errValue := reflect.ValueOf(errChan)
+
+		results, errs := resultSet.Results, resultSet.Errors
+		for results != nil || errs != nil {
+			select {
+			case result, open := <-results:
+				if !open {
+					results = nil
+					continue
+				}
+
+				values := []interface{}{result}
+				if l, ok := result.([]interface{}); ok && splitLists {
+					values = l
+				}
+
+				for _, value := range values {
+					res := reflect.New(resType).Elem()
+					if err := unmarshalValue(res, value); err != nil {
+						if !send(errValue, reflect.ValueOf(&err).Elem()) {
+							break
+						}
+						continue
+					}
+					if !send(chanValue, res) {
+						break
+					}
+				}
+
+			case err, open := <-errs:
+				if !open {
+					errs = nil
+					continue
+				}
+				send(errValue, reflect.ValueOf(&err).Elem())
+
+			case <-ctx.Done():
+			}
+
+			if ctx.Err() != nil {
+				// stop the query, and discard the results the nodes have sent already
+				resultSet.Close()
+				go func() {
+					for range resultSet.Errors {
+					}
+				}()
+
+				select {
+				case errChan <- newContextError(ctx):
+				default:
+				}
+				return
+			}
+		}
+	}()
+
+	return errChan, nil
+}
+
+// QueryAggregateInline works like QueryAggregate, but takes the source of the Lua
+// module as well, e.g. a string embedded in the binary with go:embed.
+// The module is registered as packageName.lua, unless the same module is already
+// registered on the cluster; the check is only made once per client and module content.
+// The module is registered with the DefaultWritePolicy of the client.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) QueryAggregateInline(policy *QueryPolicy,
+	statement *Statement,
+	udfBody []byte,
+	packageName string,
+	functionName string,
+	functionArgs ...Value,
+) (*ResultSet, error) {
+	if err := clnt.registerUDFIfAbsent(nil, udfBody, packageName+".lua", LUA); err != nil {
+		return nil, err
+	}
+	return clnt.QueryAggregate(policy, statement, packageName, functionName, functionArgs...)
+}
 
 // CreateIndex creates a secondary index.
 // This asynchronous server call will return before the command is complete.
@@ -1062,6 +2475,25 @@ func (clnt *Client) CreateIndex(
 	indexName string,
 	binName string,
 	indexType IndexType,
+) (*IndexTask, error) {
+	return clnt.CreateComplexIndex(policy, namespace, setName, indexName, binName, indexType, ICT_DEFAULT)
+}
+
+// CreateComplexIndex creates a secondary index, with the ability to put indexes
+// on bin containing complex data types, e.g: Maps and Lists.
+// This asynchronous server call will return before the command is complete.
+// The user can optionally wait for command completion by using the returned
+// IndexTask instance.
+// This method is only supported by Aerospike 3 servers.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) CreateComplexIndex(
+	policy *WritePolicy,
+	namespace string,
+	setName string,
+	indexName string,
+	binName string,
+	indexType IndexType,
+	indexCollectionType IndexCollectionType,
 ) (*IndexTask, error) {
 	if policy == nil {
 		if clnt.DefaultWritePolicy != nil {
@@ -1082,6 +2514,12 @@ func (clnt *Client) CreateIndex(
 
 	_, err = strCmd.WriteString(";indexname=")
 	_, err = strCmd.WriteString(indexName)
+
+	if indexCollectionType != ICT_DEFAULT {
+		_, err = strCmd.WriteString(";indextype=")
+		_, err = strCmd.WriteString(ictToString(indexCollectionType))
+	}
+
 	_, err = strCmd.WriteString(";numbins=1")
 	_, err = strCmd.WriteString(";indexdata=")
 	_, err = strCmd.WriteString(binName)
@@ -1163,16 +2601,184 @@ func (clnt *Client) DropIndex(
 	return NewAerospikeError(INDEX_GENERIC, "Drop index failed: "+response)
 }
 
+// Truncate removes all records in the specified namespace and set.
+// If setName is empty, all records in the namespace are removed.
+// If beforeLastUpdate is not nil, only records last updated before that time are removed.
+// This asynchronous server call may return before the truncation is complete.
+// This method is only supported by Aerospike 3.12 servers and later.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) Truncate(policy *WritePolicy, namespace, setName string, beforeLastUpdate *time.Time) error {
+	if policy == nil {
+		if clnt.DefaultWritePolicy != nil {
+			policy = clnt.DefaultWritePolicy
+		} else {
+			policy = NewWritePolicy(0, 0)
+		}
+	}
+
+	node, err := clnt.cluster.GetRandomNode()
+	if err != nil {
+		return err
+	}
+
+	if !node.supportsTruncate {
+		return NewAerospikeError(UNSUPPORTED_FEATURE, "Node "+node.String()+" does not support truncate. Aerospike server 3.12 or later is required.")
+	}
+
+	var strCmd bytes.Buffer
+	strCmd.WriteString("truncate:namespace=")
+	strCmd.WriteString(namespace)
+
+	if len(setName) > 0 {
+		strCmd.WriteString(";set=")
+		strCmd.WriteString(setName)
+	}
+
+	if beforeLastUpdate != nil {
+		strCmd.WriteString(";lut=")
+		strCmd.WriteString(strconv.FormatInt(beforeLastUpdate.UnixNano(), 10))
+	}
+
+	// Send truncate command to one node. That node will distribute the command to other nodes.
+	responseMap, err := clnt.sendNodeInfoCommand(policy, node, strCmd.String())
+	if err != nil {
+		return err
+	}
+
+	response := ""
+	for _, v := range responseMap {
+		response = v
+	}
+
+	if strings.ToUpper(response) == "OK" {
+		return nil
+	}
+
+	return NewAerospikeError(SERVER_ERROR, "Truncate failed: "+response)
+}
+
+//-------------------------------------------------------
+// User administration
+//-------------------------------------------------------
+
+// CreateUser creates a new user with password and roles. Clear-text password will be hashed using bcrypt
+// before sending to server.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) CreateUser(policy *AdminPolicy, user string, password string, roles []string) error {
+	policy = clnt.getUsableAdminPolicy(policy)
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return newAdminCommand().createUser(clnt.cluster, policy, user, hash, roles)
+}
+
+// DropUser removes a user from the cluster.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) DropUser(policy *AdminPolicy, user string) error {
+	policy = clnt.getUsableAdminPolicy(policy)
+
+	return newAdminCommand().dropUser(clnt.cluster, policy, user)
+}
+
+// ChangePassword changes a user's password. Clear-text password will be hashed using bcrypt before sending to server.
+// When the user is the one the client authenticated with, the old password is sent along for verification,
+// and the client uses the new password for its new connections.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) ChangePassword(policy *AdminPolicy, user string, password string) error {
+	policy = clnt.getUsableAdminPolicy(policy)
+
+	clusterUser, oldPassword := clnt.cluster.credentials()
+	if clusterUser == "" {
+		return NewAerospikeError(INVALID_USER)
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	command := newAdminCommand()
+	if user == clusterUser {
+		// Change own password.
+		err = command.changePassword(clnt.cluster, policy, user, oldPassword, hash)
+	} else {
+		// Change other user's password by user admin.
+		err = command.setPassword(clnt.cluster, policy, user, hash)
+	}
+	if err != nil {
+		return err
+	}
+
+	clnt.cluster.changePassword(user, hash, password)
+	return nil
+}
+
+// GrantRoles adds roles to user's list of roles.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) GrantRoles(policy *AdminPolicy, user string, roles []string) error {
+	policy = clnt.getUsableAdminPolicy(policy)
+
+	return newAdminCommand().grantRoles(clnt.cluster, policy, user, roles)
+}
+
+// RevokeRoles removes roles from user's list of roles.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) RevokeRoles(policy *AdminPolicy, user string, roles []string) error {
+	policy = clnt.getUsableAdminPolicy(policy)
+
+	return newAdminCommand().revokeRoles(clnt.cluster, policy, user, roles)
+}
+
+// QueryUser retrieves roles for a given user.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) QueryUser(policy *AdminPolicy, user string) (*UserRoles, error) {
+	policy = clnt.getUsableAdminPolicy(policy)
+
+	list, err := newAdminCommand().queryUsers(clnt.cluster, policy, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, NewAerospikeError(INVALID_USER)
+	}
+	return list[0], nil
+}
+
+// QueryUsers retrieves all users and their roles.
+// If the policy is nil, a default policy will be generated.
+func (clnt *Client) QueryUsers(policy *AdminPolicy) ([]*UserRoles, error) {
+	policy = clnt.getUsableAdminPolicy(policy)
+
+	return newAdminCommand().queryUsers(clnt.cluster, policy, "")
+}
+
 //-------------------------------------------------------
 // Internal Methods
 //-------------------------------------------------------
 
+func (clnt *Client) getUsableAdminPolicy(policy *AdminPolicy) *AdminPolicy {
+	if policy == nil {
+		if clnt.DefaultAdminPolicy != nil {
+			return clnt.DefaultAdminPolicy
+		}
+		return NewAdminPolicy()
+	}
+	return policy
+}
+
 func (clnt *Client) sendInfoCommand(policy *WritePolicy, command string) (map[string]string, error) {
 	node, err := clnt.cluster.GetRandomNode()
 	if err != nil {
 		return nil, err
 	}
 
+	return clnt.sendNodeInfoCommand(policy, node, command)
+}
+
+func (clnt *Client) sendNodeInfoCommand(policy *WritePolicy, node *Node, command string) (map[string]string, error) {
 	conn, err := node.GetConnection(policy.Timeout)
 	if err != nil {
 		return nil, err
@@ -1197,7 +2803,8 @@ func (clnt *Client) sendInfoCommand(policy *WritePolicy, command string) (map[st
 // Utility Functions
 //-------------------------------------------------------
 
-// mergeErrors merges several errors into one
+// mergeErrors merges several errors into one,
+// with the result code of the first error
 func mergeErrors(errs []error) error {
 	if errs == nil || len(errs) == 0 {
 		return nil
@@ -1206,42 +2813,75 @@ func mergeErrors(errs []error) error {
 	for _, err := range errs {
 		msg.WriteString(err.Error() + "\n")
 	}
-	return errors.New(msg.String())
+
+	code := SERVER_ERROR
+	var ae AerospikeError
+	if errors.As(errs[0], &ae) {
+		code = ae.ResultCode()
+	}
+	return NewAerospikeError(code, msg.String())
 }
 
-// batchExecute Uses sync.WaitGroup to run commands using multiple goroutines,
-// and waits for their return
-func (clnt *Client) batchExecute(keys []*Key, cmdGen func(node *Node, bns *batchNamespace) command) error {
+// batchExecute runs the batch commands of each namespace on each node in separate
+// goroutines, with at most maxConcurrentNodes commands in parallel, or all of them
+// if it is zero, and waits for their return.
+// The namespaces whose commands failed are returned along with the merged error.
+func (clnt *Client) batchExecute(maxConcurrentNodes int, keys []*Key, cmdGen func(node *Node, bns *batchNamespace) command) ([]*batchNamespace, error) {
 
 	batchNodes, err := newBatchNodeList(clnt.cluster, keys)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var wg sync.WaitGroup
+	var mutex sync.Mutex
+
+	// limits the commands in flight; the other goroutines wait for a slot
+	var slots chan struct{}
+	if maxConcurrentNodes > 0 {
+		slots = make(chan struct{}, maxConcurrentNodes)
+	}
 
 	// Use a goroutine per namespace per node
 	errs := []error{}
-	wg.Add(len(batchNodes))
+	failed := []*batchNamespace{}
 	for _, batchNode := range batchNodes {
 		// copy to avoid race condition
 		bn := *batchNode
 		for _, bns := range bn.BatchNamespaces {
+			wg.Add(1)
 			go func(bn *Node, bns *batchNamespace) {
 				defer wg.Done()
-				command := cmdGen(bn, bns)
-				if err := command.Execute(); err != nil {
+
+				if slots != nil {
+					slots <- struct{}{}
+					defer func() { <-slots }()
+				}
+
+				var err error
+				if bn == nil {
+					err = NewAerospikeError(SERVER_NOT_AVAILABLE, "No node available for namespace "+*bns.namespace)
+				} else {
+					err = cmdGen(bn, bns).Execute()
+				}
+
+				if err != nil {
+					mutex.Lock()
 					errs = append(errs, err)
+					failed = append(failed, bns)
+					mutex.Unlock()
 				}
 			}(bn.Node, bns)
 		}
 	}
 
 	wg.Wait()
-	return mergeErrors(errs)
+	return failed, mergeErrors(errs)
 }
 
-func (clnt *Client) mergeResultChannels(size int, channels []chan *Record, errors []chan error) (chan *Record, chan error) {
+// mergeResultChannels merges the records and errors of the channels into a single
+// record and error channel. onClose, if not nil, is called before they are closed.
+func (clnt *Client) mergeResultChannels(size int, channels []chan *Record, errors []chan error, onClose func()) (chan *Record, chan error) {
 	var wg sync.WaitGroup
 	out := make(chan *Record, size)
 	outErr := make(chan error, size)
@@ -1277,6 +2917,9 @@ func (clnt *Client) mergeResultChannels(size int, channels []chan *Record, error
 	// done.  This must start after the wg.Add call.
 	go func() {
 		wg.Wait()
+		if onClose != nil {
+			onClose()
+		}
 		close(out)
 		close(outErr)
 	}()
@@ -0,0 +1,71 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ClientIfc is the interface of the record commands of Client.
+// Code which depends on it instead of *Client can be unit tested
+// with a fake implementation, without an Aerospike cluster.
+// Clients are still created with NewClient and its variants.
+type ClientIfc interface {
+	// Close closes all client connections to database server nodes.
+	Close()
+	// IsConnected determines if the client is ready to talk to the database server cluster.
+	IsConnected() bool
+
+	// Put writes record bin(s) to the server.
+	Put(policy *WritePolicy, key *Key, bins BinMap) error
+	// PutBins writes record bin(s) to the server.
+	PutBins(policy *WritePolicy, key *Key, bins ...*Bin) error
+	// PutObject writes the exported fields of a struct as record bins.
+	PutObject(policy *WritePolicy, key *Key, obj interface{}) error
+
+	// Get reads a record header and bins for specified key.
+	Get(policy *BasePolicy, key *Key, binNames ...string) (*Record, error)
+	// GetHeader reads a record generation and expiration only for specified key.
+	GetHeader(policy *BasePolicy, key *Key) (*Record, error)
+	// GetObject reads a record into the exported fields of a struct.
+	GetObject(policy *BasePolicy, key *Key, obj interface{}) error
+
+	// Delete deletes a record for specified key.
+	Delete(policy *WritePolicy, key *Key) (bool, error)
+	// Touch updates a record's metadata.
+	Touch(policy *WritePolicy, key *Key) error
+	// Exists determine if a record key exists.
+	Exists(policy *BasePolicy, key *Key) (bool, error)
+
+	// BatchExists determines if multiple record keys exist in one batch request.
+	BatchExists(policy *BatchPolicy, keys []*Key) ([]bool, error)
+	// BatchGet reads multiple record headers and bins for specified keys in one batch request.
+	BatchGet(policy *BasePolicy, keys []*Key, binNames ...string) ([]*Record, error)
+	// BatchGetWithPolicy works like BatchGet, but takes a BatchPolicy.
+	BatchGetWithPolicy(policy *BatchPolicy, keys []*Key, binNames ...string) ([]*Record, error)
+	// BatchGetHeader reads multiple record header data for specified keys in one batch request.
+	BatchGetHeader(policy *BasePolicy, keys []*Key) ([]*Record, error)
+	// BatchGetHeaderWithPolicy works like BatchGetHeader, but takes a BatchPolicy.
+	BatchGetHeaderWithPolicy(policy *BatchPolicy, keys []*Key) ([]*Record, error)
+
+	// Operate performs multiple read/write operations on a single key in one batch request.
+	Operate(policy *WritePolicy, key *Key, operations ...*Operation) (*Record, error)
+	// Execute executes a user defined function on server and return results.
+	Execute(policy *WritePolicy, key *Key, packageName string, functionName string, args ...Value) (interface{}, error)
+
+	// ScanAll reads all records in specified namespace and set from all nodes.
+	ScanAll(policy *ScanPolicy, namespace string, setName string, binNames ...string) (*Recordset, error)
+	// Query executes a query and returns a recordset.
+	Query(policy *QueryPolicy, statement *Statement) (*Recordset, error)
+}
+
+// guarantee Client implements ClientIfc interface
+var _ ClientIfc = &Client{}
@@ -15,6 +15,9 @@
 package aerospike
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
 	"time"
 )
 
@@ -29,6 +32,114 @@ type ClientPolicy struct {
 
 	// Throw exception if host connection fails during addHost().
 	FailIfNotConnected bool //= true
+
+	// ConnectionPingInterval determines how often idle pooled connections are checked.
+	// Connections that have not been used for this long are sent a "status" info
+	// command, and discarded if they fail to respond.
+	// Zero disables the check.
+	ConnectionPingInterval time.Duration //= 0
+
+	// SeedRefreshInterval determines how often the seed host names are resolved again.
+	// Addresses not yet known to the cluster are validated and added as new nodes,
+	// or as aliases of nodes already known by name.
+	// Zero disables the refresh; seeds are then only resolved when the cluster has no nodes.
+	SeedRefreshInterval time.Duration //= 0
+
+	// MaxSocketIdle determines how long a pooled connection may stay idle.
+	// Idle connections are closed and removed from the pool during cluster tend,
+	// so that the pool shrinks back after traffic spikes.
+	// Zero keeps idle connections open indefinitely.
+	MaxSocketIdle time.Duration //= 0
+
+	// MinConnectionsPerNode is the minimum number of connections to each node
+	// which are kept open when idle connections are closed.
+	MinConnectionsPerNode int //= 0
+
+	// RackAware directs the client to learn the rack membership of the nodes
+	// during cluster tend, so that reads with the PREFER_RACK replica policy
+	// can be sent to the nodes on the same rack as the client.
+	// Requires Aerospike server 4.0 or later.
+	RackAware bool //= false
+
+	// RackId is the rack of the client. Used when RackAware is true.
+	RackId int //= 0
+
+	// TlsConfig enables TLS connections to the server nodes when set.
+	// Server certificates are validated against the TLS name of each node.
+	TlsConfig *tls.Config //= nil
+
+	// SocketKeepAlive determines the period of the TCP keep-alive probes sent on
+	// the connections to the server nodes, so that dead peers and connections
+	// dropped by firewalls or NAT devices are detected.
+	// Zero uses the default period of the Go net package; a negative value
+	// disables keep-alive probes.
+	SocketKeepAlive time.Duration //= 0
+
+	// DisableTcpNoDelay clears the TCP_NODELAY option on the connections to the server
+	// nodes, so that small writes are coalesced (Nagle's algorithm). By default it is
+	// set, and small commands are sent without waiting.
+	DisableTcpNoDelay bool //= false
+
+	// Dialer, when set, opens the network connections to the server nodes instead of
+	// a net.Dialer, e.g. to connect through a SOCKS proxy, or to record connection metrics.
+	// The context expires after the connection timeout. With TlsConfig, the TLS session is
+	// established over the returned connection. SocketKeepAlive is not applied to the
+	// connections it returns; DisableTcpNoDelay is, if they are *net.TCPConn.
+	Dialer func(ctx context.Context, network, address string) (net.Conn, error) //= nil
+
+	// User authentication to cluster. Leave empty for clusters running without restricted access.
+	User string
+
+	// Password authentication to cluster. The password will be stored by the client and resent
+	// on reconnection. It is sent to the server hashed; with external authentication
+	// it is sent in clear text as well.
+	Password string
+
+	// AuthMode determines how the user is authenticated. External authentication
+	// requires TlsConfig, unless AuthModeExternalInsecure is used.
+	AuthMode AuthMode //= AuthModeInternal
+
+	// UseServicesAlternate directs the client to discover the peers of the nodes from
+	// their alternate access addresses (services-alternate, or peers-tls-alt with TLS),
+	// instead of the addresses the nodes use among themselves.
+	// Use it when the client is outside the network of the cluster, and the
+	// servers are configured with alternate-access-address.
+	UseServicesAlternate bool //= false
+
+	// SeedOnly directs the client to only connect to the nodes reached through the
+	// seed hosts. The peers advertised by the nodes are ignored, and nodes are not
+	// removed because other nodes do not reference them.
+	// Use it when only the seed addresses, e.g. those of a proxy or a load balancer
+	// per node, are routable from the client. Records on nodes which are not reached
+	// through a seed can not be accessed.
+	SeedOnly bool //= false
+
+	// MaxAllowedTTL, when set, is the maximum expiration in seconds the client accepts
+	// for writes. Put, Touch, Operate and Execute commands whose WritePolicy.Expiration
+	// exceeds it, or is negative other than TTLNeverExpire and TTLDontUpdate,
+	// fail with a PARAMETER_ERROR before they are sent to the server.
+	// Zero disables the check.
+	MaxAllowedTTL uint32 //= 0
+
+	// DisableBinNameValidation turns off the client side check of the length of the
+	// bin names written by Put commands, and used by Operate and OperateBatch operations.
+	// Names longer than 15 bytes are otherwise rejected with a BIN_NAME_TOO_LONG error
+	// before the command is sent.
+	// Set it if a future server accepts longer names.
+	DisableBinNameValidation bool //= false
+
+	// DefaultPolicy, DefaultWritePolicy, DefaultBatchPolicy, DefaultScanPolicy,
+	// DefaultQueryPolicy and DefaultAdminPolicy, when set, become the default
+	// policies of the client, which are used by the commands a nil policy is passed to.
+	// The client uses the policy objects themselves, so they should not be shared
+	// with other clients which are meant to have different defaults.
+	// Policies left nil are generated with default values.
+	DefaultPolicy      *BasePolicy  //= nil
+	DefaultWritePolicy *WritePolicy //= nil
+	DefaultBatchPolicy *BatchPolicy //= nil
+	DefaultScanPolicy  *ScanPolicy  //= nil
+	DefaultQueryPolicy *QueryPolicy //= nil
+	DefaultAdminPolicy *AdminPolicy //= nil
 }
 
 // NewClientPolicy generates a new ClientPolicy with default values.
@@ -16,13 +16,19 @@ package aerospike_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"math"
 	"math/rand"
+	"net"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
 	. "github.com/aerospike/aerospike-client-go/utils/buffer"
 
 	. "github.com/onsi/ginkgo"
@@ -44,6 +50,486 @@ var _ = Describe("Aerospike", func() {
 			client.Close()
 			Expect(client.IsConnected()).To(BeFalse())
 		})
+
+		It("must wait until the client is connected", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(client.WaitUntilConnected(time.Second)).ToNot(HaveOccurred())
+
+			client.Close()
+			Expect(client.WaitUntilConnected(time.Second)).To(HaveOccurred())
+		})
+
+		It("must report the features advertised by the nodes", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			for _, node := range client.GetNodes() {
+				for _, feature := range node.Features() {
+					Expect(feature).ToNot(BeEmpty())
+					Expect(node.SupportsFeature(feature)).To(BeTrue())
+				}
+			}
+
+			features := client.GetNodes()[0].Features()
+			if len(features) > 0 && len(client.GetNodes()) == 1 {
+				Expect(client.SupportsFeature(features[0])).To(BeTrue())
+			}
+			Expect(client.SupportsFeature("no-such-feature")).To(BeFalse())
+		})
+
+		It("must wait until the partition map of the cluster is complete", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(client.WaitUntilReady(5 * time.Second)).ToNot(HaveOccurred())
+
+			client.Close()
+			err = client.WaitUntilReady(time.Second)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must send log messages to a custom logger", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			lgr := &testLogger{}
+			client.SetLogger(lgr)
+			defer client.SetLogger(nil)
+
+			// the cluster logs after each tend
+			Eventually(lgr.count, 3*time.Second).Should(BeNumerically(">", 0))
+		})
+
+		It("must not notify the node listener while the cluster is stable", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			var mutex sync.Mutex
+			changes := 0
+			client.SetNodeListener(func(event *NodeEvent) {
+				mutex.Lock()
+				changes += len(event.Added) + len(event.Removed)
+				mutex.Unlock()
+			})
+
+			Consistently(func() int {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return changes
+			}, 3*time.Second).Should(Equal(0))
+		})
+
+		It("must only connect to the nodes reached through the seeds in seed only mode", func() {
+			policy := NewClientPolicy()
+			policy.SeedOnly = true
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			Expect(len(client.GetNodes())).To(Equal(1))
+
+			// the node is not removed although no peer references it
+			Consistently(func() int { return len(client.GetNodes()) }, 3*time.Second).Should(Equal(1))
+		})
+
+		It("must use the default policies set in the client policy", func() {
+			policy := NewClientPolicy()
+			policy.DefaultWritePolicy = NewWritePolicy(0, 0)
+			policy.DefaultWritePolicy.SendKey = true
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			Expect(client.DefaultWritePolicy).To(BeIdenticalTo(policy.DefaultWritePolicy))
+			Expect(client.DefaultPolicy).ToNot(BeNil())
+			Expect(client.DefaultBatchPolicy).ToNot(BeNil())
+			Expect(client.DefaultScanPolicy).ToNot(BeNil())
+			Expect(client.DefaultQueryPolicy).ToNot(BeNil())
+			Expect(client.DefaultAdminPolicy).ToNot(BeNil())
+
+			// a nil policy uses the default policy of the client
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.PutBins(nil, key, NewBin("bin", 1))).ToNot(HaveOccurred())
+
+			client.DefaultPolicy.Timeout = 5 * time.Second
+			rec, err := client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins["bin"]).To(Equal(1))
+		})
+
+		It("must reject bin names longer than 15 bytes, unless disabled", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.PutBins(nil, key, NewBin("a_very_long_bin_name", 1))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(BIN_NAME_TOO_LONG))
+			Expect(err.Error()).To(ContainSubstring("a_very_long_bin_name"))
+
+			_, err = client.Operate(nil, key, AddOp(NewBin("a_very_long_bin_name", 1)))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(BIN_NAME_TOO_LONG))
+
+			Expect(client.PutBins(nil, key, NewBin("fifteen_bytes__", 1))).ToNot(HaveOccurred())
+
+			// the server rejects the name instead
+			policy := NewClientPolicy()
+			policy.DisableBinNameValidation = true
+			unchecked, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer unchecked.Close()
+
+			err = unchecked.PutBins(nil, key, NewBin("a_very_long_bin_name", 1))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).ToNot(ContainSubstring("a_very_long_bin_name"))
+		})
+
+		It("must reject writes with an expiration above MaxAllowedTTL", func() {
+			policy := NewClientPolicy()
+			policy.MaxAllowedTTL = 3600
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, expiration := range []int32{3601, -3} {
+				err = client.PutBins(NewWritePolicy(0, expiration), key, NewBin("bin", 1))
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+
+				_, err = client.Operate(NewWritePolicy(0, expiration), key, TouchOp())
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			}
+
+			exists, err := client.Exists(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+
+			for _, expiration := range []int32{3600, TTLServerDefault, TTLNeverExpire, TTLDontUpdate} {
+				Expect(client.PutBins(NewWritePolicy(0, expiration), key, NewBin("bin", 1))).ToNot(HaveOccurred())
+			}
+		})
+
+		It("must connect with custom keep-alive and TCP no-delay settings", func() {
+			policy := NewClientPolicy()
+			Expect(policy.DisableTcpNoDelay).To(BeFalse())
+
+			policy.SocketKeepAlive = 5 * time.Second
+			policy.DisableTcpNoDelay = true
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			err = client.PutBins(nil, key, NewBin("bin", 1))
+			Expect(err).ToNot(HaveOccurred())
+
+			rec, err := client.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins["bin"]).To(Equal(1))
+
+			policy.SocketKeepAlive = -1
+			client2, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client2.Close()
+			Expect(client2.IsConnected()).To(BeTrue())
+		})
+
+		It("must open the connections with the custom dialer", func() {
+			var dials int32
+			policy := NewClientPolicy()
+			policy.Dialer = func(ctx context.Context, network, address string) (net.Conn, error) {
+				atomic.AddInt32(&dials, 1)
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, address)
+			}
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.PutBins(nil, key, NewBin("bin", 1))).ToNot(HaveOccurred())
+			Expect(atomic.LoadInt32(&dials)).To(BeNumerically(">", 0))
+
+			policy.Dialer = func(ctx context.Context, network, address string) (net.Conn, error) {
+				return nil, errors.New("dial refused")
+			}
+			_, err = NewClientWithPolicy(policy, *host, *port)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must discover the nodes from their alternate access addresses", func() {
+			policy := NewClientPolicy()
+			policy.UseServicesAlternate = true
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			Expect(client.IsConnected()).To(BeTrue())
+		})
+
+		It("must not duplicate known nodes when seeds are resolved again", func() {
+			policy := NewClientPolicy()
+			policy.SeedRefreshInterval = time.Millisecond
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			nodeCount := len(client.GetNodes())
+
+			// let the cluster tend a few times
+			time.Sleep(3 * time.Second)
+			Expect(len(client.GetNodes())).To(Equal(nodeCount))
+		})
+
+		It("must report the command and connection counters of the nodes", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			before := client.Stats()
+			for i := 0; i < 10; i++ {
+				Expect(client.Put(nil, key, BinMap{"bin": i})).ToNot(HaveOccurred())
+			}
+			after := client.Stats()
+
+			Expect(len(after.Nodes)).To(Equal(len(client.GetNodes())))
+			Expect(after.Commands - before.Commands).To(BeNumerically(">=", 10))
+			Expect(after.ConnectionsPooled).To(BeNumerically(">", 0))
+			Expect(after.ConnectionsOpen).To(BeNumerically(">=", after.ConnectionsPooled))
+			Expect(after.AverageLatency).To(BeNumerically(">", 0))
+		})
+
+		It("must read large records with a socket timeout shorter than the transfer", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			value := []byte(randString(512 * 1024))
+			Expect(client.Put(nil, key, BinMap{"bin": value})).ToNot(HaveOccurred())
+
+			policy := NewPolicy()
+			policy.Timeout = 5 * time.Second
+			policy.SocketTimeout = 50 * time.Millisecond
+
+			rec, err := client.Get(policy, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins["bin"]).To(Equal(value))
+		})
+
+		It("must compute adaptive timeouts from the latency of the nodes", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			policy := NewPolicy()
+			policy.AdaptiveTimeoutMultiplier = 3
+			policy.MinAdaptiveTimeout = time.Millisecond
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.Put(nil, key, BinMap{"bin": 1})).ToNot(HaveOccurred())
+
+			for i := 0; i < 100; i++ {
+				_, err := client.Get(policy, key)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			// the node of the key has computed an adaptive timeout
+			adaptiveNodes := 0
+			for _, stats := range client.Stats().Nodes {
+				if stats.AdaptiveTimeout > 0 {
+					Expect(stats.P99Latency).To(BeNumerically(">", 0))
+					Expect(stats.AdaptiveTimeout).To(BeNumerically(">=", time.Millisecond))
+					Expect(stats.AdaptiveTimeout).To(BeNumerically(">=", stats.P99Latency))
+					adaptiveNodes++
+				}
+			}
+			Expect(adaptiveNodes).To(Equal(1))
+		})
+
+		It("must send info commands to all nodes and return the responses by node name", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			responses, err := client.RequestInfoAll(nil, "node", "build")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(responses)).To(Equal(len(client.GetNodes())))
+
+			for _, node := range client.GetNodes() {
+				info, exists := responses[node.GetName()]
+				Expect(exists).To(BeTrue())
+				Expect(info["node"]).To(Equal(node.GetName()))
+				Expect(info["build"]).ToNot(BeEmpty())
+
+				nodeInfo, err := node.RequestInfo("node")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(nodeInfo["node"]).To(Equal(node.GetName()))
+			}
+		})
+
+		It("must manage users, or fail clearly on servers without security", func() {
+			policy := NewClientPolicy()
+			policy.User = "admin"
+			policy.Password = "admin"
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			user := "user_" + randString(10)
+			err = client.CreateUser(nil, user, "password", []string{Read})
+			if err != nil {
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(SECURITY_NOT_ENABLED))
+				return
+			}
+			defer client.DropUser(nil, user)
+
+			Expect(client.GrantRoles(nil, user, []string{ReadWrite})).ToNot(HaveOccurred())
+			userRoles, err := client.QueryUser(nil, user)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(userRoles.User).To(Equal(user))
+			Expect(userRoles.Roles).To(ConsistOf(Read, ReadWrite))
+
+			Expect(client.RevokeRoles(nil, user, []string{Read})).ToNot(HaveOccurred())
+			userRoles, err = client.QueryUser(nil, user)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(userRoles.Roles).To(ConsistOf(ReadWrite))
+
+			Expect(client.ChangePassword(nil, user, "new password")).ToNot(HaveOccurred())
+
+			users, err := client.QueryUsers(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(users)).To(BeNumerically(">=", 2))
+
+			Expect(client.DropUser(nil, user)).ToNot(HaveOccurred())
+			_, err = client.QueryUser(nil, user)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must require TLS for external authentication", func() {
+			policy := NewClientPolicy()
+			policy.User = "admin"
+			policy.Password = "admin"
+			policy.AuthMode = AuthModeExternal
+			_, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must log in and reuse the session for new connections", func() {
+			policy := NewClientPolicy()
+			policy.User = "admin"
+			policy.Password = "admin"
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			// open several connections at once, which authenticate with the session
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					defer GinkgoRecover()
+					Expect(client.Put(nil, key, BinMap{"bin": i})).ToNot(HaveOccurred())
+				}(i)
+			}
+			wg.Wait()
+		})
+
+		It("must run record commands through the ClientIfc interface", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+
+			var ifc ClientIfc = client
+			defer ifc.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ifc.Put(nil, key, BinMap{"bin": 1})).ToNot(HaveOccurred())
+			rec, err := ifc.Get(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rec.Bins["bin"]).To(Equal(1))
+
+			existed, err := ifc.Delete(nil, key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(existed).To(BeTrue())
+		})
+
+		It("must close idle connections down to the minimum per node", func() {
+			policy := NewClientPolicy()
+			policy.MaxSocketIdle = 100 * time.Millisecond
+			policy.MinConnectionsPerNode = 1
+			client, err := NewClientWithPolicy(policy, *host, *port)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			// open several connections at once
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					client.Put(nil, key, BinMap{"bin": i})
+				}(i)
+			}
+			wg.Wait()
+
+			nodeCount := len(client.GetNodes())
+			Eventually(func() int { return client.Stats().ConnectionsOpen }, 5*time.Second).Should(BeNumerically("<=", nodeCount))
+		})
+
+		It("must wait for the commands in flight before closing the connections", func() {
+			client, err := NewClient(*host, *port)
+			Expect(err).ToNot(HaveOccurred())
+
+			key, err := NewKey("test", randString(50), randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					client.Put(nil, key, BinMap{"bin": i})
+				}(i)
+			}
+
+			// let the commands start
+			time.Sleep(time.Millisecond)
+			Expect(client.CloseWithTimeout(5 * time.Second)).ToNot(HaveOccurred())
+			Expect(client.IsConnected()).To(BeFalse())
+			Expect(client.Stats().CommandsInFlight).To(Equal(0))
+
+			wg.Wait()
+		})
 	})
 
 	Describe("Data operations on native types", func() {
@@ -66,6 +552,115 @@ var _ = Describe("Aerospike", func() {
 
 		Context("Put operations", func() {
 
+			Context("RecordExistsAction", func() {
+				It("must only create the record with CREATE_ONLY", func() {
+					policy := NewWritePolicy(0, 0)
+					policy.RecordExistsAction = CREATE_ONLY
+
+					err = client.PutBins(policy, key, NewBin("bin1", 1))
+					Expect(err).ToNot(HaveOccurred())
+
+					err = client.PutBins(policy, key, NewBin("bin1", 2))
+					Expect(err).To(HaveOccurred())
+					Expect(err.(AerospikeError).ResultCode()).To(Equal(KEY_EXISTS_ERROR))
+				})
+
+				It("must let the ShouldRetry callback decide which errors are retried", func() {
+					policy := NewWritePolicy(0, 0)
+					policy.RecordExistsAction = CREATE_ONLY
+					policy.SleepBetweenRetries = 0
+
+					attempts := []int{}
+					policy.ShouldRetry = func(attempt int, err error) bool {
+						attempts = append(attempts, attempt)
+						return IsResultCode(err, KEY_EXISTS_ERROR) && attempt < 3
+					}
+
+					err = client.PutBins(policy, key, NewBin("bin1", 1))
+					Expect(err).ToNot(HaveOccurred())
+					Expect(attempts).To(BeEmpty())
+
+					// the callback retries the server error, which is not retried by default, twice
+					err = client.PutBins(policy, key, NewBin("bin1", 2))
+					Expect(IsResultCode(err, KEY_EXISTS_ERROR)).To(BeTrue())
+					Expect(attempts).To(Equal([]int{1, 2, 3}))
+				})
+
+				It("must only update or replace existing records with UPDATE_ONLY and REPLACE_ONLY", func() {
+					for _, action := range []RecordExistsAction{UPDATE_ONLY, REPLACE_ONLY} {
+						policy := NewWritePolicy(0, 0)
+						policy.RecordExistsAction = action
+
+						err = client.PutBins(policy, key, NewBin("bin1", 1))
+						Expect(err).To(HaveOccurred())
+						Expect(IsKeyNotFound(err)).To(BeTrue())
+					}
+
+					err = client.PutBins(wpolicy, key, NewBin("bin1", 1), NewBin("bin2", 2))
+					Expect(err).ToNot(HaveOccurred())
+
+					policy := NewWritePolicy(0, 0)
+					policy.RecordExistsAction = UPDATE_ONLY
+					err = client.PutBins(policy, key, NewBin("bin1", 3))
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins).To(Equal(BinMap{"bin1": 3, "bin2": 2}))
+
+					policy.RecordExistsAction = REPLACE_ONLY
+					err = client.PutBins(policy, key, NewBin("bin1", 4))
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins).To(Equal(BinMap{"bin1": 4}))
+				})
+
+				It("must only write the record when its generation matches the GenerationPolicy", func() {
+					err = client.PutBins(wpolicy, key, NewBin("bin1", 1))
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+
+					policy := NewWritePolicy(int32(rec.Generation), 0)
+					policy.GenerationPolicy = EXPECT_GEN_EQUAL
+					err = client.PutBins(policy, key, NewBin("bin1", 2))
+					Expect(err).ToNot(HaveOccurred())
+
+					// the generation has changed since
+					err = client.PutBins(policy, key, NewBin("bin1", 3))
+					Expect(IsGenerationError(err)).To(BeTrue())
+
+					policy.GenerationPolicy = EXPECT_GEN_GT
+					err = client.PutBins(policy, key, NewBin("bin1", 3))
+					Expect(IsGenerationError(err)).To(BeTrue())
+
+					policy.Generation = int32(rec.Generation) + 10
+					err = client.PutBins(policy, key, NewBin("bin1", 3))
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins).To(Equal(BinMap{"bin1": 3}))
+				})
+
+				It("must drop the bins which are not written with REPLACE", func() {
+					err = client.PutBins(wpolicy, key, NewBin("bin1", 1), NewBin("bin2", 2))
+					Expect(err).ToNot(HaveOccurred())
+
+					policy := NewWritePolicy(0, 0)
+					policy.RecordExistsAction = REPLACE
+					err = client.PutBins(policy, key, NewBin("bin2", 3))
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins).To(Equal(BinMap{"bin2": 3}))
+				})
+			})
+
 			Context("Bins with `nil` values should be deleted", func() {
 				It("must save a key with SINGLE bin", func() {
 					bin := NewBin("Aerospike", "value")
@@ -138,6 +733,19 @@ var _ = Describe("Aerospike", func() {
 					_, exists = rec.Bins[bin3.Name]
 					Expect(exists).To(Equal(false))
 				})
+
+				It("must delete bins set to nil pointers in a BinMap", func() {
+					err = client.Put(wpolicy, key, BinMap{"Aerospike1": "value1", "Aerospike2": "value2"})
+					Expect(err).ToNot(HaveOccurred())
+
+					var nilString *string
+					err = client.Put(wpolicy, key, BinMap{"Aerospike2": nilString})
+					Expect(err).ToNot(HaveOccurred())
+
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins).To(Equal(BinMap{"Aerospike1": "value1"}))
+				})
 			})
 
 			Context("Bins with `string` values", func() {
@@ -285,25 +893,101 @@ var _ = Describe("Aerospike", func() {
 				})
 			})
 
-			Context("Bins with complex types", func() {
+			Context("Bins with `float32` and `float64` values", func() {
+				It("must save float bins without losing precision", func() {
+					err = client.Put(wpolicy, key, BinMap{
+						"float32": float32(1.5),
+						"float64": 0.1 + 0.2,
+						"max":     math.MaxFloat64,
+						"min":     -math.SmallestNonzeroFloat64,
+					})
+					Expect(err).ToNot(HaveOccurred())
 
-				Context("Bins with BLOB type", func() {
-					It("must save and retrieve Bins with AerospikeBlobs type", func() {
-						person := &testBLOB{name: "SomeDude"}
-						bin := NewBin("Aerospike1", person)
-						err = client.PutBins(wpolicy, key, bin)
-						Expect(err).ToNot(HaveOccurred())
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
 
-						rec, err = client.Get(rpolicy, key)
-						Expect(err).ToNot(HaveOccurred())
-					})
+					Expect(rec.Bins["float32"]).To(Equal(float64(1.5)))
+					Expect(rec.Bins["float64"]).To(Equal(0.1 + 0.2))
+					Expect(rec.Bins["max"]).To(Equal(math.MaxFloat64))
+					Expect(rec.Bins["min"]).To(Equal(-math.SmallestNonzeroFloat64))
 				})
+			})
 
-				Context("Bins with LIST type", func() {
+			Context("Bins read with typed getters", func() {
+				It("must normalize bin values and report their presence", func() {
+					err = client.Put(wpolicy, key, BinMap{
+						"int":    math.MaxInt64,
+						"int8":   int8(-5),
+						"string": "value",
+						"list":   []interface{}{1, "a", 1.5},
+					})
+					Expect(err).ToNot(HaveOccurred())
 
-					It("must save a key with Array Types", func() {
-						bin1 := NewBin("Aerospike1", []int8{math.MinInt8, 0, 1, 2, 3, math.MaxInt8})
-						bin2 := NewBin("Aerospike2", []int16{math.MinInt16, 0, 1, 2, 3, math.MaxInt16})
+					rec, err = client.Get(rpolicy, key)
+					Expect(err).ToNot(HaveOccurred())
+
+					i, ok := rec.GetInt("int")
+					Expect(ok).To(BeTrue())
+					Expect(i).To(Equal(int64(math.MaxInt64)))
+
+					i, ok = rec.GetInt("int8")
+					Expect(ok).To(BeTrue())
+					Expect(i).To(Equal(int64(-5)))
+
+					str, ok := rec.GetString("string")
+					Expect(ok).To(BeTrue())
+					Expect(str).To(Equal("value"))
+
+					list, ok := rec.GetList("list")
+					Expect(ok).To(BeTrue())
+					Expect(list).To(Equal([]interface{}{1, "a", 1.5}))
+
+					_, ok = rec.GetInt("string")
+					Expect(ok).To(BeFalse())
+
+					_, ok = rec.GetFloat("int")
+					Expect(ok).To(BeFalse())
+
+					_, ok = rec.GetString("missing")
+					Expect(ok).To(BeFalse())
+				})
+			})
+
+			Context("Bins with complex types", func() {
+
+				Context("Bins with BLOB type", func() {
+					It("must save and retrieve Bins with AerospikeBlobs type", func() {
+						person := &testBLOB{name: "SomeDude"}
+						bin := NewBin("Aerospike1", person)
+						err = client.PutBins(wpolicy, key, bin)
+						Expect(err).ToNot(HaveOccurred())
+
+						rec, err = client.Get(rpolicy, key)
+						Expect(err).ToNot(HaveOccurred())
+					})
+
+					It("must save and retrieve large blobs with compression enabled", func() {
+						blob := bytes.Repeat([]byte("aerospike"), 10000)
+
+						cwpolicy := NewWritePolicy(0, 0)
+						cwpolicy.UseCompression = true
+						bin := NewBin("Aerospike1", blob)
+						err = client.PutBins(cwpolicy, key, bin)
+						Expect(err).ToNot(HaveOccurred())
+
+						crpolicy := NewPolicy()
+						crpolicy.UseCompression = true
+						rec, err = client.Get(crpolicy, key)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(rec.Bins[bin.Name]).To(Equal(blob))
+					})
+				})
+
+				Context("Bins with LIST type", func() {
+
+					It("must save a key with Array Types", func() {
+						bin1 := NewBin("Aerospike1", []int8{math.MinInt8, 0, 1, 2, 3, math.MaxInt8})
+						bin2 := NewBin("Aerospike2", []int16{math.MinInt16, 0, 1, 2, 3, math.MaxInt16})
 						bin3 := NewBin("Aerospike3", []int32{math.MinInt32, 0, 1, 2, 3, math.MaxInt32})
 						bin4 := NewBin("Aerospike4", []int64{math.MinInt64, 0, 1, 2, 3, math.MaxInt64})
 						bin5 := NewBin("Aerospike5", []uint8{0, 1, 2, 3, math.MaxUint8})
@@ -402,6 +1086,28 @@ var _ = Describe("Aerospike", func() {
 
 			}) // context complex types
 
+			It("must save and retrieve a record using only its digest", func() {
+				wpolicy := NewWritePolicy(0, 0)
+				wpolicy.SendKey = true
+				bin := NewBin("Aerospike", randString(50))
+				err = client.PutBins(wpolicy, key, bin)
+				Expect(err).ToNot(HaveOccurred())
+
+				dkey, err := NewKeyWithDigest(key.Namespace(), key.SetName(), nil, key.Digest())
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, dkey)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject()))
+
+				err = client.PutBins(wpolicy, dkey, NewBin("Aerospike", "updated"))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin.Name]).To(Equal("updated"))
+			})
+
 		}) // put context
 
 		Context("Append operations", func() {
@@ -432,6 +1138,16 @@ var _ = Describe("Aerospike", func() {
 				Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject().(string) + appbin.Value.GetObject().(string)))
 			})
 
+			It("must append a string to a SINGLE bin", func() {
+				suffix := randString(rand.Intn(100))
+				err = client.AppendString(wpolicy, key, bin.Name, suffix)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject().(string) + suffix))
+			})
+
 		}) // append context
 
 		Context("Prepend operations", func() {
@@ -462,6 +1178,16 @@ var _ = Describe("Aerospike", func() {
 				Expect(rec.Bins[bin.Name]).To(Equal(appbin.Value.GetObject().(string) + bin.Value.GetObject().(string)))
 			})
 
+			It("must prepend a string to a SINGLE bin", func() {
+				prefix := randString(rand.Intn(100))
+				err = client.PrependString(wpolicy, key, bin.Name, prefix)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin.Name]).To(Equal(prefix + bin.Value.GetObject().(string)))
+			})
+
 		}) // prepend context
 
 		Context("Add operations", func() {
@@ -492,6 +1218,22 @@ var _ = Describe("Aerospike", func() {
 				Expect(rec.Bins[bin.Name]).To(Equal(addBin.Value.GetObject().(int) + bin.Value.GetObject().(int)))
 			})
 
+			It("must add an int64 delta to a SINGLE bin", func() {
+				err = client.AddInt(wpolicy, key, bin.Name, -10)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject().(int) - 10))
+
+				err = client.AddInt(wpolicy, key, "counter", 5)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["counter"]).To(Equal(5))
+			})
+
 		}) // add context
 
 		Context("Delete operations", func() {
@@ -524,8 +1266,93 @@ var _ = Describe("Aerospike", func() {
 				Expect(existed).To(Equal(false))
 			})
 
+			It("must Delete durably, or fail clearly on servers without durable deletes", func() {
+				policy := NewWritePolicy(0, 0)
+				policy.DurableDelete = true
+
+				existed, err := client.Delete(policy, key)
+				if err != nil {
+					// community edition servers do not support durable deletes
+					Expect(err.(AerospikeError).ResultCode()).To(Equal(ENTERPRISE_ONLY))
+					return
+				}
+				Expect(existed).To(Equal(true))
+
+				existed, err = client.Exists(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(existed).To(Equal(false))
+			})
+
+			It("must write and Delete records marked as XDR writes", func() {
+				policy := NewWritePolicy(0, 0)
+				policy.Xdr = true
+
+				err := client.PutBins(policy, key, NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err := client.Operate(policy, key, AddOp(NewBin("bin", 1)), GetOp())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["bin"]).To(Equal(2))
+
+				existed, err := client.Delete(policy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(existed).To(Equal(true))
+			})
+
 		}) // Delete context
 
+		Context("Filter expression operations", func() {
+			bin := NewBin("version", 4)
+
+			BeforeEach(func() {
+				err = client.PutBins(wpolicy, key, bin)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			versionIs := func(version int64) *WritePolicy {
+				policy := NewWritePolicy(0, 0)
+				policy.FilterExpression = []PredExp{
+					NewPredExpIntegerBin("version"),
+					NewPredExpIntegerValue(version),
+					NewPredExpIntegerEqual(),
+				}
+				return policy
+			}
+
+			It("must only Put when the filter expression is true", func() {
+				err = client.PutBins(versionIs(5), key, NewBin("version", 6))
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(FILTERED_OUT))
+
+				err = client.PutBins(versionIs(4), key, NewBin("version", 5))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["version"]).To(Equal(5))
+			})
+
+			It("must only Operate when the filter expression is true", func() {
+				_, err = client.Operate(versionIs(5), key, AddOp(NewBin("version", 1)))
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(FILTERED_OUT))
+
+				_, err = client.Operate(versionIs(4), key, AddOp(NewBin("version", 1)))
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("must only Delete when the filter expression is true", func() {
+				_, err = client.Delete(versionIs(5), key)
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(FILTERED_OUT))
+
+				existed, err := client.Delete(versionIs(4), key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(existed).To(BeTrue())
+			})
+
+		}) // filter expression context
+
 		Context("Touch operations", func() {
 			bin := NewBin("Aerospike", rand.Intn(math.MaxInt16))
 
@@ -541,6 +1368,8 @@ var _ = Describe("Aerospike", func() {
 
 				err = client.Touch(wpolicy, nxkey)
 				Expect(err).To(HaveOccurred())
+				Expect(IsKeyNotFound(err)).To(BeTrue())
+				Expect(IsTimeout(err)).To(BeFalse())
 			})
 
 			It("must Touch to an existing key", func() {
@@ -569,6 +1398,28 @@ var _ = Describe("Aerospike", func() {
 				}
 			})
 
+			It("must read the record and reset its expiration with GetAndTouch", func() {
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				generation := rec.Generation
+
+				rec, err = client.GetAndTouch(nil, key, 5000)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject()))
+				Expect(rec.Generation).To(Equal(generation + 1))
+				Expect(rec.Expiration).To(BeNumerically(">", 4900))
+
+				rec, err = client.GetAndTouch(nil, key, 6000, "no-such-bin")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(BeEmpty())
+				Expect(rec.Expiration).To(BeNumerically(">", 5900))
+
+				nxkey, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+				_, err = client.GetAndTouch(nil, nxkey, 5000)
+				Expect(IsKeyNotFound(err)).To(BeTrue())
+			})
+
 		}) // Touch context
 
 		Context("Exists operations", func() {
@@ -599,6 +1450,147 @@ var _ = Describe("Aerospike", func() {
 
 		}) // Exists context
 
+		Context("Context-aware operations", func() {
+			bin := NewBin("Aerospike", rand.Intn(math.MaxInt16))
+
+			It("must Put and Get with a live context", func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				err = client.PutBinsWithContext(ctx, wpolicy, key, bin)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.GetWithContext(ctx, rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject()))
+			})
+
+			It("must abort when the context is already cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err = client.GetWithContext(ctx, rpolicy, key)
+				Expect(err).To(HaveOccurred())
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+
+				err = client.PutBinsWithContext(ctx, wpolicy, key, bin)
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			})
+
+		}) // Context-aware context
+
+		Context("Object operations", func() {
+			type address struct {
+				City string `as:"city"`
+				Zip  int    `as:"zip"`
+			}
+
+			type person struct {
+				Name    string   `as:"name"`
+				Age     int      `as:"age"`
+				Tags    []string `as:"tags"`
+				Address address  `as:"addr"`
+				Ignored string   `as:"-"`
+				secret  string
+			}
+
+			type event struct {
+				Name string     `as:"name"`
+				At   time.Time  `as:"at"`
+				Seen *time.Time `as:"seen"`
+			}
+
+			It("must save a struct and read it back", func() {
+				obj := &person{
+					Name:    "Jane",
+					Age:     42,
+					Tags:    []string{"a", "b"},
+					Address: address{City: "Paris", Zip: 75001},
+					Ignored: "ignored",
+					secret:  "secret",
+				}
+
+				err = client.PutObject(wpolicy, key, obj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["addr"]).To(Equal(map[interface{}]interface{}{"city": "Paris", "zip": 75001}))
+				Expect(rec.Bins).ToNot(HaveKey("Ignored"))
+				Expect(rec.Bins).ToNot(HaveKey("secret"))
+
+				res := &person{}
+				err = client.GetObject(rpolicy, key, res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Name).To(Equal(obj.Name))
+				Expect(res.Age).To(Equal(obj.Age))
+				Expect(res.Tags).To(Equal(obj.Tags))
+				Expect(res.Address).To(Equal(obj.Address))
+				Expect(res.Ignored).To(BeEmpty())
+			})
+
+			It("must save float fields and read them back", func() {
+				type reading struct {
+					Sensor string  `as:"sensor"`
+					Value  float64 `as:"value"`
+					Ratio  float32 `as:"ratio"`
+				}
+
+				obj := &reading{Sensor: "t1", Value: 21.123456789012345, Ratio: 0.25}
+				err = client.PutObject(wpolicy, key, obj)
+				Expect(err).ToNot(HaveOccurred())
+
+				res := &reading{}
+				err = client.GetObject(rpolicy, key, res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(obj))
+			})
+
+			It("must save time.Time fields as nanoseconds and read them back", func() {
+				now := time.Now()
+				obj := &event{Name: "launch", At: now, Seen: &now}
+
+				err = client.PutObject(wpolicy, key, obj)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["at"]).To(Equal(int(now.UnixNano())))
+
+				res := &event{}
+				err = client.GetObject(rpolicy, key, res)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.At.Equal(now)).To(BeTrue())
+				Expect(res.Seen.Equal(now)).To(BeTrue())
+			})
+
+		}) // Object context
+
+		Context("Truncate operations", func() {
+
+			It("must remove all records in a set", func() {
+				set := randString(50)
+				keys := []*Key{}
+				for i := 0; i < 10; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					keys = append(keys, key)
+
+					err = client.PutBins(wpolicy, key, NewBin("Aerospike", i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				err = client.Truncate(nil, ns, set, nil)
+				Expect(err).ToNot(HaveOccurred())
+
+				Eventually(func() []bool {
+					exists, _ := client.BatchExists(nil, keys)
+					return exists
+				}, 5*time.Second).Should(Equal(make([]bool, len(keys))))
+			})
+
+		}) // Truncate context
+
 		Context("Batch Exists operations", func() {
 			bin := NewBin("Aerospike", rand.Intn(math.MaxInt16))
 			const keyCount = 2048
@@ -627,7 +1619,30 @@ var _ = Describe("Aerospike", func() {
 					}
 				}
 
-				exists, err = client.BatchExists(rpolicy, keys)
+				exists, err = client.BatchExists(NewBatchPolicy(), keys)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(exists)).To(Equal(len(keys)))
+				for idx, keyExists := range exists {
+					Expect(keyExists).To(Equal(idx%2 == 0))
+				}
+			})
+
+			It("must return the same results when the nodes are queried sequentially", func() {
+				keys := []*Key{}
+				for i := 0; i < 100; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					keys = append(keys, key)
+
+					if i%2 == 0 {
+						err = client.PutBins(wpolicy, key, bin)
+						Expect(err).ToNot(HaveOccurred())
+					}
+				}
+
+				policy := NewBatchPolicy()
+				policy.MaxConcurrentNodes = 1
+				exists, err := client.BatchExists(policy, keys)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(len(exists)).To(Equal(len(keys)))
 				for idx, keyExists := range exists {
@@ -684,7 +1699,10 @@ var _ = Describe("Aerospike", func() {
 					}
 				}
 
-				records, err = client.BatchGet(rpolicy, keys, bin.Name)
+				// query the nodes one at a time
+				serialPolicy := NewBatchPolicy()
+				serialPolicy.MaxConcurrentNodes = 1
+				records, err = client.BatchGetWithPolicy(serialPolicy, keys, bin.Name)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(len(records)).To(Equal(len(keys)))
 				for idx, rec := range records {
@@ -696,6 +1714,44 @@ var _ = Describe("Aerospike", func() {
 				}
 			})
 
+			It("must stream the records with their original keys as the nodes respond", func() {
+				keys := []*Key{}
+				shouldExist := map[*Key]bool{}
+
+				for i := 0; i < keyCount; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					keys = append(keys, key)
+					shouldExist[key] = rand.Intn(100) > 50
+
+					if shouldExist[key] {
+						err = client.PutBins(wpolicy, key, bin)
+						Expect(err).ToNot(HaveOccurred())
+					}
+				}
+
+				records, errs := client.BatchGetChannel(nil, keys)
+
+				count := 0
+				for res := range records {
+					count++
+					Expect(res.Err).ToNot(HaveOccurred())
+					Expect(shouldExist).To(HaveKey(res.Key))
+					if shouldExist[res.Key] {
+						Expect(res.ResultCode).To(Equal(OK))
+						Expect(res.Record.Bins[bin.Name]).To(Equal(bin.Value.GetObject()))
+					} else {
+						Expect(res.ResultCode).To(Equal(KEY_NOT_FOUND_ERROR))
+						Expect(res.Record).To(BeNil())
+					}
+				}
+				Expect(count).To(Equal(len(keys)))
+
+				for err := range errs {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			})
+
 		}) // Batch Get context
 
 		Context("GetHeader operations", func() {
@@ -720,36 +1776,125 @@ var _ = Describe("Aerospike", func() {
 				Expect(rec.Bins[bin.Name]).To(BeNil())
 			})
 
-		}) // GetHeader context
-
-		Context("Batch Get Header operations", func() {
-			bin := NewBin("Aerospike", rand.Int())
-			const keyCount = 1024
+			It("must Get the expiration of an existing key without its bins", func() {
+				err = client.PutBins(NewWritePolicy(0, 1000), key, bin)
+				Expect(err).ToNot(HaveOccurred())
 
-			BeforeEach(func() {
+				rec, err = client.GetHeader(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Expiration).To(BeNumerically(">", 0))
+				Expect(rec.Expiration).To(BeNumerically("<=", 1000))
+				Expect(len(rec.Bins)).To(Equal(0))
 			})
 
-			It("must return the records with same ordering as keys", func() {
-				var records []*Record
-				type existance struct {
-					key         *Key
-					shouldExist bool // set randomly and checked against later
-				}
+			It("must return the expiration as an absolute time", func() {
+				err = client.PutBins(NewWritePolicy(0, 1000), key, bin)
+				Expect(err).ToNot(HaveOccurred())
 
-				exList := []existance{}
-				keys := []*Key{}
+				rec, err = client.GetHeader(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.ExpirationTime()).To(BeTemporally("~", time.Now().Add(1000*time.Second), 5*time.Second))
 
-				for i := 0; i < keyCount; i++ {
-					key, err := NewKey(ns, set, randString(50))
-					Expect(err).ToNot(HaveOccurred())
-					e := existance{key: key, shouldExist: rand.Intn(100) > 50}
-					exList = append(exList, e)
-					keys = append(keys, key)
+				ttl, expires := rec.TimeToLive()
+				Expect(expires).To(BeTrue())
+				Expect(ttl).To(BeNumerically("~", 1000*time.Second, 5*time.Second))
 
-					// if key shouldExist == true, put it in the DB
-					if e.shouldExist {
-						err = client.PutBins(wpolicy, key, bin)
-						Expect(err).ToNot(HaveOccurred())
+				err = client.PutBins(NewWritePolicy(0, TTLNeverExpire), key, bin)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.GetHeader(rpolicy, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.ExpirationTime().IsZero()).To(BeTrue())
+
+				_, expires = rec.TimeToLive()
+				Expect(expires).To(BeFalse())
+			})
+
+			It("must return a nil record for a non-existing key", func() {
+				nxkey, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.GetHeader(rpolicy, nxkey)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec).To(BeNil())
+			})
+
+		}) // GetHeader context
+
+		Context("Replica and read mode policies", func() {
+			bin := NewBin("Aerospike", rand.Int())
+
+			BeforeEach(func() {
+				err = client.PutBins(wpolicy, key, bin)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("must read the record with all replica policies and read modes", func() {
+				for _, replica := range []ReplicaPolicy{MASTER, MASTER_PROLES, RANDOM, PREFER_RACK} {
+					policy := NewPolicy()
+					policy.ReplicaPolicy = replica
+					policy.ReadModeAP = ReadModeAPAll
+
+					// read several times to hit both the master and prole nodes
+					for i := 0; i < 4; i++ {
+						rec, err = client.Get(policy, key)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject()))
+
+						exists, err := client.Exists(policy, key)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(exists).To(BeTrue())
+					}
+				}
+			})
+
+			It("must read the record from the rack of the client", func() {
+				cpolicy := NewClientPolicy()
+				cpolicy.RackAware = true
+				cpolicy.RackId = 1
+				rackClient, err := NewClientWithPolicy(cpolicy, *host, *port)
+				Expect(err).ToNot(HaveOccurred())
+				defer rackClient.Close()
+
+				policy := NewPolicy()
+				policy.ReplicaPolicy = PREFER_RACK
+				for i := 0; i < 4; i++ {
+					rec, err = rackClient.Get(policy, key)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(rec.Bins[bin.Name]).To(Equal(bin.Value.GetObject()))
+				}
+			})
+
+		}) // replica context
+
+		Context("Batch Get Header operations", func() {
+			bin := NewBin("Aerospike", rand.Int())
+			const keyCount = 1024
+
+			BeforeEach(func() {
+			})
+
+			It("must return the records with same ordering as keys", func() {
+				var records []*Record
+				type existance struct {
+					key         *Key
+					shouldExist bool // set randomly and checked against later
+				}
+
+				exList := []existance{}
+				keys := []*Key{}
+
+				for i := 0; i < keyCount; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					e := existance{key: key, shouldExist: rand.Intn(100) > 50}
+					exList = append(exList, e)
+					keys = append(keys, key)
+
+					// if key shouldExist == true, put it in the DB
+					if e.shouldExist {
+						err = client.PutBins(wpolicy, key, bin)
+						Expect(err).ToNot(HaveOccurred())
 
 						// update generation
 						err = client.Touch(wpolicy, key)
@@ -777,6 +1922,193 @@ var _ = Describe("Aerospike", func() {
 
 		}) // Batch Get Header context
 
+		Context("Batch Get Operate operations", func() {
+			const keyCount = 256
+
+			It("must apply read operations and return the records with same ordering as keys", func() {
+				keys := []*Key{}
+				shouldExist := []bool{}
+
+				for i := 0; i < keyCount; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					keys = append(keys, key)
+					shouldExist = append(shouldExist, rand.Intn(100) > 50)
+
+					if shouldExist[i] {
+						err = client.PutBins(wpolicy, key, NewBin("list", []interface{}{i, i + 1, i + 2}), NewBin("other", i))
+						Expect(err).ToNot(HaveOccurred())
+					}
+				}
+
+				records, err := client.BatchGetOperate(nil, keys, ListGetRangeOp("list", 1, 2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(records)).To(Equal(len(keys)))
+				for idx, rec := range records {
+					if shouldExist[idx] {
+						Expect(rec.Bins["list"]).To(Equal([]interface{}{idx + 1, idx + 2}))
+						Expect(rec.Bins["other"]).To(BeNil())
+					} else {
+						Expect(rec).To(BeNil())
+					}
+				}
+			})
+
+			It("must reject write operations", func() {
+				_, err := client.BatchGetOperate(nil, []*Key{key}, PutOp(NewBin("bin", 1)))
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			})
+
+		}) // Batch Get Operate context
+
+		Context("Batch Get Complex operations", func() {
+
+			It("must read different bins and operations per key", func() {
+				key1, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+				key2, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+				missing, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.PutBins(wpolicy, key1, NewBin("a", 1), NewBin("b", 2), NewBin("list", []interface{}{1, 2, 3}))
+				Expect(err).ToNot(HaveOccurred())
+				err = client.PutBins(wpolicy, key2, NewBin("a", 3), NewBin("b", 4))
+				Expect(err).ToNot(HaveOccurred())
+
+				reads := []*BatchRead{
+					NewBatchRead(key1, "a"),
+					NewBatchRead(key2),
+					NewBatchReadHeader(key1),
+					NewBatchReadOps(key1, ListSizeOp("list")),
+					NewBatchRead(missing, "a"),
+				}
+
+				err = client.BatchGetComplex(nil, reads)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(reads[0].Record.Bins).To(Equal(BinMap{"a": 1}))
+				Expect(reads[1].Record.Bins).To(Equal(BinMap{"a": 3, "b": 4}))
+				Expect(reads[2].Record).NotTo(BeNil())
+				Expect(len(reads[2].Record.Bins)).To(Equal(0))
+				Expect(reads[2].Record.Generation).To(BeNumerically(">", 0))
+				Expect(reads[3].Record.Bins).To(Equal(BinMap{"list": 3}))
+				Expect(reads[4].Record).To(BeNil())
+			})
+
+			It("must read adjacent keys which repeat the same read", func() {
+				keys := make([]*Key, 4)
+				for i := range keys {
+					keys[i], err = NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					err = client.PutBins(wpolicy, keys[i], NewBin("a", i), NewBin("b", -i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				// the reads of the second and third keys repeat the first one's
+				reads := []*BatchRead{
+					NewBatchRead(keys[0], "a"),
+					NewBatchRead(keys[1], "a"),
+					NewBatchRead(keys[2], "a"),
+					NewBatchRead(keys[3], "b"),
+				}
+
+				err = client.BatchGetComplex(nil, reads)
+				Expect(err).ToNot(HaveOccurred())
+
+				for i := 0; i < 3; i++ {
+					Expect(reads[i].Record.Bins).To(Equal(BinMap{"a": i}))
+				}
+				Expect(reads[3].Record.Bins).To(Equal(BinMap{"b": -3}))
+			})
+
+			It("must reject write operations", func() {
+				err := client.BatchGetComplex(nil, []*BatchRead{NewBatchReadOps(key, PutOp(NewBin("bin", 1)))})
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			})
+
+		}) // Batch Get Complex context
+
+		Context("Batch Delete operations", func() {
+			const keyCount = 256
+
+			It("must delete the records and report which ones existed with same ordering as keys", func() {
+				keys := []*Key{}
+				shouldExist := []bool{}
+
+				for i := 0; i < keyCount; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					keys = append(keys, key)
+					shouldExist = append(shouldExist, rand.Intn(100) > 50)
+
+					if shouldExist[i] {
+						err = client.PutBins(wpolicy, key, NewBin("bin", i))
+						Expect(err).ToNot(HaveOccurred())
+					}
+				}
+
+				results, err := client.BatchDelete(nil, keys)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(results)).To(Equal(len(keys)))
+				for idx, res := range results {
+					Expect(res.Key).To(Equal(keys[idx]))
+					Expect(res.Err).ToNot(HaveOccurred())
+					if shouldExist[idx] {
+						Expect(res.ResultCode).To(Equal(OK))
+					} else {
+						Expect(res.ResultCode).To(Equal(KEY_NOT_FOUND_ERROR))
+					}
+				}
+
+				exists, err := client.BatchExists(nil, keys)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).ToNot(ContainElement(true))
+			})
+
+		}) // Batch Delete context
+
+		Context("Operate Batch operations", func() {
+			const keyCount = 256
+
+			It("must apply the operations to all keys and return per key results with same ordering as keys", func() {
+				keys := []*Key{}
+				for i := 0; i < keyCount; i++ {
+					key, err := NewKey(ns, set, randString(50))
+					Expect(err).ToNot(HaveOccurred())
+					keys = append(keys, key)
+
+					err = client.PutBins(wpolicy, key, NewBin("counter", i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				results, err := client.OperateBatch(nil, keys, AddOp(NewBin("counter", 10)), TouchOp(), GetOpForBin("counter"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(len(results)).To(Equal(len(keys)))
+				for idx, res := range results {
+					Expect(res.Key).To(Equal(keys[idx]))
+					Expect(res.Err).ToNot(HaveOccurred())
+					Expect(res.ResultCode).To(Equal(OK))
+					Expect(res.Record.Bins["counter"]).To(Equal(idx + 10))
+				}
+
+				records, err := client.BatchGet(nil, keys)
+				Expect(err).ToNot(HaveOccurred())
+				for idx, rec := range records {
+					Expect(rec.Bins["counter"]).To(Equal(idx + 10))
+				}
+			})
+
+			It("must reject a batch without operations", func() {
+				_, err := client.OperateBatch(nil, []*Key{key})
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			})
+
+		}) // Operate Batch context
+
 		Context("Operate operations", func() {
 			bin1 := NewBin("Aerospike1", rand.Intn(math.MaxInt16))
 			bin2 := NewBin("Aerospike2", randString(100))
@@ -912,6 +2244,346 @@ var _ = Describe("Aerospike", func() {
 				Expect(rec.Generation).To(Equal(4))
 			})
 
+			It("must keep the existing expiration when TTLDontUpdate is used", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				wpolicy := NewWritePolicy(0, 1000)
+				_, err = client.Operate(wpolicy, key, PutOp(bin1))
+				Expect(err).ToNot(HaveOccurred())
+
+				wpolicy.Expiration = TTLDontUpdate
+				rec, err = client.Operate(wpolicy, key, PutOp(bin2), GetHeaderOp())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Expiration).To(BeNumerically("<=", 1000))
+				Expect(rec.Expiration).To(BeNumerically(">", 900))
+
+				wpolicy.Expiration = 5000
+				rec, err = client.Operate(wpolicy, key, PutOp(bin1), TouchOp(), GetHeaderOp())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Expiration).To(BeNumerically(">", 4900))
+			})
+
+			It("must not double count increments when in doubt writes are not retried", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				wpolicy := NewWritePolicy(0, 0)
+				wpolicy.NoRetryInDoubt = true
+				for i := 0; i < 10; i++ {
+					_, err = client.Operate(wpolicy, key, AddOp(NewBin("counter", 1)))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				rec, err = client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["counter"]).To(Equal(10))
+
+				// a command which times out before it is sent is not in doubt
+				wpolicy.Timeout = time.Nanosecond
+				_, err = client.Operate(wpolicy, key, AddOp(NewBin("counter", 1)))
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(TIMEOUT))
+				Expect(err.(AerospikeError).InDoubt()).To(BeFalse())
+			})
+
+			It("must return all bins of the updated record when reading all bins with write operations", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				bins := BinMap{"bin1": 1, "bin2": "a", "bin3": []interface{}{1, 2}, "bin4": 4.5}
+				err = client.Put(nil, key, bins)
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Operate(nil, key, AddOp(NewBin("bin1", 10)), PutOp(NewBin("bin5", "new")), GetOp())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins).To(Equal(BinMap{"bin1": 11, "bin2": "a", "bin3": []interface{}{1, 2}, "bin4": 4.5, "bin5": "new"}))
+			})
+
+			It("must return the record generation for write-only operations", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Operate(nil, key, PutOp(bin1))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec).ToNot(BeNil())
+				Expect(rec.Generation).To(Equal(1))
+				Expect(rec.Bins).To(BeEmpty())
+
+				wpolicy := NewWritePolicy(int32(rec.Generation), 0)
+				wpolicy.GenerationPolicy = EXPECT_GEN_EQUAL
+				rec, err = client.Operate(wpolicy, key, AddOp(bin1))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Generation).To(Equal(2))
+
+				// the generation has changed since
+				_, err = client.Operate(wpolicy, key, AddOp(bin1))
+				Expect(err).To(HaveOccurred())
+				Expect(IsGenerationError(err)).To(BeTrue())
+				Expect(IsResultCode(err, GENERATION_ERROR)).To(BeTrue())
+			})
+
+			It("must apply list and map CDT operations", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				for i := 1; i <= 5; i++ {
+					_, err = client.Operate(nil, key, ListAppendOp("list", i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				rec, err = client.Operate(nil, key, ListGetRangeOp("list", 1, 3))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal([]interface{}{2, 3, 4}))
+
+				_, err = client.Operate(nil, key, MapPutOp("map", "key1", 42))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Operate(nil, key, MapGetByKeyOp("map", "key1", MAP_RETURN_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(Equal(42))
+			})
+
+			It("must apply bit operations on blob bins", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.PutBins(nil, key, NewBin("flags", []byte{0x00, 0x00}))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Operate(nil, key,
+					BitSetOp("flags", 0, 4, []byte{0xF0}),
+					BitOrOp("flags", 8, 8, []byte{0x01}),
+					BitGetOp("flags", 0, 16),
+				)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["flags"]).To(Equal([]byte{0xF0, 0x01}))
+
+				rec, err = client.Operate(nil, key,
+					BitAndOp("flags", 0, 8, []byte{0x30}),
+					BitLShiftOp("flags", 8, 8, 3),
+					BitCountOp("flags", 0, 16),
+					BitGetOp("flags", 0, 16),
+				)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["flags"]).To(Equal([]byte{0x30, 0x08}))
+			})
+
+			It("must apply HyperLogLog operations", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Operate(nil, key, HLLInitOp("hll", 12), HLLAddOp("hll", "a", "b", "c", "a"), HLLGetCountOp("hll"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["hll"]).To(Equal(int64(3)))
+
+				rec, err = client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				hll, ok := rec.Bins["hll"].(*HLLValue)
+				Expect(ok).To(BeTrue())
+
+				key2, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Operate(nil, key2, HLLInitOp("hll", 12), HLLAddOp("hll", "c", "d"), HLLSimilarityOp("hll", hll))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["hll"]).To(BeNumerically(">", 0.0))
+				Expect(rec.Bins["hll"]).To(BeNumerically("<", 1.0))
+
+				rec, err = client.Operate(nil, key2, HLLMergeOp("hll", hll), HLLGetCountOp("hll"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["hll"]).To(Equal(int64(4)))
+			})
+
+			It("must sort lists and remove their duplicate values", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				for _, i := range []int{3, 1, 2, 3, 1} {
+					_, err = client.Operate(nil, key, ListAppendOp("list", i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				rec, err = client.Operate(nil, key, ListSortOp("list", LIST_SORT_DESCENDING), ListSizeOp("list"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal(5))
+
+				rec, err = client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal([]interface{}{3, 3, 2, 1, 1}))
+
+				rec, err = client.Operate(nil, key, ListRemoveDuplicatesOp("list"), ListSizeOp("list"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal(3))
+
+				rec, err = client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal([]interface{}{1, 2, 3}))
+			})
+
+			It("must sort lists in ascending order", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				for _, i := range []int{5, 9, 1, 7, 3} {
+					_, err = client.Operate(nil, key, ListAppendOp("list", i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				_, err = client.Operate(nil, key, ListSortOp("list", LIST_SORT_DEFAULT))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Get(nil, key)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal([]interface{}{1, 3, 5, 7, 9}))
+			})
+
+			It("must get list items by rank and by value", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				for _, i := range []int{30, 10, 20, 10} {
+					_, err = client.Operate(nil, key, ListAppendOp("list", i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				rec, err := client.Operate(nil, key, ListGetByRankOp("list", -1, LIST_RETURN_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal(30))
+
+				rec, err = client.Operate(nil, key, ListGetByRankOp("list", 0, LIST_RETURN_INDEX))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(BeNumerically("==", 1))
+
+				rec, err = client.Operate(nil, key, ListGetByValueOp("list", 10, LIST_RETURN_INDEX))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal([]interface{}{1, 3}))
+
+				rec, err = client.Operate(nil, key, ListGetByValueOp("list", 10, LIST_RETURN_COUNT))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal(2))
+
+				rec, err = client.Operate(nil, key, ListGetByValueRangeOp("list", 10, 30, LIST_RETURN_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(ConsistOf(10, 20, 10))
+
+				rec, err = client.Operate(nil, key, ListGetByValueRangeOp("list", 20, nil, LIST_RETURN_RANK))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(ConsistOf(2, 3))
+			})
+
+			It("must return the data selected by the map return type", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				for i, mapKey := range []string{"key1", "key2", "key3"} {
+					_, err = client.Operate(nil, key, MapPutOp("map", mapKey, i+1))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				rec, err := client.Operate(nil, key, MapGetByKeyOp("map", "key2", MAP_RETURN_KEY_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(Equal([]MapPair{{Key: "key2", Value: 2}}))
+
+				rec, err = client.Operate(nil, key, MapGetByKeyRangeOp("map", "key1", "key3", MAP_RETURN_COUNT))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(Equal(2))
+
+				rec, err = client.Operate(nil, key, MapGetByKeyRangeOp("map", "key2", nil, MAP_RETURN_KEY_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(ConsistOf(MapPair{Key: "key2", Value: 2}, MapPair{Key: "key3", Value: 3}))
+
+				rec, err = client.Operate(nil, key, MapGetByKeyListOp("map", []Value{NewValue("key1"), NewValue("key3"), NewValue("key4")}, MAP_RETURN_KEY_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(ConsistOf(MapPair{Key: "key1", Value: 1}, MapPair{Key: "key3", Value: 3}))
+
+				rec, err = client.Operate(nil, key, MapGetByKeyListOp("map", []Value{NewValue("key1"), NewValue("key3")}, MAP_RETURN_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(ConsistOf(1, 3))
+
+				rec, err = client.Operate(nil, key, MapGetByKeyListOp("map", []Value{NewValue("key2"), NewValue("key4")}, MAP_RETURN_COUNT))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(Equal(1))
+
+				rec, err = client.Operate(nil, key, MapGetByKeyOp("map", "key1", MAP_RETURN_NONE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["map"]).To(BeNil())
+			})
+
+			It("must store key ordered maps and read their items in order", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				series := map[interface{}]interface{}{30: "c", 10: "a", 20: "b"}
+				err = client.PutBins(nil, key, NewBin("ordered", NewOrderedMapValue(series, KEY_ORDERED)), NewBin("unordered", series))
+				Expect(err).ToNot(HaveOccurred())
+
+				ordered := []MapPair{{Key: 10, Value: "a"}, {Key: 20, Value: "b"}, {Key: 30, Value: "c"}}
+
+				rec, err := client.Operate(nil, key, MapGetByIndexRangeOp("ordered", 0, 0, MAP_RETURN_KEY_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["ordered"]).To(Equal(ordered))
+
+				rec, err = client.Operate(nil, key, MapGetByIndexRangeOp("ordered", 1, 1, MAP_RETURN_KEY_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["ordered"]).To(Equal(ordered[1:2]))
+
+				_, err = client.Operate(nil, key, MapSetOrderOp("unordered", KEY_ORDERED))
+				Expect(err).ToNot(HaveOccurred())
+
+				rec, err = client.Operate(nil, key, MapGetByIndexRangeOp("unordered", 0, 0, MAP_RETURN_KEY_VALUE))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["unordered"]).To(Equal(ordered))
+			})
+
+			It("must return the results of multiple operations on the same bin", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				for i := 1; i <= 5; i++ {
+					_, err = client.Operate(nil, key, ListAppendOp("list", i))
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				// only the last result of the bin is returned by default
+				rec, err = client.Operate(nil, key, ListGetRangeOp("list", 0, 1), ListGetRangeOp("list", 3, 2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal([]interface{}{4, 5}))
+
+				wpolicy := NewWritePolicy(0, 0)
+				wpolicy.RespondPerEachOp = true
+				rec, err = client.Operate(wpolicy, key, ListGetRangeOp("list", 0, 1), ListGetRangeOp("list", 3, 2))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal(OpResults{[]interface{}{1}, []interface{}{4, 5}}))
+
+				rec, err = client.Operate(wpolicy, key, ListGetRangeOp("list", 0, 1), ListAppendOp("list", 6), TouchOp(), ListGetRangeOp("list", 5, 1))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rec.Bins["list"]).To(Equal(OpResults{[]interface{}{1}, 6, []interface{}{6}}))
+			})
+
+			It("must return the result of each operation at its index with OperateWithResults", func() {
+				key, err := NewKey(ns, set, randString(50))
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.PutBins(wpolicy, key, NewBin("list", []interface{}{1, 2, 3}), NewBin("bin", 1))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, results, err := client.OperateWithResults(nil, key,
+					ListGetRangeOp("list", 0, 1),
+					ListAppendOp("list", 4),
+					TouchOp(),
+					PutOp(NewBin("bin", 2)),
+					GetOpForBin("bin"),
+					ListGetRangeOp("list", 3, 1),
+				)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results).To(Equal(OperateResults{[]interface{}{1}, 4, nil, nil, 2, []interface{}{4}}))
+
+				_, _, err = client.OperateWithResults(nil, key, GetOp())
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+			})
+
 		}) // GetHeader context
 
 	})
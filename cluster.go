@@ -15,8 +15,13 @@
 package aerospike
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"math"
+	"net"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +35,7 @@ type tendCommand int
 
 const (
 	_TEND_CMD_CLOSE tendCommand = iota
+	_TEND_CMD_STOP
 	_TEND_MSG_CLOSED
 )
 
@@ -52,6 +58,12 @@ type Cluster struct {
 	// Hints for best node for a partition
 	partitionWriteMap map[string][]*Node
 
+	// Nodes holding the prole (replica) of each partition
+	partitionProleMap map[string][]*Node
+
+	// Round-robin index to distribute reads between masters and proles.
+	replicaIndex *AtomicInt
+
 	// Random node index.
 	nodeIndex *AtomicInt
 
@@ -61,22 +73,112 @@ type Cluster struct {
 	// Initial connection timeout.
 	connectionTimeout time.Duration
 
+	// Interval to check idle pooled connections.
+	connectionPingInterval time.Duration
+
+	// Maximum idle time of pooled connections, and the number of
+	// connections per node which are kept open regardless.
+	maxSocketIdle         time.Duration
+	minConnectionsPerNode int
+
+	// Rack awareness, and the rack of the client.
+	rackAware bool
+	rackId    int
+
+	// Interval to resolve seed host names again, and the last time they were resolved.
+	seedRefreshInterval time.Duration
+	lastSeedRefresh     time.Time
+
+	// TLS configuration; nil means plain connections.
+	tlsConfig *tls.Config
+
+	// TCP keep-alive period and TCP_NODELAY option of new connections.
+	socketKeepAlive time.Duration
+	tcpNoDelay      bool
+
+	// Custom function to open the network connections; nil uses a net.Dialer.
+	dialer func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// Discover peers from their alternate access addresses,
+	// or only connect to the nodes reached through the seeds.
+	useServicesAlternate bool
+	seedOnly             bool
+
+	// Maximum expiration of writes; 0 means unlimited.
+	maxAllowedTTL uint32
+
+	// Check the length of bin names before writes are sent.
+	validateBinNames bool
+
+	// User name, the hash of the password new connections authenticate with,
+	// and the clear password sent for external authentication.
+	user          string
+	password      []byte
+	clearPassword []byte
+	authMode      AuthMode
+	passwordMutex sync.RWMutex
+
 	mutex       sync.RWMutex
 	tendChannel chan tendCommand
 	closed      AtomicBool
+
+	// Requests to refresh the partition map before the next tend,
+	// and the channel closed once the requested refresh is done.
+	partitionRefresh     chan struct{}
+	refreshMutex         sync.Mutex
+	partitionRefreshDone chan struct{}
+
+	// Listener of node changes, and the changes queued for it.
+	listenerMutex sync.Mutex
+	nodeListener  NodeListener
+	nodeEvents    []*NodeEvent
+	notifying     bool
 }
 
 // NewCluster generates a Cluster instance.
 func NewCluster(policy *ClientPolicy, hosts []*Host) (*Cluster, error) {
 	newCluster := &Cluster{
-		seeds:               hosts,
-		connectionQueueSize: policy.ConnectionQueueSize,
-		connectionTimeout:   policy.Timeout,
-		aliases:             make(map[Host]*Node),
-		nodes:               []*Node{},
-		partitionWriteMap:   make(map[string][]*Node),
-		nodeIndex:           NewAtomicInt(0),
-		tendChannel:         make(chan tendCommand),
+		seeds:                  hosts,
+		connectionQueueSize:    policy.ConnectionQueueSize,
+		connectionTimeout:      policy.Timeout,
+		connectionPingInterval: policy.ConnectionPingInterval,
+		maxSocketIdle:          policy.MaxSocketIdle,
+		minConnectionsPerNode:  policy.MinConnectionsPerNode,
+		rackAware:              policy.RackAware,
+		rackId:                 policy.RackId,
+		seedRefreshInterval:    policy.SeedRefreshInterval,
+		lastSeedRefresh:        time.Now(),
+		tlsConfig:              policy.TlsConfig,
+		socketKeepAlive:        policy.SocketKeepAlive,
+		tcpNoDelay:             !policy.DisableTcpNoDelay,
+		dialer:                 policy.Dialer,
+		useServicesAlternate:   policy.UseServicesAlternate,
+		seedOnly:               policy.SeedOnly,
+		maxAllowedTTL:          policy.MaxAllowedTTL,
+		validateBinNames:       !policy.DisableBinNameValidation,
+		aliases:                make(map[Host]*Node),
+		nodes:                  []*Node{},
+		partitionWriteMap:      make(map[string][]*Node),
+		partitionProleMap:      make(map[string][]*Node),
+		replicaIndex:           NewAtomicInt(0),
+		nodeIndex:              NewAtomicInt(0),
+		tendChannel:            make(chan tendCommand),
+		partitionRefresh:       make(chan struct{}, 1),
+	}
+
+	if policy.User != "" {
+		if policy.AuthMode == AuthModeExternal && policy.TlsConfig == nil {
+			return nil, NewAerospikeError(PARAMETER_ERROR, "External authentication requires TLS. Set ClientPolicy.TlsConfig, or use AuthModeExternalInsecure.")
+		}
+
+		password, err := hashPassword(policy.Password)
+		if err != nil {
+			return nil, err
+		}
+		newCluster.user = policy.User
+		newCluster.password = password
+		newCluster.clearPassword = []byte(policy.Password)
+		newCluster.authMode = policy.AuthMode
 	}
 
 	// try to seed connections for first use
@@ -84,7 +186,7 @@ func NewCluster(policy *ClientPolicy, hosts []*Host) (*Cluster, error) {
 
 	// apply policy rules
 	if policy.FailIfNotConnected && !newCluster.IsConnected() {
-		return nil, fmt.Errorf("Failed to connect to host(s): %v", hosts)
+		return nil, NewAerospikeError(SERVER_NOT_AVAILABLE, fmt.Sprintf("Failed to connect to host(s): %v", hosts))
 	}
 
 	// start up cluster maintenance go routine
@@ -97,15 +199,18 @@ func NewCluster(policy *ClientPolicy, hosts []*Host) (*Cluster, error) {
 // Maintains the cluster on intervals.
 // All clean up code for cluster is here as well.
 func (clstr *Cluster) clusterBoss() {
+	var cmd tendCommand
 
 Loop:
 	for {
 		select {
-		case cmd := <-clstr.tendChannel:
+		case cmd = <-clstr.tendChannel:
 			switch cmd {
-			case _TEND_CMD_CLOSE:
+			case _TEND_CMD_CLOSE, _TEND_CMD_STOP:
 				break Loop
 			}
+		case <-clstr.partitionRefresh:
+			clstr.refreshPartitions()
 		case <-time.After(tendInterval):
 			if err := clstr.tend(); err != nil {
 				Logger.Warn(err.Error())
@@ -116,10 +221,10 @@ Loop:
 	// cleanup code goes here
 	clstr.closed.Set(true)
 
-	// close the nodes
-	nodeArray := clstr.GetNodes()
-	for _, node := range nodeArray {
-		node.Close()
+	// close the nodes, unless the caller closes them
+	// after the commands in flight are drained
+	if cmd == _TEND_CMD_CLOSE {
+		clstr.closeNodes()
 	}
 
 	clstr.tendChannel <- _TEND_MSG_CLOSED
@@ -143,9 +248,14 @@ func (clstr *Cluster) getSeeds() []*Host {
 // Updates cluster state
 func (clstr *Cluster) tend() error {
 	nodes := clstr.GetNodes()
+	defer func(before []*Node) {
+		clstr.notifyNodeChanges(before, clstr.GetNodes())
+	}(nodes)
 
 	// All node additions/deletions are performed in tend goroutine.
-	// If active nodes don't exist, seed cluster.
+	// If active nodes don't exist, seed cluster. The original seeds are kept,
+	// so that the cluster is seeded again on every tend until it recovers,
+	// even after losing all its nodes.
 	if len(nodes) == 0 {
 		Logger.Info("No connections available; seeding...")
 		clstr.seedNodes()
@@ -172,9 +282,19 @@ func (clstr *Cluster) tend() error {
 					friendList = append(friendList, friends...)
 				}
 			}
+
+			if clstr.maxSocketIdle > 0 {
+				node.closeIdleConnections(clstr.maxSocketIdle, clstr.minConnectionsPerNode)
+			}
 		}
 	}
 
+	// Seed addresses may have changed since they were resolved.
+	if clstr.seedRefreshInterval > 0 && time.Since(clstr.lastSeedRefresh) >= clstr.seedRefreshInterval {
+		friendList = append(friendList, clstr.resolveSeeds()...)
+		clstr.lastSeedRefresh = time.Now()
+	}
+
 	// Add nodes in a batch.
 	if addList := clstr.findNodesToAdd(friendList); len(addList) > 0 {
 		clstr.addNodes(addList)
@@ -253,30 +373,23 @@ func (clstr *Cluster) getPartitions() map[string][]*Node {
 	return res
 }
 
+func (clstr *Cluster) setProlePartitions(partMap map[string][]*Node) {
+	clstr.mutex.Lock()
+	clstr.partitionProleMap = partMap
+	clstr.mutex.Unlock()
+}
+
+func (clstr *Cluster) getProlePartitions() map[string][]*Node {
+	clstr.mutex.RLock()
+	res := clstr.partitionProleMap
+	clstr.mutex.RUnlock()
+	return res
+}
+
 func (clstr *Cluster) updatePartitions(conn *Connection, node *Node) error {
-	// TODO: Cluster should not care about version of tokenizer
-	// decouple clstr interface
-	var nmap map[string][]*Node
-	if node.useNewInfo {
-		Logger.Info("Updating partitions using new protocol...")
-		tokens, err := newPartitionTokenizerNew(conn)
-		if err != nil {
-			return err
-		}
-		nmap, err = tokens.UpdatePartition(clstr.getPartitions(), node)
-		if err != nil {
-			return err
-		}
-	} else {
-		Logger.Info("Updating partitions using old protocol...")
-		tokens, err := newPartitionTokenizerOld(conn)
-		if err != nil {
-			return err
-		}
-		nmap, err = tokens.UpdatePartition(clstr.getPartitions(), node)
-		if err != nil {
-			return err
-		}
+	nmap, err := clstr.parsePartitions(conn, node, replicasName, clstr.getPartitions())
+	if err != nil {
+		return err
 	}
 
 	// update partition write map
@@ -284,10 +397,77 @@ func (clstr *Cluster) updatePartitions(conn *Connection, node *Node) error {
 		clstr.setPartitions(nmap)
 	}
 
+	// update partition prole map; reads fall back to masters if it is not available
+	pmap, err := clstr.parsePartitions(conn, node, replicasProleName, clstr.getProlePartitions())
+	if err != nil {
+		Logger.Warn("Failed to update prole partitions for node %s: %s", node.String(), err.Error())
+	} else if pmap != nil {
+		clstr.setProlePartitions(pmap)
+	}
+
 	Logger.Info("Partitions updated...")
 	return nil
 }
 
+// requestPartitionRefresh asks the tend goroutine to refresh the partition map
+// without waiting for the next tend. The returned channel is closed once the
+// partition map has been refreshed. Concurrent requests share the same refresh.
+func (clstr *Cluster) requestPartitionRefresh() <-chan struct{} {
+	clstr.refreshMutex.Lock()
+	defer clstr.refreshMutex.Unlock()
+
+	if clstr.partitionRefreshDone == nil {
+		clstr.partitionRefreshDone = make(chan struct{})
+		select {
+		case clstr.partitionRefresh <- struct{}{}:
+		default:
+		}
+	}
+	return clstr.partitionRefreshDone
+}
+
+// refreshPartitions checks the partition generation of all nodes, and updates
+// the partition map from the nodes whose partitions have changed.
+// It must only be called from the tend goroutine.
+func (clstr *Cluster) refreshPartitions() {
+	clstr.refreshMutex.Lock()
+	done := clstr.partitionRefreshDone
+	clstr.partitionRefreshDone = nil
+	clstr.refreshMutex.Unlock()
+
+	for _, node := range clstr.GetNodes() {
+		if err := node.refreshPartitions(); err != nil {
+			Logger.Warn("Node `%s` partition refresh failed: %s", node, err)
+		}
+	}
+
+	if done != nil {
+		close(done)
+	}
+}
+
+// parsePartitions requests the partitions of the node using the given info name,
+// and updates the partition map with them.
+func (clstr *Cluster) parsePartitions(conn *Connection, node *Node, name string, partitions map[string][]*Node) (map[string][]*Node, error) {
+	// TODO: Cluster should not care about version of tokenizer
+	// decouple clstr interface
+	if node.useNewInfo {
+		Logger.Info("Updating partitions using new protocol...")
+		tokens, err := newPartitionTokenizerNew(conn, name)
+		if err != nil {
+			return nil, err
+		}
+		return tokens.UpdatePartition(partitions, node)
+	}
+
+	Logger.Info("Updating partitions using old protocol...")
+	tokens, err := newPartitionTokenizerOld(conn, name)
+	if err != nil {
+		return nil, err
+	}
+	return tokens.UpdatePartition(partitions, node)
+}
+
 // Adds seeds to the cluster
 func (clstr *Cluster) seedNodes() {
 	// Must copy array reference for copy on write semantics to work.
@@ -299,7 +479,7 @@ func (clstr *Cluster) seedNodes() {
 	list := []*Node{}
 
 	for _, seed := range seedArray {
-		seedNodeValidator, err := newNodeValidator(seed, clstr.connectionTimeout)
+		seedNodeValidator, err := newNodeValidator(clstr, seed, clstr.connectionTimeout)
 		if err != nil {
 			Logger.Warn("Seed %s failed: %s", seed.String(), err.Error())
 			continue
@@ -312,7 +492,7 @@ func (clstr *Cluster) seedNodes() {
 			if *alias == *seed {
 				nv = seedNodeValidator
 			} else {
-				nv, err = newNodeValidator(alias, clstr.connectionTimeout)
+				nv, err = newNodeValidator(clstr, alias, clstr.connectionTimeout)
 				if err != nil {
 					Logger.Warn("Seed %s failed: %s", seed.String(), err.Error())
 					continue
@@ -332,6 +512,32 @@ func (clstr *Cluster) seedNodes() {
 	}
 }
 
+// resolveSeeds resolves the seed host names again, and returns
+// the addresses which are not yet known aliases of any node.
+func (clstr *Cluster) resolveSeeds() []*Host {
+	hosts := []*Host{}
+
+	for _, seed := range clstr.getSeeds() {
+		addresses, err := net.LookupHost(seed.Name)
+		if err != nil {
+			Logger.Warn("Seed %s failed to resolve: %s", seed.String(), err.Error())
+			continue
+		}
+
+		for _, addr := range addresses {
+			host := NewHost(addr, seed.Port)
+			host.TLSName = seed.TLSName
+
+			if clstr.findAlias(host) == nil {
+				Logger.Debug("Seed %s resolved to new address %s", seed.String(), host.String())
+				hosts = append(hosts, host)
+			}
+		}
+	}
+
+	return hosts
+}
+
 // Finds a node by name in a list of nodes
 func (clstr *Cluster) findNodeName(list []*Node, name string) bool {
 	for _, node := range list {
@@ -362,7 +568,7 @@ func (clstr *Cluster) findNodesToAdd(hosts []*Host) []*Node {
 	list := make([]*Node, 0, len(hosts))
 
 	for _, host := range hosts {
-		if nv, err := newNodeValidator(host, clstr.connectionTimeout); err != nil {
+		if nv, err := newNodeValidator(clstr, host, clstr.connectionTimeout); err != nil {
 			Logger.Warn("Add node %s failed: %s", err.Error())
 		} else {
 			node := clstr.findNodeByName(nv.name)
@@ -409,6 +615,14 @@ func (clstr *Cluster) findNodesToRemove(refreshCount int) []*Node {
 			continue
 		}
 
+		// peers do not reference the nodes reached through the seeds
+		if clstr.seedOnly {
+			if node.IsUnhealthy() {
+				removeList = append(removeList, node)
+			}
+			continue
+		}
+
 		switch len(nodes) {
 		case 1:
 			// Single node clusters rely solely on node health.
@@ -465,7 +679,12 @@ func (clstr *Cluster) findNodeInPartitionMap(filter *Node) bool {
 func (clstr *Cluster) addNodes(nodesToAdd []*Node) {
 	// Add all nodes at once to avoid copying entire array multiple times.
 	for _, node := range nodesToAdd {
+		Logger.Info("Adding node %s (%s)", node.name, node.host.String())
 		clstr.addAliases(node)
+
+		if clstr.connectionPingInterval > 0 {
+			go node.pingIdleConnections(clstr.connectionPingInterval)
+		}
 	}
 	clstr.addNodesCopy(nodesToAdd)
 }
@@ -552,6 +771,62 @@ func (clstr *Cluster) nodeExists(search *Node, nodeList []*Node) bool {
 	return false
 }
 
+// newConnection opens a connection to the address with the TLS and
+// socket settings of the cluster.
+func (clstr *Cluster) newConnection(address string, timeout time.Duration, tlsName string) (*Connection, error) {
+	return dialConnection(address, timeout, clstr.tlsConfig, tlsName, clstr.socketKeepAlive, clstr.tcpNoDelay, clstr.dialer)
+}
+
+// validateExpiration rejects the expiration of a write if it exceeds the
+// MaxAllowedTTL of the client policy, or is an invalid negative value.
+func (clstr *Cluster) validateExpiration(expiration int32) error {
+	if clstr.maxAllowedTTL == 0 {
+		return nil
+	}
+
+	switch {
+	case expiration == TTLNeverExpire, expiration == TTLDontUpdate:
+	case expiration < 0:
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid expiration %d; the only negative values allowed are TTLNeverExpire and TTLDontUpdate.", expiration))
+	case uint32(expiration) > clstr.maxAllowedTTL:
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Expiration %d exceeds the maximum allowed TTL of %d seconds.", expiration, clstr.maxAllowedTTL))
+	}
+	return nil
+}
+
+// validateBins rejects bins whose names are too long, unless the
+// check is disabled in the client policy.
+func (clstr *Cluster) validateBins(bins []*Bin) error {
+	if !clstr.validateBinNames {
+		return nil
+	}
+
+	for _, bin := range bins {
+		if err := validateBinName(bin.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOperations rejects operations on bins whose names are too long,
+// unless the check is disabled in the client policy.
+func (clstr *Cluster) validateOperations(operations []*Operation) error {
+	if !clstr.validateBinNames {
+		return nil
+	}
+
+	for _, operation := range operations {
+		if operation.BinName == nil {
+			continue
+		}
+		if err := validateBinName(*operation.BinName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // IsConnected returns true if cluster has nodes and is not already closed.
 func (clstr *Cluster) IsConnected() bool {
 	// Must copy array reference for copy on write semantics to work.
@@ -559,6 +834,98 @@ func (clstr *Cluster) IsConnected() bool {
 	return (len(nodeArray) > 0) && !clstr.closed.Get()
 }
 
+// WaitUntilConnected blocks until the cluster has at least one active node,
+// or returns an error if the timeout expires or the cluster is closed first.
+// If timeout is zero or negative, it waits indefinitely.
+func (clstr *Cluster) WaitUntilConnected(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = _NO_TIMEOUT
+	}
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !clstr.IsConnected() {
+		if clstr.closed.Get() {
+			return NewAerospikeError(SERVER_NOT_AVAILABLE, "Cluster is closed.")
+		}
+
+		select {
+		case <-deadline:
+			return NewAerospikeError(TIMEOUT, "Cluster did not connect in time.")
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// WaitUntilReady blocks until the cluster is connected, and the master node of every
+// partition of all namespaces is known, or returns an error describing what is still
+// missing if the timeout expires or the cluster is closed first.
+// If timeout is zero or negative, it waits indefinitely.
+func (clstr *Cluster) WaitUntilReady(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = _NO_TIMEOUT
+	}
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		missing := clstr.notReadyReason()
+		if missing == "" {
+			return nil
+		}
+
+		if clstr.closed.Get() {
+			return NewAerospikeError(SERVER_NOT_AVAILABLE, "Cluster is closed.")
+		}
+
+		select {
+		case <-deadline:
+			return NewAerospikeError(TIMEOUT, "Cluster was not ready in time: "+missing)
+		case <-ticker.C:
+		}
+	}
+}
+
+// notReadyReason describes why the cluster can not serve all partitions yet,
+// or returns an empty string if it can.
+func (clstr *Cluster) notReadyReason() string {
+	if !clstr.IsConnected() {
+		return "no node is connected"
+	}
+
+	partitions := clstr.getPartitions()
+	if len(partitions) == 0 {
+		return "the partition map is empty"
+	}
+
+	namespaces := make([]string, 0, len(partitions))
+	for namespace := range partitions {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	reasons := []string{}
+	for _, namespace := range namespaces {
+		missing := 0
+		for _, node := range partitions[namespace] {
+			if node == nil || !node.IsActive() {
+				missing++
+			}
+		}
+		missing += _PARTITIONS - len(partitions[namespace])
+
+		if missing > 0 {
+			reasons = append(reasons, fmt.Sprintf("namespace `%s` has no master node for %d partitions", namespace, missing))
+		}
+	}
+	return strings.Join(reasons, ", ")
+}
+
 // GetNode returns a node for the provided partition.
 func (clstr *Cluster) GetNode(partition *Partition) (*Node, error) {
 	// Must copy hashmap reference for copy on write semantics to work.
@@ -573,6 +940,85 @@ func (clstr *Cluster) GetNode(partition *Partition) (*Node, error) {
 	return clstr.GetRandomNode()
 }
 
+// getReadNode returns a node to read the provided partition from,
+// according to the replica policy.
+func (clstr *Cluster) getReadNode(partition *Partition, replica ReplicaPolicy) (*Node, error) {
+	switch replica {
+	case MASTER_PROLES:
+		return clstr.getMasterProleNode(partition)
+	case RANDOM:
+		return clstr.GetRandomNode()
+	case PREFER_RACK:
+		return clstr.getRackNode(partition)
+	}
+	return clstr.GetNode(partition)
+}
+
+// getRackNode returns the master or prole node of the partition which is on the
+// rack of the client. If neither is on the rack, or the cluster is not rack aware,
+// it falls back to any replica, alternating between the master and the prole node.
+func (clstr *Cluster) getRackNode(partition *Partition) (*Node, error) {
+	if clstr.rackAware {
+		for _, nmap := range []map[string][]*Node{clstr.getPartitions(), clstr.getProlePartitions()} {
+			if nodeArray, exists := nmap[partition.Namespace]; exists {
+				node := nodeArray[partition.PartitionId]
+
+				if node != nil && node.IsActive() && node.hasRack(partition.Namespace, clstr.rackId) {
+					return node, nil
+				}
+			}
+		}
+	}
+	return clstr.getMasterProleNode(partition)
+}
+
+// getMasterProleNode alternates between the prole and the master node of the partition.
+// It falls back to the master node if the prole node is not known or not active.
+func (clstr *Cluster) getMasterProleNode(partition *Partition) (*Node, error) {
+	if clstr.replicaIndex.IncrementAndGet()%2 == 0 {
+		nmap := clstr.getProlePartitions()
+		if nodeArray, exists := nmap[partition.Namespace]; exists {
+			node := nodeArray[partition.PartitionId]
+
+			if node != nil && node.IsActive() {
+				return node, nil
+			}
+		}
+	}
+	return clstr.GetNode(partition)
+}
+
+// credentials returns the user and the hashed password new connections authenticate with.
+// The user is empty when the cluster runs without restricted access.
+func (clstr *Cluster) credentials() (string, []byte) {
+	clstr.passwordMutex.RLock()
+	defer clstr.passwordMutex.RUnlock()
+	return clstr.user, clstr.password
+}
+
+// changePassword replaces the password of the user, if it is the cluster user.
+func (clstr *Cluster) changePassword(user string, password []byte, clearPassword string) {
+	clstr.passwordMutex.Lock()
+	defer clstr.passwordMutex.Unlock()
+	if user == clstr.user {
+		clstr.password = password
+		clstr.clearPassword = []byte(clearPassword)
+	}
+}
+
+// login logs the cluster user in on a new connection, and returns the session token
+// and its expiration. The token is nil when the cluster does not use sessions.
+func (clstr *Cluster) login(conn *Connection) ([]byte, time.Time, error) {
+	clstr.passwordMutex.RLock()
+	user, password, clearPassword, authMode := clstr.user, clstr.password, clstr.clearPassword, clstr.authMode
+	clstr.passwordMutex.RUnlock()
+
+	if user == "" {
+		return nil, time.Time{}, nil
+	}
+	return newAdminCommand().login(conn, authMode, user, password, clearPassword)
+}
+
 // GetRandomNode returns a random node on the cluster
 func (clstr *Cluster) GetRandomNode() (*Node, error) {
 	// Must copy array reference for copy on write semantics to work.
@@ -635,6 +1081,59 @@ func (clstr *Cluster) Close() {
 	}
 }
 
+// CloseWithTimeout stops the tend goroutine and waits up to timeout for the
+// commands in flight to complete before closing all cached connections to
+// the cluster nodes. If timeout is zero or negative, it waits until all
+// commands are complete. A TIMEOUT error is returned if commands were still
+// in flight when the connections were closed.
+func (clstr *Cluster) CloseWithTimeout(timeout time.Duration) error {
+	if clstr.closed.Get() {
+		return nil
+	}
+
+	// send stop signal to maintenance channel
+	clstr.tendChannel <- _TEND_CMD_STOP
+
+	// wait until tendChannel returns
+	<-clstr.tendChannel
+
+	err := clstr.waitForCommands(timeout)
+	clstr.closeNodes()
+	return err
+}
+
+// waitForCommands waits until no command is in flight on any of the nodes,
+// or the timeout expires.
+func (clstr *Cluster) waitForCommands(timeout time.Duration) error {
+	var limit time.Time
+	if timeout > 0 {
+		limit = time.Now().Add(timeout)
+	}
+
+	for {
+		inFlight := 0
+		for _, node := range clstr.GetNodes() {
+			inFlight += node.stats.commandsInFlight.Get()
+		}
+
+		if inFlight == 0 {
+			return nil
+		}
+
+		if !limit.IsZero() && time.Now().After(limit) {
+			return NewAerospikeError(TIMEOUT, fmt.Sprintf("%d commands still in flight after %v.", inFlight, timeout))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (clstr *Cluster) closeNodes() {
+	nodeArray := clstr.GetNodes()
+	for _, node := range nodeArray {
+		node.Close()
+	}
+}
+
 // MigrationInProgress determines if any node in the cluster
 // is participating in a data migration
 func (clstr *Cluster) MigrationInProgress(timeout time.Duration) (res bool, err error) {
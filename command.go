@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -32,9 +33,21 @@ const (
 	// Get all bins.
 	_INFO1_GET_ALL int = (1 << 1)
 
+	// Batch read or exists.
+	_INFO1_BATCH int = (1 << 3)
+
+	// Write made by XDR, which XDR does not ship to other datacenters.
+	_INFO1_XDR int = (1 << 4)
+
 	// Do not read the bins
 	_INFO1_NOBINDATA int = (1 << 5)
 
+	// Involve all replicas in read operation.
+	_INFO1_CONSISTENCY_ALL int = (1 << 6)
+
+	// Ask the server to compress the response.
+	_INFO1_COMPRESS_RESPONSE int = (1 << 7)
+
 	// Create or update record
 	_INFO2_WRITE int = (1 << 0)
 	// Fling a record into the belly of Moloch.
@@ -45,11 +58,19 @@ const (
 	_INFO2_GENERATION_GT int = (1 << 3)
 	// Create a duplicate on a generation collision.
 	_INFO2_GENERATION_DUP int = (1 << 4)
+	// Write a tombstone when the record is deleted. Servers which support
+	// durable deletes reuse the obsolete generation duplicate bit.
+	_INFO2_DURABLE_DELETE int = (1 << 4)
 	// Create only. Fail if record already exists.
 	_INFO2_CREATE_ONLY int = (1 << 5)
+	// Return a result for every operation.
+	_INFO2_RESPOND_ALL_OPS int = (1 << 7)
 
 	// This is the last of a multi-part message.
 	_INFO3_LAST int = (1 << 0)
+
+	// Marks the end of a partition in partition scans.
+	_INFO3_PARTITION_DONE int = (1 << 2)
 	// Update only. Merge bins.
 	_INFO3_UPDATE_ONLY int = (1 << 3)
 
@@ -58,6 +79,22 @@ const (
 	// Completely replace existing record only.
 	_INFO3_REPLACE_ONLY int = (1 << 5)
 
+	// See ReadModeSC for the meaning of these flags.
+	_INFO3_SC_READ_TYPE  int = (1 << 6)
+	_INFO3_SC_READ_RELAX int = (1 << 7)
+
+	// Batch index flags: allow the server to process the batch inline,
+	// and return a result for every key, including the keys which are not found.
+	_BATCH_ALLOW_INLINE     int = (1 << 0)
+	_BATCH_RESPOND_ALL_KEYS int = (1 << 2)
+
+	// Batch index key flags: repeat the previous key's command, or send the
+	// command attributes, generation and expiration of the key.
+	_BATCH_MSG_REPEAT int = (1 << 0)
+	_BATCH_MSG_INFO   int = (1 << 1)
+	_BATCH_MSG_GEN    int = (1 << 2)
+	_BATCH_MSG_TTL    int = (1 << 3)
+
 	_MSG_TOTAL_HEADER_SIZE     uint8 = 30
 	_FIELD_HEADER_SIZE         uint8 = 5
 	_OPERATION_HEADER_SIZE     uint8 = 8
@@ -89,21 +126,34 @@ type baseCommand struct {
 	node *Node
 	conn *Connection
 
+	// ctx allows the caller to abort the command; nil means no cancellation.
+	ctx context.Context
+
 	dataBuffer []byte
 	dataOffset int
 }
 
 // Writes the command for write operations
 func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, key *Key, bins []*Bin) error {
+	if policy.UseMsgpack {
+		bins = msgpackBins(bins)
+	}
+
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key)
 
-	if policy.SendKey {
+	if policy.SendKey && key.hasValueToSend() {
 		// field header size + key size
 		cmd.dataOffset += key.userKey.estimateSize() + int(_FIELD_HEADER_SIZE) + 1
 		fieldCount++
 	}
 
+	predExpSize := 0
+	if len(policy.FilterExpression) > 0 {
+		predExpSize = cmd.estimatePredExpSize(policy.FilterExpression)
+		fieldCount++
+	}
+
 	for i := range bins {
 		cmd.estimateOperationSizeForBin(bins[i])
 	}
@@ -113,10 +163,14 @@ func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, k
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE, fieldCount, len(bins))
 	cmd.writeKey(key)
 
-	if policy.SendKey {
+	if policy.SendKey && key.hasValueToSend() {
 		cmd.writeFieldValue(key.userKey, KEY)
 	}
 
+	if len(policy.FilterExpression) > 0 {
+		cmd.writePredExp(policy.FilterExpression, predExpSize)
+	}
+
 	for i := range bins {
 		if err := cmd.writeOperationForBin(bins[i], operation); err != nil {
 			return err
@@ -131,11 +185,22 @@ func (cmd *baseCommand) setWrite(policy *WritePolicy, operation OperationType, k
 func (cmd *baseCommand) setDelete(policy *WritePolicy, key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key)
+
+	predExpSize := 0
+	if len(policy.FilterExpression) > 0 {
+		predExpSize = cmd.estimatePredExpSize(policy.FilterExpression)
+		fieldCount++
+	}
+
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE|_INFO2_DELETE, fieldCount, 0)
 	cmd.writeKey(key)
+
+	if len(policy.FilterExpression) > 0 {
+		cmd.writePredExp(policy.FilterExpression, predExpSize)
+	}
 	cmd.end()
 	return nil
 
@@ -145,7 +210,7 @@ func (cmd *baseCommand) setDelete(policy *WritePolicy, key *Key) error {
 func (cmd *baseCommand) setTouch(policy *WritePolicy, key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key)
-	if policy.SendKey {
+	if policy.SendKey && key.hasValueToSend() {
 		// field header size + key size
 		cmd.dataOffset += key.userKey.estimateSize() + int(_FIELD_HEADER_SIZE) + 1
 		fieldCount++
@@ -156,7 +221,7 @@ func (cmd *baseCommand) setTouch(policy *WritePolicy, key *Key) error {
 	}
 	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE, fieldCount, 1)
 	cmd.writeKey(key)
-	if policy.SendKey {
+	if policy.SendKey && key.hasValueToSend() {
 		cmd.writeFieldValue(key.userKey, KEY)
 	}
 	cmd.writeOperationForOperationType(TOUCH)
@@ -223,19 +288,14 @@ func (cmd *baseCommand) setRead(key *Key, binNames []string) (err error) {
 func (cmd *baseCommand) setReadHeader(key *Key) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key)
-	cmd.estimateOperationSizeForBinName("")
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
 
-	// The server does not currently return record header data with _INFO1_NOBINDATA attribute set.
-	// The workaround is to request a non-existent bin.
-	// TODO: Fix this on server.
-	//command.setRead(_INFO1_READ | _INFO1_NOBINDATA);
-	cmd.writeHeader(_INFO1_READ, 0, fieldCount, 1)
+	// Ask the server to return the record header only, without any bin data.
+	cmd.writeHeader(_INFO1_READ|_INFO1_NOBINDATA, 0, fieldCount, 0)
 
 	cmd.writeKey(key)
-	cmd.writeOperationForBinName("", READ)
 	cmd.end()
 	return nil
 
@@ -243,6 +303,10 @@ func (cmd *baseCommand) setReadHeader(key *Key) error {
 
 // Implements different command operations
 func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*Operation) error {
+	if policy.UseMsgpack {
+		operations = msgpackOperations(operations)
+	}
+
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key)
 	readAttr := 0
@@ -267,33 +331,50 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 			readAttr |= _INFO1_READ
 			readHeader = true
 
+		case CDT_READ, BIT_READ, HLL_READ:
+			readAttr |= _INFO1_READ
+
 		default:
 			writeAttr = _INFO2_WRITE
 		}
 		cmd.estimateOperationSizeForOperation(operations[i])
 	}
 
-	if policy.SendKey && writeAttr != 0 {
+	if policy.RespondPerEachOp {
+		writeAttr |= _INFO2_RESPOND_ALL_OPS
+	}
+
+	if policy.SendKey && key.hasValueToSend() && writeAttr&_INFO2_WRITE != 0 {
 		// field header size + key size
 		cmd.dataOffset += key.userKey.estimateSize() + int(_FIELD_HEADER_SIZE) + 1
 		fieldCount++
 	}
 
+	predExpSize := 0
+	if len(policy.FilterExpression) > 0 {
+		predExpSize = cmd.estimatePredExpSize(policy.FilterExpression)
+		fieldCount++
+	}
+
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
 
-	if writeAttr != 0 {
+	if writeAttr&_INFO2_WRITE != 0 {
 		cmd.writeHeaderWithPolicy(policy, readAttr, writeAttr, fieldCount, len(operations))
 	} else {
 		cmd.writeHeader(readAttr, writeAttr, fieldCount, len(operations))
 	}
 	cmd.writeKey(key)
 
-	if policy.SendKey && writeAttr != 0 {
+	if policy.SendKey && key.hasValueToSend() && writeAttr&_INFO2_WRITE != 0 {
 		cmd.writeFieldValue(key.userKey, KEY)
 	}
 
+	if len(policy.FilterExpression) > 0 {
+		cmd.writePredExp(policy.FilterExpression, predExpSize)
+	}
+
 	for _, operation := range operations {
 		if err := cmd.writeOperationForOperation(operation); err != nil {
 			return err
@@ -310,9 +391,16 @@ func (cmd *baseCommand) setOperate(policy *WritePolicy, key *Key, operations []*
 	return nil
 }
 
-func (cmd *baseCommand) setUdf(key *Key, packageName string, functionName string, args []Value) error {
+func (cmd *baseCommand) setUdf(policy *WritePolicy, key *Key, packageName string, functionName string, args []Value) error {
 	cmd.begin()
 	fieldCount := cmd.estimateKeySize(key)
+
+	if policy.SendKey && key.hasValueToSend() {
+		// field header size + key size
+		cmd.dataOffset += key.userKey.estimateSize() + int(_FIELD_HEADER_SIZE) + 1
+		fieldCount++
+	}
+
 	argBytes, err := packValueArray(args)
 	if err != nil {
 		return err
@@ -322,8 +410,13 @@ func (cmd *baseCommand) setUdf(key *Key, packageName string, functionName string
 	if err := cmd.sizeBuffer(); err != nil {
 		return nil
 	}
-	cmd.writeHeader(0, _INFO2_WRITE, fieldCount, 0)
+	cmd.writeHeaderWithPolicy(policy, 0, _INFO2_WRITE, fieldCount, 0)
 	cmd.writeKey(key)
+
+	if policy.SendKey && key.hasValueToSend() {
+		cmd.writeFieldValue(key.userKey, KEY)
+	}
+
 	cmd.writeFieldString(packageName, UDF_PACKAGE_NAME)
 	cmd.writeFieldString(functionName, UDF_FUNCTION)
 	cmd.writeFieldBytes(argBytes, UDF_ARGLIST)
@@ -398,10 +491,306 @@ func (cmd *baseCommand) setBatchGet(batchNamespace *batchNamespace, binNames map
 	return nil
 }
 
-func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *string, binNames []string) error {
+// setBatchIndexRead writes a batch index request which applies the same
+// read operations to all keys. Only the first key carries the namespace and
+// operations; the following keys are flagged to repeat them.
+func (cmd *baseCommand) setBatchIndexRead(batchNamespace *batchNamespace, operations []*Operation, readAttr int) error {
+	// Estimate buffer size
+	cmd.begin()
+	keys := batchNamespace.keys
+
+	// key count and inline flag
+	cmd.dataOffset += int(_FIELD_HEADER_SIZE) + 5
+
+	for i := range keys {
+		// batch index, digest and repeat flag
+		cmd.dataOffset += 4 + int(_DIGEST_SIZE) + 1
+
+		if i == 0 {
+			// read attributes, field and operation counts
+			cmd.dataOffset += 5 + len(*batchNamespace.namespace) + int(_FIELD_HEADER_SIZE)
+			for _, operation := range operations {
+				cmd.estimateOperationSizeForOperation(operation)
+			}
+		}
+	}
+
+	if err := cmd.sizeBuffer(); err != nil {
+		return err
+	}
+
+	cmd.writeHeader(readAttr|_INFO1_BATCH, 0, 1, 0)
+
+	// real field size is written after all keys
+	fieldSizeOffset := cmd.dataOffset
+	cmd.writeFieldHeader(0, BATCH_INDEX)
+
+	Buffer.Int32ToBytes(int32(len(keys)), cmd.dataBuffer, cmd.dataOffset)
+	cmd.dataOffset += 4
+	// allow the server to process the batch inline
+	cmd.dataBuffer[cmd.dataOffset] = 1
+	cmd.dataOffset++
+
+	for i, key := range keys {
+		Buffer.Int32ToBytes(int32(i), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 4
+		cmd.dataOffset += copy(cmd.dataBuffer[cmd.dataOffset:], key.digest)
+
+		if i > 0 {
+			// repeat namespace and operations of the previous key
+			cmd.dataBuffer[cmd.dataOffset] = 1
+			cmd.dataOffset++
+			continue
+		}
+
+		cmd.dataBuffer[cmd.dataOffset] = 0
+		cmd.dataOffset++
+		cmd.dataBuffer[cmd.dataOffset] = byte(readAttr)
+		cmd.dataOffset++
+		Buffer.Int16ToBytes(1, cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 2
+		Buffer.Int16ToBytes(int16(len(operations)), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 2
+		cmd.writeFieldString(*batchNamespace.namespace, NAMESPACE)
+
+		for _, operation := range operations {
+			if err := cmd.writeOperationForOperation(operation); err != nil {
+				return err
+			}
+		}
+	}
+
+	Buffer.Int32ToBytes(int32(cmd.dataOffset-int(_MSG_TOTAL_HEADER_SIZE)-4), cmd.dataBuffer, fieldSizeOffset)
+	cmd.end()
+
+	return nil
+}
+
+// setBatchRead writes a batch index request which applies a separate read
+// request to each key, in the same format as setBatchIndexRead, so that it
+// is supported by Aerospike 3.6 servers. Keys whose request is the same as
+// the previous key's are flagged to repeat it.
+func (cmd *baseCommand) setBatchRead(batchNamespace *batchNamespace, records []*BatchRead) error {
+	// Estimate buffer size
+	cmd.begin()
+	offsets := batchNamespace.offsets
+
+	// key count and inline flag
+	cmd.dataOffset += int(_FIELD_HEADER_SIZE) + 5
+
+	var prev *BatchRead
+	for _, offset := range offsets {
+		record := records[offset]
+
+		// batch index, digest and repeat flag
+		cmd.dataOffset += 4 + int(_DIGEST_SIZE) + 1
+
+		if prev == nil || !record.sameRead(prev) {
+			// read attributes, field and operation counts
+			cmd.dataOffset += 1 + 2 + 2
+			cmd.dataOffset += len(record.Key.namespace) + int(_FIELD_HEADER_SIZE)
+			if record.Key.setName != "" {
+				cmd.dataOffset += len(record.Key.setName) + int(_FIELD_HEADER_SIZE)
+			}
+
+			if len(record.Ops) > 0 {
+				for _, operation := range record.Ops {
+					cmd.estimateOperationSizeForOperation(operation)
+				}
+			} else {
+				for _, binName := range record.BinNames {
+					cmd.estimateOperationSizeForBinName(binName)
+				}
+			}
+		}
+		prev = record
+	}
+
+	if err := cmd.sizeBuffer(); err != nil {
+		return err
+	}
+
+	cmd.writeHeader(_INFO1_READ|_INFO1_BATCH, 0, 1, 0)
+
+	// real field size is written after all keys
+	fieldSizeOffset := cmd.dataOffset
+	cmd.writeFieldHeader(0, BATCH_INDEX)
+
+	Buffer.Int32ToBytes(int32(len(offsets)), cmd.dataBuffer, cmd.dataOffset)
+	cmd.dataOffset += 4
+	// allow the server to process the batch inline
+	cmd.dataBuffer[cmd.dataOffset] = 1
+	cmd.dataOffset++
+
+	prev = nil
+	for i, offset := range offsets {
+		record := records[offset]
+
+		Buffer.Int32ToBytes(int32(i), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 4
+		cmd.dataOffset += copy(cmd.dataBuffer[cmd.dataOffset:], record.Key.digest)
+
+		if prev != nil && record.sameRead(prev) {
+			// repeat the read request of the previous key
+			cmd.dataBuffer[cmd.dataOffset] = 1
+			cmd.dataOffset++
+			continue
+		}
+		prev = record
+
+		cmd.dataBuffer[cmd.dataOffset] = 0
+		cmd.dataOffset++
+		cmd.dataBuffer[cmd.dataOffset] = byte(record.readAttr())
+		cmd.dataOffset++
+
+		fieldCount := 1
+		if record.Key.setName != "" {
+			fieldCount++
+		}
+		operationCount := len(record.Ops)
+		if operationCount == 0 {
+			operationCount = len(record.BinNames)
+		}
+		Buffer.Int16ToBytes(int16(fieldCount), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 2
+		Buffer.Int16ToBytes(int16(operationCount), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 2
+
+		cmd.writeFieldString(record.Key.namespace, NAMESPACE)
+		if record.Key.setName != "" {
+			cmd.writeFieldString(record.Key.setName, TABLE)
+		}
+
+		if len(record.Ops) > 0 {
+			for _, operation := range record.Ops {
+				if err := cmd.writeOperationForOperation(operation); err != nil {
+					return err
+				}
+			}
+		} else {
+			for _, binName := range record.BinNames {
+				cmd.writeOperationForBinName(binName, READ)
+			}
+		}
+	}
+
+	Buffer.Int32ToBytes(int32(cmd.dataOffset-int(_MSG_TOTAL_HEADER_SIZE)-4), cmd.dataBuffer, fieldSizeOffset)
+	cmd.end()
+
+	return nil
+}
+
+// setBatchDelete writes a batch index request which deletes all keys.
+func (cmd *baseCommand) setBatchDelete(batchNamespace *batchNamespace) error {
+	return cmd.setBatchWrite(batchNamespace, 0, _INFO2_WRITE|_INFO2_DELETE, nil)
+}
+
+// setBatchWrite writes a batch index request which applies the same
+// command attributes and operations to all keys. Keys which have the same
+// namespace and set as the previous key are flagged to repeat its command.
+func (cmd *baseCommand) setBatchWrite(batchNamespace *batchNamespace, readAttr, writeAttr int, operations []*Operation) error {
+	// Estimate buffer size
+	cmd.begin()
+	keys := batchNamespace.keys
+
+	// key count and batch flags
+	cmd.dataOffset += int(_FIELD_HEADER_SIZE) + 5
+
+	for i, key := range keys {
+		// batch index, digest and key flags
+		cmd.dataOffset += 4 + int(_DIGEST_SIZE) + 1
+
+		if i == 0 || key.setName != keys[i-1].setName {
+			// command attributes, generation, expiration, field and operation counts
+			cmd.dataOffset += 3 + 2 + 4 + 4
+			cmd.dataOffset += len(key.namespace) + int(_FIELD_HEADER_SIZE)
+			if key.setName != "" {
+				cmd.dataOffset += len(key.setName) + int(_FIELD_HEADER_SIZE)
+			}
+			for _, operation := range operations {
+				cmd.estimateOperationSizeForOperation(operation)
+			}
+		}
+	}
+
+	if err := cmd.sizeBuffer(); err != nil {
+		return err
+	}
+
+	cmd.writeHeader(_INFO1_BATCH, 0, 1, 0)
+
+	// real field size is written after all keys
+	fieldSizeOffset := cmd.dataOffset
+	cmd.writeFieldHeader(0, BATCH_INDEX)
+
+	Buffer.Int32ToBytes(int32(len(keys)), cmd.dataBuffer, cmd.dataOffset)
+	cmd.dataOffset += 4
+	cmd.dataBuffer[cmd.dataOffset] = byte(_BATCH_ALLOW_INLINE | _BATCH_RESPOND_ALL_KEYS)
+	cmd.dataOffset++
+
+	for i, key := range keys {
+		Buffer.Int32ToBytes(int32(i), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 4
+		cmd.dataOffset += copy(cmd.dataBuffer[cmd.dataOffset:], key.digest)
+
+		if i > 0 && key.setName == keys[i-1].setName {
+			cmd.dataBuffer[cmd.dataOffset] = byte(_BATCH_MSG_REPEAT)
+			cmd.dataOffset++
+			continue
+		}
+
+		cmd.dataBuffer[cmd.dataOffset] = byte(_BATCH_MSG_INFO | _BATCH_MSG_GEN | _BATCH_MSG_TTL)
+		cmd.dataOffset++
+		cmd.dataBuffer[cmd.dataOffset] = byte(readAttr)
+		cmd.dataOffset++
+		cmd.dataBuffer[cmd.dataOffset] = byte(writeAttr)
+		cmd.dataOffset++
+		cmd.dataBuffer[cmd.dataOffset] = 0
+		cmd.dataOffset++
+
+		// generation and expiration are not used
+		Buffer.Int16ToBytes(0, cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 2
+		Buffer.Int32ToBytes(0, cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 4
+
+		fieldCount := 1
+		if key.setName != "" {
+			fieldCount++
+		}
+		Buffer.Int16ToBytes(int16(fieldCount), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 2
+		Buffer.Int16ToBytes(int16(len(operations)), cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 2
+
+		cmd.writeFieldString(key.namespace, NAMESPACE)
+		if key.setName != "" {
+			cmd.writeFieldString(key.setName, TABLE)
+		}
+
+		for _, operation := range operations {
+			if err := cmd.writeOperationForOperation(operation); err != nil {
+				return err
+			}
+		}
+	}
+
+	Buffer.Int32ToBytes(int32(cmd.dataOffset-int(_MSG_TOTAL_HEADER_SIZE)-4), cmd.dataBuffer, fieldSizeOffset)
+	cmd.end()
+
+	return nil
+}
+
+func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *string, binNames []string, partitions []int) error {
 	cmd.begin()
 	fieldCount := 0
 
+	recordsPerSecond := cmd.recordsPerSecond(policy.RecordsPerSecond, cmd.node.supportsScanRPS)
+	if recordsPerSecond > 0 {
+		cmd.dataOffset += 4 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
 	if namespace != nil {
 		cmd.dataOffset += len(*namespace) + int(_FIELD_HEADER_SIZE)
 		fieldCount++
@@ -412,10 +801,20 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 		fieldCount++
 	}
 
+	if len(partitions) > 0 {
+		cmd.dataOffset += len(partitions)*2 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
 	// Estimate scan options size.
 	cmd.dataOffset += 2 + int(_FIELD_HEADER_SIZE)
 	fieldCount++
 
+	// bins are not sent back without bin data
+	if !policy.IncludeBinData {
+		binNames = nil
+	}
+
 	if binNames != nil {
 		for i := range binNames {
 			cmd.estimateOperationSizeForBinName(binNames[i])
@@ -444,6 +843,16 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 		cmd.writeFieldString(*setName, TABLE)
 	}
 
+	if len(partitions) > 0 {
+		cmd.writeFieldHeader(len(partitions)*2, PID_ARRAY)
+		// partition ids are sent in little endian order
+		for _, id := range partitions {
+			cmd.dataBuffer[cmd.dataOffset] = byte(id)
+			cmd.dataBuffer[cmd.dataOffset+1] = byte(id >> 8)
+			cmd.dataOffset += 2
+		}
+	}
+
 	cmd.writeFieldHeader(2, SCAN_OPTIONS)
 	priority := byte(policy.Priority)
 	priority <<= 4
@@ -456,6 +865,10 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 	cmd.dataBuffer[cmd.dataOffset] = byte(policy.ScanPercent)
 	cmd.dataOffset++
 
+	if recordsPerSecond > 0 {
+		cmd.writeFieldInt32(int32(recordsPerSecond), RECORDS_PER_SECOND)
+	}
+
 	if binNames != nil {
 		for i := range binNames {
 			cmd.writeOperationForBinName(binNames[i], READ)
@@ -466,6 +879,22 @@ func (cmd *baseCommand) setScan(policy *ScanPolicy, namespace *string, setName *
 	return nil
 }
 
+// recordsPerSecond returns the rate limit to send to the node, or zero
+// if there is none or the node does not support it.
+func (cmd *baseCommand) recordsPerSecond(recordsPerSecond int, supported bool) int {
+	if recordsPerSecond <= 0 {
+		return 0
+	}
+
+	if !supported {
+		cmd.node.rpsWarning.Do(func() {
+			Logger.Warn("Node %s does not support RecordsPerSecond; records will be returned without a rate limit.", cmd.node.String())
+		})
+		return 0
+	}
+	return recordsPerSecond
+}
+
 func (cmd *baseCommand) estimateKeySize(key *Key) int {
 	fieldCount := 0
 
@@ -572,6 +1001,14 @@ func (cmd *baseCommand) writeHeaderWithPolicy(policy *WritePolicy, readAttr int,
 		break
 	}
 
+	if policy.DurableDelete {
+		writeAttr |= _INFO2_DURABLE_DELETE
+	}
+
+	if policy.Xdr {
+		readAttr |= _INFO1_XDR
+	}
+
 	// Write all header data except total size which must be written last.
 	cmd.dataBuffer[8] = _MSG_REMAINING_HEADER_SIZE // Message header length.
 	cmd.dataBuffer[9] = byte(readAttr)
@@ -704,6 +1141,30 @@ func (cmd *baseCommand) writeFieldBytes(bytes []byte, ftype FieldType) {
 	cmd.dataOffset += len(bytes)
 }
 
+func (cmd *baseCommand) writeFieldInt32(val int32, ftype FieldType) {
+	cmd.writeFieldHeader(4, ftype)
+	Buffer.Int32ToBytes(val, cmd.dataBuffer, cmd.dataOffset)
+	cmd.dataOffset += 4
+}
+
+// estimatePredExpSize adds the size of the predicate expression field
+// to the command, and returns the size of the expressions.
+func (cmd *baseCommand) estimatePredExpSize(predExps []PredExp) int {
+	size := 0
+	for _, predExp := range predExps {
+		size += predExp.marshaledSize()
+	}
+	cmd.dataOffset += size + int(_FIELD_HEADER_SIZE)
+	return size
+}
+
+func (cmd *baseCommand) writePredExp(predExps []PredExp, size int) {
+	cmd.writeFieldHeader(size, PREDEXP)
+	for _, predExp := range predExps {
+		cmd.dataOffset = predExp.marshal(cmd.dataBuffer, cmd.dataOffset)
+	}
+}
+
 func (cmd *baseCommand) writeFieldHeader(size int, ftype FieldType) {
 	Buffer.Int32ToBytes(int32(size+1), cmd.dataBuffer, cmd.dataOffset)
 	cmd.dataOffset += 4
@@ -761,44 +1222,155 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 	policy := ifc.getPolicy(ifc).GetBasePolicy()
 	iterations := 0
 
+	// a write command is in doubt once it may have been applied on the server
+	inDoubt := false
+	noRetryInDoubt := false
+
+	// the last error of a node which rejected the command because it was
+	// overloaded, and whether to back off before the next retry
+	var backpressureErr error
+	backoff := false
+
+	// the error of the last attempt which was retried
+	var lastErr error
+
+	// shouldRetry decides whether to retry the command after the failed attempt;
+	// the ShouldRetry callback of the policy overrides the default decision
+	shouldRetry := func(err error, retryByDefault bool) bool {
+		lastErr = err
+		if policy.ShouldRetry != nil {
+			return policy.ShouldRetry(iterations, err)
+		}
+		return retryByDefault
+	}
+
+	if wp, ok := ifc.getPolicy(ifc).(*WritePolicy); ok {
+		noRetryInDoubt = wp.NoRetryInDoubt
+	}
+
+	ctx := cmd.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// context deadline takes precedence over the policy timeout when it is sooner
+	timeout := policy.Timeout
+	if deadline, exists := ctx.Deadline(); exists {
+		if remaining := deadline.Sub(time.Now()); timeout <= 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+
 	// set timeout outside the loop
-	limit := time.Now().Add(policy.Timeout)
+	limit := time.Now().Add(timeout)
+
+	// the duration of scans and queries depends on the number of records,
+	// so they neither use, nor count towards, the adaptive timeouts of the nodes
+	isScanOrQuery := false
+	switch ifc.getPolicy(ifc).(type) {
+	case *ScanPolicy, *QueryPolicy:
+		isScanOrQuery = true
+	}
+
+	// the node the command is in flight on, so that a graceful
+	// cluster close can wait for the command to finish
+	var activeNode *Node
+	defer func() {
+		if activeNode != nil {
+			activeNode.stats.commandsInFlight.DecrementAndGet()
+		}
+	}()
 
 	// Execute command until successful, timed out or maximum iterations have been reached.
 	for {
-		// too many retries
-		if iterations++; (policy.MaxRetries > 0) && (iterations > policy.MaxRetries+1) {
+		// command was cancelled by the caller
+		if ctx.Err() != nil {
+			return newContextError(ctx)
+		}
+
+		// too many retries, unless the ShouldRetry callback decides when to give up
+		if iterations++; policy.ShouldRetry == nil && (policy.MaxRetries > 0) && (iterations > policy.MaxRetries+1) {
 			break
 		}
 
-		// Sleep before trying again, after the first iteration
-		if iterations > 1 && policy.SleepBetweenRetries > 0 {
-			time.Sleep(policy.SleepBetweenRetries)
+		// Sleep before trying again, after the first iteration;
+		// back off longer if the node was overloaded
+		if iterations > 1 {
+			delay := policy.retryDelay(iterations - 2)
+			if backoff {
+				delay = policy.BackpressureBackoff
+			}
+
+			if delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return newContextError(ctx)
+				}
+			}
 		}
+		backoff = false
 
 		// check for command timeout
-		if policy.Timeout > 0 && time.Now().After(limit) {
+		if timeout > 0 && time.Now().After(limit) {
 			break
 		}
 
 		node, err := ifc.getNode(ifc)
 		if err != nil {
 			// Node is currently inactive.  Retry.
-			continue
+			if shouldRetry(err, true) {
+				continue
+			}
+			return err
 		}
 
 		// set command node, so when you return a record it has the node
 		cmd.node = node
 
-		cmd.conn, err = node.GetConnection(policy.Timeout)
+		if activeNode != nil {
+			activeNode.stats.commandsInFlight.DecrementAndGet()
+		}
+		activeNode = node
+		node.stats.commandsInFlight.IncrementAndGet()
+
+		node.stats.commands.IncrementAndGet()
+		if iterations > 1 {
+			node.stats.retries.IncrementAndGet()
+			Logger.Debug("Node %s: retrying command, attempt %d", node.String(), iterations)
+		}
+
+		socketTimeout := timeout
+		if !isScanOrQuery {
+			if adaptive := node.adaptiveTimeout(policy); adaptive > 0 && (socketTimeout <= 0 || adaptive < socketTimeout) {
+				socketTimeout = adaptive
+			}
+		}
+
+		cmd.conn, err = node.GetConnection(socketTimeout)
 		if err != nil {
 			// Socket connection error has occurred. Decrease health and retry.
 			node.DecreaseHealth()
 
 			Logger.Warn("Node " + node.String() + ": " + err.Error())
-			continue
+			if shouldRetry(err, true) {
+				continue
+			}
+			return err
+		}
+
+		if err = cmd.conn.setSocketTimeout(policy.SocketTimeout); err != nil {
+			cmd.conn.Close()
+			Logger.Warn("Node %s: %s", node.String(), err.Error())
+			if shouldRetry(err, true) {
+				continue
+			}
+			return err
 		}
 
+		// abort blocking socket IO as soon as the context is done
+		release := cmd.conn.bindContext(ctx)
+
 		// Draw a buffer from buffer pool, and make sure it will be put back
 		cmd.dataBuffer = bufPool.Get()
 		// defer bufPool.Put(cmd.dataBuffer)
@@ -808,25 +1380,57 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 		if err != nil {
 			// All runtime exceptions are considered fatal. Do not retry.
 			// Close socket to flush out possible garbage. Do not put back in pool.
+			release()
 			cmd.conn.Close()
 			return err
 		}
 
 		// Reset timeout in send buffer (destined for server) and socket.
-		Buffer.Int32ToBytes(int32(policy.Timeout/time.Millisecond), cmd.dataBuffer, 22)
+		Buffer.Int32ToBytes(int32(timeout/time.Millisecond), cmd.dataBuffer, 22)
+
+		isWrite := int(cmd.dataBuffer[10])&_INFO2_WRITE != 0
+
+		cmd.setReadMode(policy)
+
+		sendBuffer, compressed, err := cmd.compress(policy, node)
+		if err != nil {
+			release()
+			cmd.conn.Close()
+			return err
+		}
+		cmd.conn.setCompressed(compressed)
 
 		// Send command.
-		_, err = cmd.conn.Write(cmd.dataBuffer[:cmd.dataOffset])
+		start := time.Now()
+		_, err = cmd.conn.Write(sendBuffer)
 		if err != nil {
 			// IO errors are considered temporary anomalies. Retry.
 			// Close socket to flush out possible garbage. Do not put back in pool.
+			release()
 			cmd.conn.Close()
 
+			if ctx.Err() != nil {
+				return newContextError(ctx)
+			}
+
 			Logger.Warn("Node " + node.String() + ": " + err.Error())
 			// IO error means connection to server node is unhealthy.
 			// Reflect cmd status.
 			node.DecreaseHealth()
-			continue
+
+			// the server may have received the write before the connection failed
+			if isWrite {
+				inDoubt = true
+				if noRetryInDoubt {
+					return markInDoubt(err)
+				}
+				err = markInDoubt(err)
+			}
+
+			if shouldRetry(err, true) {
+				continue
+			}
+			return err
 		}
 
 		// Parse results.
@@ -836,15 +1440,73 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 			// cancelling/closing the batch/multi commands will return an error, which will
 			// close the connection to throw away its data and signal the server about the
 			// situation. We will not put back the connection in the buffer.
+			release()
 			cmd.conn.Close()
+
+			if ctx.Err() != nil {
+				return newContextError(ctx)
+			}
+
+			if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == TIMEOUT {
+				node.stats.timeouts.IncrementAndGet()
+
+				// count the attempt as a sample at its timeout, so that the adaptive
+				// timeout widens again once the latency of the node has risen
+				if !isScanOrQuery && socketTimeout > 0 {
+					node.stats.latencies.add(socketTimeout)
+				}
+			}
+
+			if isWrite && isNetworkError(err) {
+				err = markInDoubt(err)
+				if noRetryInDoubt || !shouldRetry(err, false) {
+					return err
+				}
+				inDoubt = true
+				continue
+			}
+
+			// the partition has moved to another node; refresh the partition
+			// map now and retry on the new owner, if the owner has changed
+			if cmd.partitionMoved(ifc, node, err) {
+				lastErr = err
+				continue
+			}
+
+			// the node is overloaded; the command was not applied
+			if ae, ok := isBackpressure(err); ok {
+				backpressureErr = newBackpressureError(node, ae)
+				if shouldRetry(backpressureErr, policy.BackpressureBackoff > 0) {
+					backoff = policy.BackpressureBackoff > 0
+					continue
+				}
+				return backpressureErr
+			}
+
+			// scans and queries may have returned records already
+			if !isScanOrQuery && shouldRetry(err, false) {
+				continue
+			}
 			return err
 		}
 
+		latency := time.Since(start)
+		node.stats.recordLatency(latency)
+		if !isScanOrQuery {
+			node.stats.latencies.add(latency)
+		}
+
 		// Reflect healthy status.
 		node.RestoreHealth()
 
-		// Put connection back in pool.
-		node.PutConnection(cmd.conn)
+		// Put connection back in pool, unless its deadline was
+		// expired to abort IO after the context was done.
+		if release() {
+			cmd.conn.Close()
+		} else {
+			cmd.conn.setCompressed(false)
+			node.PutConnection(cmd.conn)
+		}
 
 		// put back buffer to the pool
 		bufPool.Put(cmd.dataBuffer)
@@ -854,8 +1516,128 @@ func (cmd *baseCommand) execute(ifc command) (err error) {
 
 	}
 
+	// retries were exhausted after a node was overloaded; the last attempt
+	// may have failed for another reason, so return the error it failed with
+	if backpressureErr != nil && !inDoubt {
+		return lastErr
+	}
+
 	// execution timeout
-	return NewAerospikeError(TIMEOUT, "command execution timed out.")
+	if cmd.node != nil {
+		cmd.node.stats.timeouts.IncrementAndGet()
+	}
+
+	err = NewAerospikeError(TIMEOUT, "command execution timed out.")
+	if inDoubt {
+		return markInDoubt(err)
+	}
+	return err
+}
+
+// partitionMoved returns true if the node does not own the partition of the
+// command anymore, and another node owns it after the partition map was refreshed.
+func (cmd *baseCommand) partitionMoved(ifc command, node *Node, err error) bool {
+	if ae, ok := err.(AerospikeError); !ok || ae.ResultCode() != PARTITION_UNAVAILABLE {
+		return false
+	}
+
+	if _, ok := ifc.(partitionCommand); !ok {
+		return false
+	}
+
+	Logger.Debug("Node %s: partition unavailable, refreshing the partition map", node.String())
+
+	// the next tend refreshes the partition map anyway
+	select {
+	case <-node.cluster.requestPartitionRefresh():
+	case <-time.After(tendInterval):
+	}
+
+	newNode, err := ifc.getNode(ifc)
+	return err == nil && newNode != node
+}
+
+// isNetworkError returns true if the error was caused by the connection,
+// and not returned by the server.
+func isNetworkError(err error) bool {
+	var ae AerospikeError
+	if errors.As(err, &ae) {
+		return ae.ResultCode() == TIMEOUT || ae.ResultCode() == NETWORK_ERROR
+	}
+	return true
+}
+
+// markInDoubt marks the error of a write command which
+// may have been applied on the server as in doubt.
+func markInDoubt(err error) error {
+	ae, ok := err.(AerospikeError)
+	if !ok {
+		ae = NewAerospikeError(NETWORK_ERROR, err.Error()).(AerospikeError)
+	}
+	return ae.MarkInDoubt()
+}
+
+// setReadMode sets the read consistency flags of the policy
+// in the header of read only commands.
+func (cmd *baseCommand) setReadMode(policy *BasePolicy) {
+	readAttr := int(cmd.dataBuffer[9])
+	if readAttr&_INFO1_READ == 0 || int(cmd.dataBuffer[10])&_INFO2_WRITE != 0 {
+		return
+	}
+
+	if policy.ReadModeAP == ReadModeAPAll {
+		readAttr |= _INFO1_CONSISTENCY_ALL
+	}
+	cmd.dataBuffer[9] = byte(readAttr)
+
+	infoAttr := int(cmd.dataBuffer[11])
+	switch policy.ReadModeSC {
+	case ReadModeSCLinearize:
+		infoAttr |= _INFO3_SC_READ_TYPE
+	case ReadModeSCAllowReplica:
+		infoAttr |= _INFO3_SC_READ_RELAX
+	case ReadModeSCAllowUnavailable:
+		infoAttr |= _INFO3_SC_READ_TYPE | _INFO3_SC_READ_RELAX
+	}
+	cmd.dataBuffer[11] = byte(infoAttr)
+}
+
+// compress returns the message to send to the node, compressed if the policy
+// asks for it and the node supports it. It also reports whether the response
+// may be compressed.
+func (cmd *baseCommand) compress(policy *BasePolicy, node *Node) ([]byte, bool, error) {
+	msg := cmd.dataBuffer[:cmd.dataOffset]
+	if !policy.UseCompression {
+		return msg, false, nil
+	}
+
+	if !node.supportsCompression {
+		node.compressionWarning.Do(func() {
+			Logger.Warn("Node %s does not support compression; commands will be sent uncompressed.", node.String())
+		})
+		return msg, false, nil
+	}
+
+	cmd.dataBuffer[9] |= byte(_INFO1_COMPRESS_RESPONSE)
+	if cmd.dataOffset <= _COMPRESS_THRESHOLD {
+		return msg, true, nil
+	}
+
+	res, err := compressMessage(msg, policy.CompressionAlgorithm)
+	if err != nil {
+		return nil, false, err
+	}
+	return res, true, nil
+}
+
+// newContextError wraps the context's error so the caller can inspect it.
+// Exceeded deadlines are reported as timeouts.
+func newContextError(ctx context.Context) error {
+	code := COMMAND_CANCELLED
+	if ctx.Err() == context.DeadlineExceeded {
+		code = TIMEOUT
+	}
+	return WrapAerospikeError(code, fmt.Errorf("command aborted: %w", ctx.Err()))
 }
 
 func (cmd *baseCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
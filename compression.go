@@ -0,0 +1,95 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+// CompressionAlgorithm determines how command messages are compressed
+// when compression is enabled in the policy.
+type CompressionAlgorithm int
+
+const (
+	// COMPRESSION_ZLIB compresses messages with zlib, the format
+	// the server uses for compressed messages.
+	COMPRESSION_ZLIB CompressionAlgorithm = iota
+)
+
+const (
+	// proto message type of compressed messages
+	_AS_MSG_TYPE_COMPRESSED int64 = 4
+
+	// messages smaller than this are sent uncompressed
+	_COMPRESS_THRESHOLD = 128
+)
+
+// compressMessage wraps the proto message in msg into a compressed proto message.
+// The compressed message carries the uncompressed size in its first 8 bytes.
+func compressMessage(msg []byte, algorithm CompressionAlgorithm) ([]byte, error) {
+	if algorithm != COMPRESSION_ZLIB {
+		return nil, NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Unsupported compression algorithm: %d", algorithm))
+	}
+
+	// reserve space for the proto header and the uncompressed size
+	res := bytes.NewBuffer(make([]byte, 16, len(msg)/2+16))
+
+	w, err := zlib.NewWriterLevel(res, zlib.BestSpeed)
+	if err != nil {
+		return nil, NewAerospikeError(SERIALIZE_ERROR, err.Error())
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, NewAerospikeError(SERIALIZE_ERROR, err.Error())
+	}
+	if err := w.Close(); err != nil {
+		return nil, NewAerospikeError(SERIALIZE_ERROR, err.Error())
+	}
+
+	buf := res.Bytes()
+	Buffer.Int64ToBytes(int64(len(buf)-8)|(_CL_MSG_VERSION<<56)|(_AS_MSG_TYPE_COMPRESSED<<48), buf, 0)
+	Buffer.Int64ToBytes(int64(len(msg)), buf, 8)
+	return buf, nil
+}
+
+// decompressMessage returns the proto message inflated from the body of a
+// compressed proto message.
+func decompressMessage(body []byte) ([]byte, error) {
+	if len(body) < 8 {
+		return nil, NewAerospikeError(PARSE_ERROR, "Invalid compressed message")
+	}
+
+	size := Buffer.BytesToInt64(body, 0)
+	if size < 8 || size > _MAX_BUFFER_SIZE {
+		return nil, NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid uncompressed message size: %d", size))
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(body[8:]))
+	if err != nil {
+		return nil, NewAerospikeError(PARSE_ERROR, err.Error())
+	}
+	defer r.Close()
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, NewAerospikeError(PARSE_ERROR, err.Error())
+	}
+	return msg, nil
+}
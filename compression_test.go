@@ -0,0 +1,189 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"bytes"
+	"net"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// protoMessage returns a proto message of the given type with the body.
+func protoMessage(msgType int64, body []byte) []byte {
+	msg := make([]byte, 8, 8+len(body))
+	Buffer.Int64ToBytes(int64(len(body))|(_CL_MSG_VERSION<<56)|(msgType<<48), msg, 0)
+	return append(msg, body...)
+}
+
+var _ = Describe("Compression Test", func() {
+
+	Context("Messages", func() {
+
+		It("must inflate compressed messages to the original message", func() {
+			msg := protoMessage(_AS_MSG_TYPE, bytes.Repeat([]byte("aerospike"), 100))
+
+			res, err := compressMessage(msg, COMPRESSION_ZLIB)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(res)).To(BeNumerically("<", len(msg)))
+
+			proto := Buffer.BytesToInt64(res, 0)
+			Expect((proto >> 48) & 0xFF).To(Equal(_AS_MSG_TYPE_COMPRESSED))
+			Expect(int(proto & 0xFFFFFFFFFFFF)).To(Equal(len(res) - 8))
+			Expect(int(Buffer.BytesToInt64(res, 8))).To(Equal(len(msg)))
+
+			inflated, err := decompressMessage(res[8:])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inflated).To(Equal(msg))
+		})
+
+		It("must reject unsupported algorithms", func() {
+			_, err := compressMessage([]byte{1}, CompressionAlgorithm(1))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+		})
+
+		It("must reject invalid compressed messages", func() {
+			msg := protoMessage(_AS_MSG_TYPE, bytes.Repeat([]byte("aerospike"), 100))
+			res, err := compressMessage(msg, COMPRESSION_ZLIB)
+			Expect(err).ToNot(HaveOccurred())
+			body := res[8:]
+
+			// too short to hold the uncompressed size
+			_, err = decompressMessage(body[:7])
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+
+			// invalid uncompressed sizes
+			for _, size := range []int64{0, _MAX_BUFFER_SIZE + 1, int64(len(msg) + 1)} {
+				invalid := append([]byte{}, body...)
+				Buffer.Int64ToBytes(size, invalid, 0)
+				_, err = decompressMessage(invalid)
+				Expect(err).To(HaveOccurred())
+				Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+			}
+
+			// truncated and corrupted data
+			_, err = decompressMessage(body[:len(body)/2])
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+
+			_, err = decompressMessage(append(body[:8:8], 0xff, 0xff, 0xff))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+		})
+	})
+
+	Context("Commands", func() {
+
+		// compress compresses a command of the given size
+		compress := func(policy *BasePolicy, node *Node, size int) ([]byte, bool, []byte) {
+			cmd := &baseCommand{dataBuffer: make([]byte, size), dataOffset: size}
+			copy(cmd.dataBuffer, protoMessage(_AS_MSG_TYPE, make([]byte, size-8)))
+
+			msg, compressed, err := cmd.compress(policy, node)
+			Expect(err).ToNot(HaveOccurred())
+			return msg, compressed, cmd.dataBuffer
+		}
+
+		node := &Node{name: "BB9000000000000", host: NewHost("127.0.0.1", 3000), supportsCompression: true}
+
+		It("must only compress commands larger than the threshold", func() {
+			policy := NewPolicy()
+			policy.UseCompression = true
+
+			msg, compressed, buf := compress(policy, node, _COMPRESS_THRESHOLD)
+			Expect(compressed).To(BeTrue())
+			Expect(msg).To(Equal(buf))
+			// the response may be compressed nonetheless
+			Expect(int(buf[9]) & _INFO1_COMPRESS_RESPONSE).To(Equal(_INFO1_COMPRESS_RESPONSE))
+
+			msg, compressed, buf = compress(policy, node, _COMPRESS_THRESHOLD+1)
+			Expect(compressed).To(BeTrue())
+			Expect((Buffer.BytesToInt64(msg, 0) >> 48) & 0xFF).To(Equal(_AS_MSG_TYPE_COMPRESSED))
+			Expect(int(buf[9]) & _INFO1_COMPRESS_RESPONSE).To(Equal(_INFO1_COMPRESS_RESPONSE))
+
+			inflated, err := decompressMessage(msg[8:])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inflated).To(Equal(buf))
+		})
+
+		It("must not compress commands without compression, or to nodes which do not support it", func() {
+			msg, compressed, buf := compress(NewPolicy(), node, 1024)
+			Expect(compressed).To(BeFalse())
+			Expect(msg).To(Equal(buf))
+			Expect(int(buf[9]) & _INFO1_COMPRESS_RESPONSE).To(Equal(0))
+
+			policy := NewPolicy()
+			policy.UseCompression = true
+			oldNode := &Node{name: "BB9000000000001", host: NewHost("127.0.0.1", 3000)}
+			msg, compressed, buf = compress(policy, oldNode, 1024)
+			Expect(compressed).To(BeFalse())
+			Expect(msg).To(Equal(buf))
+			Expect(int(buf[9]) & _INFO1_COMPRESS_RESPONSE).To(Equal(0))
+		})
+	})
+
+	Context("Responses", func() {
+
+		It("must read compressed and uncompressed messages of a response", func() {
+			first := protoMessage(_AS_MSG_TYPE, bytes.Repeat([]byte{1}, 200))
+			second := protoMessage(_AS_MSG_TYPE, []byte{2, 2, 2})
+			compressed, err := compressMessage(append(append([]byte{}, first...), second...), COMPRESSION_ZLIB)
+			Expect(err).ToNot(HaveOccurred())
+			third := protoMessage(_AS_MSG_TYPE, []byte{3})
+
+			client, server := net.Pipe()
+			defer client.Close()
+			go func() {
+				defer server.Close()
+				server.Write(append(compressed, third...))
+			}()
+
+			conn := &Connection{conn: client}
+			conn.setCompressed(true)
+
+			// reads may span the inflated messages
+			buf := make([]byte, len(first)+len(second)+len(third))
+			_, err = conn.Read(buf, 8)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = conn.Read(buf[8:], len(first)+4)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = conn.Read(buf[len(first)+12:], len(buf)-len(first)-12)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf).To(Equal(append(append(append([]byte{}, first...), second...), third...)))
+		})
+
+		It("must return the error of invalid compressed messages", func() {
+			client, server := net.Pipe()
+			defer client.Close()
+			go func() {
+				defer server.Close()
+				server.Write(protoMessage(_AS_MSG_TYPE_COMPRESSED, []byte{0, 0, 0, 0, 0, 0, 0, 16, 1, 2, 3}))
+			}()
+
+			conn := &Connection{conn: client}
+			conn.setCompressed(true)
+
+			_, err := conn.Read(make([]byte, 8), 8)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR))
+		})
+	})
+})
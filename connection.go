@@ -15,11 +15,16 @@
 package aerospike
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
 	"net"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go/logger"
 	. "github.com/aerospike/aerospike-client-go/types"
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
 )
 
 // Connection represents a connection with a timeout.
@@ -27,15 +32,36 @@ type Connection struct {
 	// timeout
 	timeout time.Duration
 
+	// idle timeout of socket reads and writes, which is refreshed as long as
+	// data is transferred, and the deadline it can not be extended beyond
+	socketTimeout time.Duration
+	deadline      time.Time
+
+	// set when the deadline was expired to abort the command
+	aborted AtomicBool
+
 	// connection object
 	conn net.Conn
+
+	// last time the connection was put back in the pool
+	lastUsed time.Time
+
+	// node the connection belongs to, if it was opened by a node
+	node *Node
+
+	// when set, responses are read one proto message at a time,
+	// and compressed messages are inflated into pending
+	compressed bool
+	pending    []byte
 }
 
+// errToTimeoutErr wraps network errors in an AerospikeError;
+// timeouts with the TIMEOUT result code, and other errors with NETWORK_ERROR.
 func errToTimeoutErr(err error) error {
 	if err, ok := err.(net.Error); ok && err.Timeout() {
-		return NewAerospikeError(TIMEOUT, err.Error())
+		return WrapAerospikeError(TIMEOUT, err)
 	}
-	return err
+	return WrapAerospikeError(NETWORK_ERROR, err)
 }
 
 // NewConnection creates a connection on the network and returns the pointer
@@ -43,10 +69,78 @@ func errToTimeoutErr(err error) error {
 // If the connection is not established in the specified timeout,
 // an error will be returned
 func NewConnection(address string, timeout time.Duration) (*Connection, error) {
+	return NewSecureConnection(address, timeout, nil, "")
+}
+
+// NewSecureConnection creates a connection on the network and returns the pointer.
+// If tlsConfig is not nil, the connection is established over TLS, and the server
+// certificate is validated against tlsName. The TLS handshake must complete in
+// the specified timeout as well.
+func NewSecureConnection(address string, timeout time.Duration, tlsConfig *tls.Config, tlsName string) (*Connection, error) {
+	return dialConnection(address, timeout, tlsConfig, tlsName, 0, true, nil)
+}
+
+// dialConnection establishes a connection like NewSecureConnection, and sets the
+// TCP keep-alive period and the TCP_NODELAY option of the socket.
+// A zero keepAlive uses the default period of the net package; a negative one
+// disables keep-alive probes.
+// If dial is not nil, it opens the network connection instead of a net.Dialer,
+// under a context which expires after the timeout; keepAlive is not applied then.
+func dialConnection(
+	address string,
+	timeout time.Duration,
+	tlsConfig *tls.Config,
+	tlsName string,
+	keepAlive time.Duration,
+	noDelay bool,
+	dial func(ctx context.Context, network, address string) (net.Conn, error),
+) (*Connection, error) {
 	newConn := &Connection{}
 
-	conn, err := net.DialTimeout("tcp", address, timeout)
+	var conn net.Conn
+	var err error
+	if dial != nil {
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		conn, err = dial(ctx, "tcp", address)
+	} else {
+		dialer := &net.Dialer{Timeout: timeout, KeepAlive: keepAlive}
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err == nil {
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			err = tcpConn.SetNoDelay(noDelay)
+		}
+	}
+	if err == nil && tlsConfig != nil {
+		config := tlsConfig.Clone()
+		if tlsName != "" {
+			config.ServerName = tlsName
+		} else if config.ServerName == "" {
+			// verify the certificate against the host of the seed
+			if host, _, err := net.SplitHostPort(address); err == nil {
+				config.ServerName = host
+			}
+		}
+
+		// the handshake must complete in the timeout as well
+		tlsConn := tls.Client(conn, config)
+		if timeout > 0 {
+			err = tlsConn.SetDeadline(time.Now().Add(timeout))
+		}
+		if err == nil {
+			err = tlsConn.Handshake()
+		}
+		conn = tlsConn
+	}
 	if err != nil {
+		if conn != nil {
+			conn.Close()
+		}
 		Logger.Error("Connection to address `" + address + "` failed to establish with error: " + err.Error())
 		return nil, errToTimeoutErr(err)
 	}
@@ -54,7 +148,7 @@ func NewConnection(address string, timeout time.Duration) (*Connection, error) {
 
 	// set timeout at the last possible moment
 	if err := newConn.SetTimeout(timeout); err != nil {
-		return nil, err
+		return nil, errToTimeoutErr(err)
 	}
 	return newConn, nil
 }
@@ -70,6 +164,10 @@ func (ctn *Connection) Write(buf []byte) (total int, err error) {
 			break
 		}
 		total += r
+
+		if err = ctn.refreshDeadline(); err != nil {
+			break
+		}
 	}
 
 	if err == nil {
@@ -80,6 +178,69 @@ func (ctn *Connection) Write(buf []byte) (total int, err error) {
 
 // Read reads from connection buffer to the provided slice.
 func (ctn *Connection) Read(buf []byte, length int) (total int, err error) {
+	if ctn.compressed {
+		return ctn.readMessages(buf, length)
+	}
+	return ctn.read(buf, length)
+}
+
+// setCompressed determines if the responses to the next
+// command may be compressed.
+func (ctn *Connection) setCompressed(compressed bool) {
+	ctn.compressed = compressed
+	ctn.pending = nil
+}
+
+// readMessages reads from the inflated proto messages of the response.
+func (ctn *Connection) readMessages(buf []byte, length int) (total int, err error) {
+	for total < length {
+		if len(ctn.pending) == 0 {
+			if err := ctn.readMessage(); err != nil {
+				return total, err
+			}
+		}
+
+		n := copy(buf[total:length], ctn.pending)
+		ctn.pending = ctn.pending[n:]
+		total += n
+	}
+	return total, nil
+}
+
+// readMessage reads the next proto message into pending,
+// and inflates it if it is compressed.
+func (ctn *Connection) readMessage() error {
+	header := make([]byte, 8)
+	if _, err := ctn.read(header, len(header)); err != nil {
+		return err
+	}
+
+	proto := Buffer.BytesToInt64(header, 0)
+	size := int(proto & 0xFFFFFFFFFFFF)
+	if size > _MAX_BUFFER_SIZE {
+		return NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid message size: %d", size))
+	}
+
+	body := make([]byte, size)
+	if _, err := ctn.read(body, size); err != nil {
+		return err
+	}
+
+	if (proto>>48)&0xFF == _AS_MSG_TYPE_COMPRESSED {
+		msg, err := decompressMessage(body)
+		if err != nil {
+			return err
+		}
+		ctn.pending = msg
+		return nil
+	}
+
+	ctn.pending = append(header, body...)
+	return nil
+}
+
+// read reads length bytes from the socket.
+func (ctn *Connection) read(buf []byte, length int) (total int, err error) {
 	// if all bytes are not read, retry until successful
 	// Don't worry about the loop; we've already set the timeout elsewhere
 	var r int
@@ -88,6 +249,10 @@ func (ctn *Connection) Read(buf []byte, length int) (total int, err error) {
 			break
 		}
 		total += r
+
+		if err = ctn.refreshDeadline(); err != nil {
+			break
+		}
 	}
 
 	if err == nil && total == length {
@@ -105,18 +270,22 @@ func (ctn *Connection) IsConnected() bool {
 }
 
 // SetTimeout sets connection timeout for both read and write operations.
+// Any socket timeout set before is cleared.
 func (ctn *Connection) SetTimeout(timeout time.Duration) error {
+	ctn.socketTimeout = 0
+	ctn.deadline = time.Time{}
+	ctn.aborted.Set(false)
+
 	// Set timeout ONLY if there is or has been a timeout
 	if timeout > 0 || ctn.timeout != 0 {
 		ctn.timeout = timeout
 
 		// important: remove deadline when not needed; connections are pooled
 		if ctn.conn != nil {
-			var deadline time.Time
 			if timeout > 0 {
-				deadline = time.Now().Add(timeout)
+				ctn.deadline = time.Now().Add(timeout)
 			}
-			if err := ctn.conn.SetDeadline(deadline); err != nil {
+			if err := ctn.conn.SetDeadline(ctn.deadline); err != nil {
 				return err
 			}
 		}
@@ -125,6 +294,67 @@ func (ctn *Connection) SetTimeout(timeout time.Duration) error {
 	return nil
 }
 
+// setSocketTimeout limits how long each socket read or write may block.
+// Unlike the timeout, the deadline is refreshed whenever a partial read or
+// write makes progress, but it never extends beyond the deadline of the timeout.
+// Zero disables the socket timeout.
+func (ctn *Connection) setSocketTimeout(timeout time.Duration) error {
+	ctn.socketTimeout = timeout
+	return ctn.refreshDeadline()
+}
+
+// refreshDeadline moves the socket deadline forward by the socket timeout.
+func (ctn *Connection) refreshDeadline() error {
+	if ctn.socketTimeout <= 0 || ctn.conn == nil {
+		return nil
+	}
+
+	deadline := time.Now().Add(ctn.socketTimeout)
+	if !ctn.deadline.IsZero() && ctn.deadline.Before(deadline) {
+		deadline = ctn.deadline
+	}
+	if err := ctn.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	// the deadline may have been expired by bindContext in the meantime
+	if ctn.aborted.Get() {
+		return ctn.conn.SetDeadline(time.Unix(1, 0))
+	}
+	return nil
+}
+
+// bindContext aborts blocking reads and writes on the connection as soon as
+// ctx is done, by expiring the socket deadline.
+// The returned function stops watching the context and reports whether the
+// deadline was expired; such a connection must not be put back in the pool.
+func (ctn *Connection) bindContext(ctx context.Context) (release func() bool) {
+	if ctx.Done() == nil || ctn.conn == nil {
+		return func() bool { return false }
+	}
+
+	conn := ctn.conn
+	done := make(chan struct{})
+	fired := make(chan bool, 1)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			// a deadline in the past unblocks any pending IO immediately
+			ctn.aborted.Set(true)
+			conn.SetDeadline(time.Unix(1, 0))
+			fired <- true
+		case <-done:
+			fired <- false
+		}
+	}()
+
+	return func() bool {
+		close(done)
+		return <-fired
+	}
+}
+
 // Close closes the connection
 func (ctn *Connection) Close() {
 	if ctn != nil && ctn.conn != nil {
@@ -132,5 +362,9 @@ func (ctn *Connection) Close() {
 			Logger.Warn(err.Error())
 		}
 		ctn.conn = nil
+
+		if ctn.node != nil {
+			ctn.node.stats.connectionsOpen.DecrementAndGet()
+		}
 	}
 }
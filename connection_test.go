@@ -0,0 +1,64 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Connection Test", func() {
+
+	Context("TLS", func() {
+
+		It("must verify the server against the seed host when no TLS name is set", func() {
+			serverNames := make(chan string, 1)
+			config := &tls.Config{
+				GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+					serverNames <- hello.ServerName
+					return nil, errors.New("no certificate")
+				},
+			}
+
+			ln, err := tls.Listen("tcp", "127.0.0.1:0", config)
+			Expect(err).ToNot(HaveOccurred())
+			defer ln.Close()
+
+			go func() {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+
+			_, port, err := net.SplitHostPort(ln.Addr().String())
+			Expect(err).ToNot(HaveOccurred())
+
+			// the handshake fails, but only after the client hello was sent
+			_, err = NewSecureConnection(net.JoinHostPort("localhost", port), time.Second, &tls.Config{}, "")
+			Expect(err).To(HaveOccurred())
+			Expect(<-serverNames).To(Equal("localhost"))
+		})
+
+	})
+
+})
@@ -17,6 +17,7 @@ package aerospike
 type executeCommand struct {
 	*readCommand
 
+	writePolicy  *WritePolicy
 	packageName  string
 	functionName string
 	args         []Value
@@ -32,16 +33,25 @@ func newExecuteCommand(
 ) *executeCommand {
 	return &executeCommand{
 		readCommand:  newReadCommand(cluster, policy, key, nil),
+		writePolicy:  policy,
 		packageName:  packageName,
 		functionName: functionName,
 		args:         args,
 	}
 }
 
+// UDFs may write to the record, so they are always sent to the master node.
+func (cmd *executeCommand) getNode(ifc command) (*Node, error) {
+	return cmd.cluster.GetNode(cmd.partition)
+}
+
 func (cmd *executeCommand) writeBuffer(ifc command) error {
-	return cmd.setUdf(cmd.key, cmd.packageName, cmd.functionName, cmd.args)
+	return cmd.setUdf(cmd.writePolicy, cmd.key, cmd.packageName, cmd.functionName, cmd.args)
 }
 
 func (cmd *executeCommand) Execute() error {
+	if err := cmd.cluster.validateExpiration(cmd.writePolicy.Expiration); err != nil {
+		return err
+	}
 	return cmd.execute(cmd)
 }
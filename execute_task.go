@@ -86,6 +86,9 @@ func (etsk *ExecuteTask) IsDone() (bool, error) {
 		status := response[:end]
 
 		if status == "ABORTED" {
+			if etsk.scan {
+				return false, NewAerospikeError(SCAN_TERMINATED)
+			}
 			return false, NewAerospikeError(QUERY_TERMINATED)
 		} else if status == "IN PROGRESS" {
 			return false, nil
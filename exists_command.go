@@ -46,6 +46,10 @@ func (cmd *existsCommand) getPolicy(ifc command) Policy {
 	return cmd.policy.GetBasePolicy()
 }
 
+func (cmd *existsCommand) getNode(ifc command) (*Node, error) {
+	return cmd.getReadNode(cmd.policy)
+}
+
 func (cmd *existsCommand) writeBuffer(ifc command) error {
 	return cmd.setExists(cmd.key)
 }
@@ -15,14 +15,19 @@
 package aerospike
 
 import (
+	"fmt"
+
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
 	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
 )
 
 // Filter specifies a query filter definition.
 type Filter struct {
-	name  string
-	begin Value
-	end   Value
+	name      string
+	idxType   IndexCollectionType
+	valueType int
+	begin     Value
+	end       Value
 }
 
 // NewEqualFilter creates a new equality filter instance for query.
@@ -38,14 +43,65 @@ func NewRangeFilter(binName string, begin int64, end int64) *Filter {
 	return newFilter(binName, NewValue(begin), NewValue(end))
 }
 
+// NewFloatRangeFilter creates a range filter for query over float64 bin values.
+// The bin must be indexed by a server which supports indexes on float values.
+func NewFloatRangeFilter(binName string, begin float64, end float64) *Filter {
+	return newFilter(binName, NewFloatValue(begin), NewFloatValue(end))
+}
+
+// NewContainsFilter creates a contains filter for query on collection index.
+// The bin must be indexed with the same IndexCollectionType, e.g. ICT_LIST
+// to select the records whose list bin contains the value.
+func NewContainsFilter(binName string, indexCollectionType IndexCollectionType, value interface{}) *Filter {
+	val := NewValue(value)
+	fltr := newFilter(binName, val, val)
+	fltr.idxType = indexCollectionType
+	return fltr
+}
+
+// NewContainsRangeFilter creates a contains filter for query on ranges of data in a collection index.
+// Range arguments must be int64 values.
+func NewContainsRangeFilter(binName string, indexCollectionType IndexCollectionType, begin, end int64) *Filter {
+	fltr := newFilter(binName, NewValue(begin), NewValue(end))
+	fltr.idxType = indexCollectionType
+	return fltr
+}
+
+// IndexCollectionType returns the collection type of the index the filter applies to.
+func (fltr *Filter) IndexCollectionType() IndexCollectionType {
+	return fltr.idxType
+}
+
+// NewGeoWithinRegionFilter creates a geospatial filter for query,
+// selecting the points of the bin that are within the GeoJSON region.
+// The bin must be indexed with a GEO2DSPHERE index.
+func NewGeoWithinRegionFilter(binName, region string) *Filter {
+	v := NewStringValue(region)
+	return &Filter{
+		name:      binName,
+		valueType: ParticleType.GEOJSON,
+		begin:     v,
+		end:       v,
+	}
+}
+
+// NewGeoWithinRadiusFilter creates a geospatial filter for query,
+// selecting the points of the bin that are within radius meters
+// of the specified latitude and longitude.
+// The bin must be indexed with a GEO2DSPHERE index.
+func NewGeoWithinRadiusFilter(binName string, lat, lng, radius float64) *Filter {
+	return NewGeoWithinRegionFilter(binName, fmt.Sprintf("{ \"type\": \"AeroCircle\", \"coordinates\": [[%.8f, %.8f], %f] }", lng, lat, radius))
+}
+
 // Create a filter for query.
 // Range arguments must be longs or integers which can be cast to longs.
 // String ranges are not supported.
 func newFilter(name string, begin Value, end Value) *Filter {
 	return &Filter{
-		name:  name,
-		begin: begin,
-		end:   end,
+		name:      name,
+		valueType: begin.GetType(),
+		begin:     begin,
+		end:       end,
 	}
 }
 
@@ -63,7 +119,7 @@ func (fltr *Filter) write(buf []byte, offset int) (int, error) {
 	offset += len + 1
 
 	// Write particle type.
-	buf[offset] = byte(fltr.begin.GetType())
+	buf[offset] = byte(fltr.valueType)
 	offset++
 
 	// Write filter begin.
@@ -31,5 +31,8 @@ const (
 	// DUPLICATE means: Create duplicate record if expected generation is not equal to server generation.
 	// Duplicates are only created when the server configuration option "allow-versions"
 	// is true (default is false).
+	//
+	// Deprecated: servers which support durable deletes reuse its flag, and
+	// interpret it as WritePolicy.DurableDelete.
 	DUPLICATE
 )
@@ -0,0 +1,74 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// HLL operation codes.
+const (
+	_HLL_INIT       = 0
+	_HLL_ADD        = 1
+	_HLL_SET_UNION  = 2
+	_HLL_COUNT      = 50
+	_HLL_SIMILARITY = 54
+)
+
+// hllValues converts HLL values to an array value.
+func hllValues(hlls []*HLLValue) *ValueArray {
+	values := make([]Value, len(hlls))
+	for i := range hlls {
+		values[i] = hlls[i]
+	}
+	return NewValueArray(values)
+}
+
+// HLLInitOp creates an operation which creates a new, empty HyperLogLog bin,
+// or resets an existing one. indexBits determines the number of registers
+// of the HLL, and therefore its precision; it must be between 4 and 16.
+// The operation does not return a result.
+// HLL operations are supported by Aerospike 4.9 servers and later.
+func HLLInitOp(binName string, indexBits int) *Operation {
+	return &Operation{OpType: HLL_MODIFY, BinName: &binName, BinValue: newPackedOpValue(_HLL_INIT, NewIntegerValue(indexBits), NewIntegerValue(-1), NewIntegerValue(0))}
+}
+
+// HLLAddOp creates an operation which adds the values to the HyperLogLog
+// in the bin. The bin must have been created with HLLInitOp.
+// It returns the number of values which updated the HLL.
+func HLLAddOp(binName string, values ...interface{}) *Operation {
+	list := make([]Value, len(values))
+	for i := range values {
+		list[i] = NewValue(values[i])
+	}
+	return &Operation{OpType: HLL_MODIFY, BinName: &binName, BinValue: newPackedOpValue(_HLL_ADD, NewValueArray(list), NewIntegerValue(-1), NewIntegerValue(-1), NewIntegerValue(0))}
+}
+
+// HLLGetCountOp creates an operation which returns the estimated number of
+// distinct values added to the HyperLogLog in the bin, as an int64.
+func HLLGetCountOp(binName string) *Operation {
+	opValue := newPackedOpValue(_HLL_COUNT)
+	opValue.int64Result = true
+	return &Operation{OpType: HLL_READ, BinName: &binName, BinValue: opValue}
+}
+
+// HLLMergeOp creates an operation which sets the HyperLogLog in the bin to the
+// union of itself and the HLLs. The HLLs must have the same number of index bits.
+// The operation does not return a result.
+func HLLMergeOp(binName string, hlls ...*HLLValue) *Operation {
+	return &Operation{OpType: HLL_MODIFY, BinName: &binName, BinValue: newPackedOpValue(_HLL_SET_UNION, hllValues(hlls), NewIntegerValue(0))}
+}
+
+// HLLSimilarityOp creates an operation which returns the estimated similarity
+// of the HyperLogLog in the bin and the HLLs, as a float64 between 0 and 1.
+func HLLSimilarityOp(binName string, hlls ...*HLLValue) *Operation {
+	return &Operation{OpType: HLL_READ, BinName: &binName, BinValue: newPackedOpValue(_HLL_SIMILARITY, hllValues(hlls))}
+}
@@ -27,6 +27,10 @@ type Host struct {
 	// Port of database server.
 	Port int
 
+	// TLSName is the name the server presents in its TLS certificate.
+	// It is used for SNI and certificate validation when TLS is enabled.
+	TLSName string
+
 	addPort string
 }
 
@@ -23,4 +23,7 @@ const (
 
 	// STRING specifies an index on string values.
 	STRING IndexType = "STRING"
+
+	// GEO2DSPHERE specifies an index on GeoJSON values.
+	GEO2DSPHERE IndexType = "GEO2DSPHERE"
 )
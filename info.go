@@ -36,18 +36,7 @@ type info struct {
 
 // RequestNodeInfo gets info values by name from the specified database server node.
 func RequestNodeInfo(node *Node, name ...string) (map[string]string, error) {
-	conn, err := node.GetConnection(_DEFAULT_TIMEOUT)
-	if err != nil {
-		return nil, err
-	}
-
-	response, err := RequestInfo(conn, name...)
-	if err != nil {
-		conn.Close()
-		return nil, err
-	}
-	node.PutConnection(conn)
-	return response, nil
+	return node.RequestInfo(name...)
 }
 
 // RequestNodeStats returns statistics for the specified node as a map
@@ -69,6 +69,12 @@ func (ky *Key) Digest() []byte {
 	return ky.digest
 }
 
+// hasValueToSend returns true if the key has a user key value
+// which can be sent to the server.
+func (ky *Key) hasValueToSend() bool {
+	return ky.userKey != nil && ky.userKey.GetType() != ParticleType.NULL
+}
+
 // Equals uses key digests to compare key equality.
 func (ky *Key) Equals(other *Key) bool {
 	return bytes.Equal(ky.digest, other.digest)
@@ -97,13 +103,18 @@ func NewKey(namespace string, setName string, key interface{}) (newKey *Key, err
 	return newKey, err
 }
 
-// NewKey initializes a key from namespace, optional set name and user key.
-// The server handles record identifiers by digest only.
+// NewKeyWithDigest initializes a key from namespace, optional set name, optional user key
+// and a precomputed 20 byte digest. The digest is sent to the server as-is.
+// Pass a nil user key to identify records whose original key is not known;
+// such keys are never sent to the server, even if the policy's SendKey is set.
 func NewKeyWithDigest(namespace string, setName string, key interface{}, digest []byte) (newKey *Key, err error) {
 	newKey = &Key{
 		namespace: namespace,
 		setName:   setName,
-		userKey:   NewValue(key),
+	}
+
+	if key != nil {
+		newKey.userKey = NewValue(key)
 	}
 
 	if err = newKey.SetDigest(digest); err != nil {
@@ -112,10 +123,10 @@ func NewKeyWithDigest(namespace string, setName string, key interface{}, digest
 	return newKey, err
 }
 
-//Set custom hash
+// SetDigest replaces the digest of the key with a precomputed 20 byte digest.
 func (ky *Key) SetDigest(digest []byte) error {
 	if len(digest) != 20 {
-		return NewAerospikeError(PARAMETER_ERROR, "Invalid digest: not 20 byte")
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid digest: expected 20 bytes, got %d", len(digest)))
 	}
 	ky.digest = digest
 	return nil
@@ -147,6 +147,16 @@ var _ = Describe("Key Test", func() {
 			Expect(key.Digest()).To(Equal([]byte("01234567890123456789")))
 		})
 
+		It("for digest-only keys", func() {
+			key, err := NewKeyWithDigest("namespace", "set", nil, []byte("01234567890123456789"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(key.Value()).To(BeNil())
+			Expect(key.Digest()).To(Equal([]byte("01234567890123456789")))
+
+			_, err = NewKeyWithDigest("namespace", "set", nil, []byte("0123456789"))
+			Expect(err).To(HaveOccurred())
+		})
+
 	})
 
 })
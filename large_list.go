@@ -42,7 +42,9 @@ func (ll *LargeList) Add(values ...interface{}) error {
 	return err
 }
 
-// Update updates/adds each value in values list depending if key exists or not.
+// Update replaces the elements of the list which have the same key as values, in place.
+// If an element with the key of a value does not exist, an error with the
+// LARGE_ITEM_NOT_FOUND result code is returned.
 func (ll *LargeList) Update(values ...interface{}) error {
 	var err error
 	if len(values) == 1 {
@@ -50,7 +52,7 @@ func (ll *LargeList) Update(values ...interface{}) error {
 	} else {
 		_, err = ll.client.Execute(ll.policy, ll.key, ll.packageName(), "update_all", ll.binName, ToValueArray(values), ll.userModule)
 	}
-	return err
+	return ldtItemError(err, "Large list element to update not found")
 }
 
 // Remove deletes value from list.
@@ -59,6 +61,15 @@ func (ll *LargeList) Remove(value interface{}) error {
 	return err
 }
 
+// Exists checks existence of value in the list.
+func (ll *LargeList) Exists(value interface{}) (bool, error) {
+	ret, err := ll.client.Execute(ll.policy, ll.key, ll.packageName(), "exists", ll.binName, NewValue(value))
+	if err != nil {
+		return false, err
+	}
+	return ldtBool(ret), nil
+}
+
 // Find selects values from list.
 func (ll *LargeList) Find(value interface{}) ([]interface{}, error) {
 	res, err := ll.client.Execute(ll.policy, ll.key, ll.packageName(), "find", ll.binName, NewValue(value))
@@ -90,15 +101,44 @@ func (ll *LargeList) Scan() ([]interface{}, error) {
 	return ll.scan(ll)
 }
 
-// Filter selects values from list and apply specified Lua filter.
-func (ll *LargeList) Filter(filterName string, filterArgs ...interface{}) ([]interface{}, error) {
-	res, err := ll.client.Execute(ll.policy, ll.key, ll.packageName(), "filter", ll.binName, ll.userModule, NewValue(filterName), ToValueArray(filterArgs))
+// Range selects values from list between from and to bounds, inclusive.
+// A nil bound leaves the range open-ended on that side.
+func (ll *LargeList) Range(from, to Value) ([]interface{}, error) {
+	if from == nil {
+		from = NewNullValue()
+	}
+	if to == nil {
+		to = NewNullValue()
+	}
+
+	res, err := ll.client.Execute(ll.policy, ll.key, ll.packageName(), "range", ll.binName, from, to)
 	if err != nil {
 		return nil, err
 	}
 
 	if res == nil {
-		return nil, nil
+		return []interface{}{}, nil
+	}
+	return res.([]interface{}), err
+}
+
+// Filter selects values from list and applies the Lua filter function filterName
+// from filterModule to them on the server, passing args to the filter.
+// If filterModule is empty, the list's userModule is used.
+// An empty slice is returned when no values match.
+func (ll *LargeList) Filter(filterModule, filterName string, args ...Value) ([]interface{}, error) {
+	module := ll.userModule
+	if filterModule != "" {
+		module = NewStringValue(filterModule)
+	}
+
+	res, err := ll.client.Execute(ll.policy, ll.key, ll.packageName(), "filter", ll.binName, module, NewStringValue(filterName), NewValueArray(args))
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return []interface{}{}, nil
 	}
 	return res.([]interface{}), err
 }
@@ -108,6 +108,90 @@ var _ = Describe("LargeList Test", func() {
 		Expect(len(scanResult)).To(Equal(0))
 	})
 
+	It("should select values with Range()", func() {
+		llist := client.GetLargeList(wpolicy, key, randString(10), "")
+		for i := 1; i <= 10; i++ {
+			err = llist.Add(NewValue(i))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		rangeResult, err := llist.Range(NewValue(3), NewValue(5))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rangeResult).To(Equal([]interface{}{3, 4, 5}))
+
+		rangeResult, err = llist.Range(nil, NewValue(2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rangeResult).To(Equal([]interface{}{1, 2}))
+
+		rangeResult, err = llist.Range(NewValue(9), nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rangeResult).To(Equal([]interface{}{9, 10}))
+
+		rangeResult, err = llist.Range(NewValue(100), NewValue(200))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rangeResult).ToNot(BeNil())
+		Expect(len(rangeResult)).To(Equal(0))
+	})
+
+	It("should Update() existing elements in place and check them with Exists()", func() {
+		llist := client.GetLargeList(wpolicy, key, randString(10), "")
+		for i := 1; i <= 3; i++ {
+			err = llist.Add(map[interface{}]interface{}{"key": i, "version": 1})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		err = llist.Update(NewValue(map[interface{}]interface{}{"key": 2, "version": 2}))
+		Expect(err).ToNot(HaveOccurred())
+
+		findResult, err := llist.Find(map[interface{}]interface{}{"key": 2})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findResult).To(Equal([]interface{}{map[interface{}]interface{}{"key": 2, "version": 2}}))
+
+		sz, err := llist.Size()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sz).To(Equal(3))
+
+		err = llist.Update(NewValue(map[interface{}]interface{}{"key": 4, "version": 2}))
+		Expect(err).To(HaveOccurred())
+		Expect(err.(AerospikeError).ResultCode()).To(Equal(LARGE_ITEM_NOT_FOUND))
+
+		exists, err := llist.Exists(map[interface{}]interface{}{"key": 3})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		exists, err = llist.Exists(map[interface{}]interface{}{"key": 4})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should select values with Filter()", func() {
+		const filterBody = `function greaterThan(value, args)
+	if value > args[1] then
+		return value
+	end
+	return nil
+end`
+
+		regTask, err := client.RegisterUDF(nil, []byte(filterBody), "llistFilter.lua", LUA)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-regTask.OnComplete()).ToNot(HaveOccurred())
+
+		llist := client.GetLargeList(wpolicy, key, randString(10), "")
+		for i := 1; i <= 10; i++ {
+			err = llist.Add(NewValue(i))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		filterResult, err := llist.Filter("llistFilter", "greaterThan", NewValue(7))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filterResult).To(Equal([]interface{}{8, 9, 10}))
+
+		filterResult, err = llist.Filter("llistFilter", "greaterThan", NewValue(100))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filterResult).ToNot(BeNil())
+		Expect(len(filterResult)).To(Equal(0))
+	})
+
 	It("should correctly GetConfig()", func() {
 		llist := client.GetLargeList(wpolicy, key, randString(10), "")
 		err = llist.Add(NewValue(0))
@@ -14,6 +14,12 @@
 
 package aerospike
 
+import (
+	"fmt"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
 // LargeMap encapsulates a map within a single bin.
 type LargeMap struct {
 	*baseLargeObject
@@ -40,8 +46,24 @@ func (lm *LargeMap) Put(name interface{}, value interface{}) error {
 // PutMap adds map values to the map.
 // If the map does not exist, create it using specified userModule configuration.
 func (lm *LargeMap) PutMap(theMap map[interface{}]interface{}) error {
-	_, err := lm.client.Execute(lm.policy, lm.key, lm.packageName(), "put_all", lm.binName, NewMapValue(theMap), lm.userModule)
-	return err
+	return lm.PutAll(theMap)
+}
+
+// PutAll adds all entries of m to the map in a single UDF invocation.
+// If the map does not exist, create it using specified userModule configuration.
+// If the server reports that only some of the entries were written,
+// the returned error states how many.
+func (lm *LargeMap) PutAll(m map[interface{}]interface{}) error {
+	res, err := lm.client.Execute(lm.policy, lm.key, lm.packageName(), "put_all", lm.binName, NewMapValue(m), lm.userModule)
+	if err != nil {
+		return err
+	}
+
+	// put_all returns 0 on success; a positive result is the number of entries written.
+	if written, ok := res.(int); ok && written > 0 && written < len(m) {
+		return NewAerospikeError(UDF_BAD_RESPONSE, fmt.Sprintf("put_all wrote %d of %d entries", written, len(m)))
+	}
+	return nil
 }
 
 // Get returns  value from map corresponding with the provided key.
@@ -70,6 +70,26 @@ var _ = Describe("LargeMap Test", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should create a valid LargeMap; Support PutAll() and Scan()", func() {
+		lmap := client.GetLargeMap(wpolicy, key, randString(10), "")
+
+		testMap := make(map[interface{}]interface{})
+		for i := 1; i <= 100; i++ {
+			testMap[i] = i * 10
+		}
+
+		err = lmap.PutAll(testMap)
+		Expect(err).ToNot(HaveOccurred())
+
+		sz, err := lmap.Size()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sz).To(Equal(len(testMap)))
+
+		resMap, err := lmap.Scan()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resMap).To(Equal(testMap))
+	})
+
 	It("should create a valid LargeMap; Support Put(), Get(), Remove(), Find(), Size(), Scan() and GetCapacity()", func() {
 		lmap := client.GetLargeMap(wpolicy, key, randString(10), "")
 		res, err := lmap.Size()
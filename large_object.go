@@ -14,6 +14,12 @@
 
 package aerospike
 
+import (
+	"strings"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
 // LargeObject interface defines methods to work with LDTs.
 type LargeObject interface {
 	packageName() string
@@ -124,3 +130,30 @@ func (lo *baseLargeObject) scan(ifc LargeObject) ([]interface{}, error) {
 	}
 	return ret.([]interface{}), nil
 }
+
+// ldtBool converts the result of a Lua function to a boolean.
+// Depending on the server version, Lua booleans are returned either as booleans or integers.
+func ldtBool(ret interface{}) bool {
+	switch v := ret.(type) {
+	case bool:
+		return v
+	case int:
+		return v != 0
+	case int64:
+		return v != 0
+	}
+	return false
+}
+
+// ldtItemError converts the UDF failures the LDT modules report for missing
+// elements to an error with the LARGE_ITEM_NOT_FOUND result code.
+func ldtItemError(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == UDF_BAD_RESPONSE && strings.Contains(ae.Error(), "Not Found") {
+		return NewAerospikeError(LARGE_ITEM_NOT_FOUND, msg+": "+ae.Error())
+	}
+	return err
+}
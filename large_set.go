@@ -60,7 +60,7 @@ func (ls *LargeSet) Exists(value interface{}) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return (ret == 1), nil
+	return ldtBool(ret), nil
 }
 
 // Scan returns all objects in the set.
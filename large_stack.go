@@ -33,13 +33,21 @@ func (lstk *LargeStack) packageName() string {
 
 // Push pushes values onto stack.
 // If the stack does not exist, create it using specified userModule configuration.
+// Server errors, like pushing past the capacity of the stack, are returned as is.
 func (lstk *LargeStack) Push(values ...interface{}) error {
-	var err error
-	if len(values) == 1 {
-		_, err = lstk.client.Execute(lstk.policy, lstk.key, lstk.packageName(), "push", lstk.binName, NewValue(values[0]), lstk.userModule)
-	} else {
-		_, err = lstk.client.Execute(lstk.policy, lstk.key, lstk.packageName(), "push_all", lstk.binName, ToValueArray(values), lstk.userModule)
+	if len(values) != 1 {
+		return lstk.PushAll(values)
 	}
+
+	_, err := lstk.client.Execute(lstk.policy, lstk.key, lstk.packageName(), "push", lstk.binName, NewValue(values[0]), lstk.userModule)
+	return err
+}
+
+// PushAll pushes all values onto stack in a single command.
+// If the stack does not exist, create it using specified userModule configuration.
+// Server errors, like pushing past the capacity of the stack, are returned as is.
+func (lstk *LargeStack) PushAll(values []interface{}) error {
+	_, err := lstk.client.Execute(lstk.policy, lstk.key, lstk.packageName(), "push_all", lstk.binName, ToValueArray(values), lstk.userModule)
 	return err
 }
 
@@ -100,6 +100,33 @@ var _ = Describe("LargeStack Test", func() {
 		Expect(len(scanResult)).To(Equal(0))
 	})
 
+	It("should PushAll() values, and Peek() them without removing them before Pop()", func() {
+		lstack := client.GetLargeStack(wpolicy, key, randString(10), "")
+		err = lstack.PushAll([]interface{}{1, 2, 3, 4, 5})
+		Expect(err).ToNot(HaveOccurred())
+
+		sz, err := lstack.Size()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sz).To(Equal(5))
+
+		v, err := lstack.Peek(2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v).To(Equal([]interface{}{5, 4}))
+
+		// peek must not change the stack
+		sz, err = lstack.Size()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sz).To(Equal(5))
+
+		v, err = lstack.Pop(2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(v).To(Equal([]interface{}{5, 4}))
+
+		sz, err = lstack.Size()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sz).To(Equal(3))
+	})
+
 	It("should correctly GetConfig()", func() {
 		lstack := client.GetLargeStack(wpolicy, key, randString(10), "")
 		err = lstack.Push(NewValue(0))
@@ -0,0 +1,41 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ListReturnType determines what the list get by rank and by value operations return.
+type ListReturnType int
+
+const (
+	// LIST_RETURN_NONE does not return a result.
+	LIST_RETURN_NONE ListReturnType = 0
+
+	// LIST_RETURN_INDEX returns the index order of the items.
+	LIST_RETURN_INDEX ListReturnType = 1
+
+	// LIST_RETURN_REVERSE_INDEX returns the reverse index order of the items.
+	LIST_RETURN_REVERSE_INDEX ListReturnType = 2
+
+	// LIST_RETURN_RANK returns the value order of the items.
+	LIST_RETURN_RANK ListReturnType = 3
+
+	// LIST_RETURN_REVERSE_RANK returns the reverse value order of the items.
+	LIST_RETURN_REVERSE_RANK ListReturnType = 4
+
+	// LIST_RETURN_COUNT returns the count of the items selected, as an int.
+	LIST_RETURN_COUNT ListReturnType = 5
+
+	// LIST_RETURN_VALUE returns the values of the items.
+	LIST_RETURN_VALUE ListReturnType = 7
+)
@@ -0,0 +1,30 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ListSortFlags determines how ListSortOp sorts a list bin.
+// The flags can be combined with a bitwise or.
+type ListSortFlags int
+
+const (
+	// LIST_SORT_DEFAULT sorts the list in ascending order, and keeps duplicate values.
+	LIST_SORT_DEFAULT ListSortFlags = 0
+
+	// LIST_SORT_DESCENDING sorts the list in descending order.
+	LIST_SORT_DESCENDING ListSortFlags = 1
+
+	// LIST_SORT_DROP_DUPLICATES removes the duplicate values from the list.
+	LIST_SORT_DROP_DUPLICATES ListSortFlags = 2
+)
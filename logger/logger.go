@@ -31,9 +31,22 @@ const (
 	OFF LogPriority = 999
 )
 
+// GenericLogger is implemented by loggers which receive the client's log
+// messages in place of the standard *log.Logger, e.g. to route them
+// into a structured logging system.
+type GenericLogger interface {
+	Debug(format string, v ...interface{})
+	Info(format string, v ...interface{})
+	Warn(format string, v ...interface{})
+	Error(format string, v ...interface{})
+}
+
 type logger struct {
 	*log.Logger
 
+	// custom receives all messages when set, regardless of level
+	custom GenericLogger
+
 	level LogPriority
 	mutex sync.RWMutex
 }
@@ -56,6 +69,23 @@ func (lgr *logger) SetLogger(l *log.Logger) {
 	lgr.Logger = l
 }
 
+// SetCustomLogger sets a logger which receives all log messages regardless
+// of the log level, and is expected to filter them itself.
+// Pass nil to send messages to the *log.Logger again.
+func (lgr *logger) SetCustomLogger(l GenericLogger) {
+	lgr.mutex.Lock()
+	defer lgr.mutex.Unlock()
+
+	lgr.custom = l
+}
+
+func (lgr *logger) customLogger() GenericLogger {
+	lgr.mutex.RLock()
+	defer lgr.mutex.RUnlock()
+
+	return lgr.custom
+}
+
 // SetLevel sets logging level. Default is ERR.
 func (lgr *logger) SetLevel(level LogPriority) {
 	lgr.mutex.Lock()
@@ -68,40 +98,48 @@ func (lgr *logger) SetLevel(level LogPriority) {
 func (lgr *logger) LogAtLevel(level LogPriority, format string, v ...interface{}) {
 	switch level {
 	case DEBUG:
-		lgr.Debug(format, v)
+		lgr.Debug(format, v...)
 	case INFO:
-		lgr.Info(format, v)
+		lgr.Info(format, v...)
 	case WARNING:
-		lgr.Warn(format, v)
+		lgr.Warn(format, v...)
 	case ERR:
-		lgr.Error(format, v)
+		lgr.Error(format, v...)
 	}
 }
 
 // Debug logs a message if log level allows to do so.
 func (lgr *logger) Debug(format string, v ...interface{}) {
-	if lgr.level <= DEBUG {
+	if custom := lgr.customLogger(); custom != nil {
+		custom.Debug(format, v...)
+	} else if lgr.level <= DEBUG {
 		lgr.Logger.Printf(format, v...)
 	}
 }
 
 // Info logs a message if log level allows to do so.
 func (lgr *logger) Info(format string, v ...interface{}) {
-	if lgr.level <= INFO {
+	if custom := lgr.customLogger(); custom != nil {
+		custom.Info(format, v...)
+	} else if lgr.level <= INFO {
 		lgr.Logger.Printf(format, v...)
 	}
 }
 
 // Warn logs a message if log level allows to do so.
 func (lgr *logger) Warn(format string, v ...interface{}) {
-	if lgr.level <= WARNING {
+	if custom := lgr.customLogger(); custom != nil {
+		custom.Warn(format, v...)
+	} else if lgr.level <= WARNING {
 		lgr.Logger.Printf(format, v...)
 	}
 }
 
 // Error logs a message if log level allows to do so.
 func (lgr *logger) Error(format string, v ...interface{}) {
-	if lgr.level <= ERR {
+	if custom := lgr.customLogger(); custom != nil {
+		custom.Error(format, v...)
+	} else if lgr.level <= ERR {
 		lgr.Logger.Printf(format, v...)
 	}
 }
@@ -0,0 +1,30 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// MapOrder determines how the entries of a map bin are ordered on the server.
+// The values match the map flags used by the other Aerospike clients.
+type MapOrder int
+
+const (
+	// UNORDERED means the map entries are not ordered. This is the default.
+	UNORDERED MapOrder = 0
+
+	// KEY_ORDERED means the map entries are ordered by key.
+	KEY_ORDERED MapOrder = 1
+
+	// KEY_VALUE_ORDERED means the map entries are ordered by key, then by value.
+	KEY_VALUE_ORDERED MapOrder = 3
+)
@@ -0,0 +1,55 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// MapReturnType determines what the map get operations return.
+type MapReturnType int
+
+const (
+	// MAP_RETURN_NONE does not return a result.
+	MAP_RETURN_NONE MapReturnType = 0
+
+	// MAP_RETURN_INDEX returns the key index order of the items.
+	MAP_RETURN_INDEX MapReturnType = 1
+
+	// MAP_RETURN_REVERSE_INDEX returns the reverse key order of the items.
+	MAP_RETURN_REVERSE_INDEX MapReturnType = 2
+
+	// MAP_RETURN_RANK returns the value order of the items.
+	MAP_RETURN_RANK MapReturnType = 3
+
+	// MAP_RETURN_REVERSE_RANK returns the reverse value order of the items.
+	MAP_RETURN_REVERSE_RANK MapReturnType = 4
+
+	// MAP_RETURN_COUNT returns the count of the items selected, as an int.
+	MAP_RETURN_COUNT MapReturnType = 5
+
+	// MAP_RETURN_KEY returns the keys of the items.
+	MAP_RETURN_KEY MapReturnType = 6
+
+	// MAP_RETURN_VALUE returns the values of the items.
+	MAP_RETURN_VALUE MapReturnType = 7
+
+	// MAP_RETURN_KEY_VALUE returns the keys and values of the items,
+	// as a []MapPair in the order they were returned by the server.
+	MAP_RETURN_KEY_VALUE MapReturnType = 8
+)
+
+// MapPair is a key/value item of a map, returned by the map get
+// operations with MAP_RETURN_KEY_VALUE.
+type MapPair struct {
+	Key   interface{}
+	Value interface{}
+}
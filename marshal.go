@@ -0,0 +1,372 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// aerospikeTag is the struct field tag used to name the bin a field maps to.
+const aerospikeTag = "as"
+
+// time.Time values are stored as nanoseconds since the Unix epoch.
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldAlias returns the bin name for a struct field.
+// An empty string means the field must be skipped.
+func fieldAlias(f reflect.StructField) string {
+	// unexported fields are ignored
+	if f.PkgPath != "" {
+		return ""
+	}
+
+	tag := strings.TrimSpace(f.Tag.Get(aerospikeTag))
+	if tag == "-" {
+		return ""
+	}
+	if tag != "" {
+		return tag
+	}
+	return f.Name
+}
+
+// structValue dereferences obj and makes sure it is a struct.
+func structValue(obj interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(obj)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return rv, NewAerospikeError(PARAMETER_ERROR, "Object must not be nil")
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return rv, NewAerospikeError(PARAMETER_ERROR, "Object must be a struct or a pointer to a struct")
+	}
+	return rv, nil
+}
+
+// marshalObject converts the exported fields of a struct into bins.
+func marshalObject(obj interface{}) (BinMap, error) {
+	rv, err := structValue(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	bins := BinMap{}
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		alias := fieldAlias(rt.Field(i))
+		if alias == "" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		switch fv.Kind() {
+		case reflect.Bool:
+			return nil, NewAerospikeError(TYPE_NOT_SUPPORTED, "Field `"+rt.Field(i).Name+"` of type "+fv.Type().String()+" can not be stored in a bin")
+		}
+
+		val, err := marshalValue(fv)
+		if err != nil {
+			return nil, err
+		}
+		bins[alias] = val
+	}
+	return bins, nil
+}
+
+// marshalValue converts a reflected value into a type supported by NewValue.
+// Structs are converted to maps, slices and arrays to lists.
+func marshalValue(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return marshalValue(v.Elem())
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.Interface().(time.Time).UnixNano(), nil
+		}
+
+		rt := v.Type()
+		m := make(map[interface{}]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			alias := fieldAlias(rt.Field(i))
+			if alias == "" {
+				continue
+			}
+
+			val, err := marshalValue(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			m[alias] = val
+		}
+		return m, nil
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes(), nil
+		}
+		fallthrough
+
+	case reflect.Array:
+		l := make([]interface{}, v.Len())
+		for i := range l {
+			val, err := marshalValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			l[i] = val
+		}
+		return l, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		m := make(map[interface{}]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			key, err := marshalValue(k)
+			if err != nil {
+				return nil, err
+			}
+			val, err := marshalValue(v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			m[key] = val
+		}
+		return m, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return nil, NewAerospikeError(TYPE_NOT_SUPPORTED, "Unsigned value out of range")
+		}
+		return int64(v.Uint()), nil
+
+	case reflect.String:
+		return v.String(), nil
+
+	case reflect.Bool:
+		return v.Bool(), nil
+
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+
+	return nil, NewAerospikeError(TYPE_NOT_SUPPORTED, "Value type '"+v.Type().String()+"' not supported")
+}
+
+// unmarshalObject populates the fields of the struct pointed to by obj from bins.
+func unmarshalObject(bins BinMap, obj interface{}) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return NewAerospikeError(PARAMETER_ERROR, "Object must be a non-nil pointer to a struct")
+	}
+
+	rv, err := structValue(obj)
+	if err != nil {
+		return err
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rv.NumField(); i++ {
+		alias := fieldAlias(rt.Field(i))
+		if alias == "" {
+			continue
+		}
+
+		if val, exists := bins[alias]; exists {
+			if err := unmarshalValue(rv.Field(i), val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshalValue sets dst to the value read from the database.
+// Maps are converted to structs, lists to slices and arrays.
+func unmarshalValue(dst reflect.Value, src interface{}) error {
+	if src == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	sv := reflect.ValueOf(src)
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := unmarshalValue(elem.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+
+	case reflect.Interface:
+		if sv.Type().AssignableTo(dst.Type()) {
+			dst.Set(sv)
+			return nil
+		}
+
+	case reflect.Struct:
+		if dst.Type() == timeType {
+			switch sv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				dst.Set(reflect.ValueOf(time.Unix(0, sv.Int())))
+				return nil
+			}
+			break
+		}
+
+		m, ok := src.(map[interface{}]interface{})
+		if !ok {
+			break
+		}
+
+		rt := dst.Type()
+		for i := 0; i < dst.NumField(); i++ {
+			alias := fieldAlias(rt.Field(i))
+			if alias == "" {
+				continue
+			}
+
+			if val, exists := m[alias]; exists {
+				if err := unmarshalValue(dst.Field(i), val); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+
+	case reflect.Slice:
+		if b, ok := src.([]byte); ok && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+
+		l, ok := src.([]interface{})
+		if !ok {
+			break
+		}
+
+		s := reflect.MakeSlice(dst.Type(), len(l), len(l))
+		for i := range l {
+			if err := unmarshalValue(s.Index(i), l[i]); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+		return nil
+
+	case reflect.Array:
+		l, ok := src.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < dst.Len() && i < len(l); i++ {
+			if err := unmarshalValue(dst.Index(i), l[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		m, ok := src.(map[interface{}]interface{})
+		if !ok {
+			break
+		}
+
+		dm := reflect.MakeMap(dst.Type())
+		for k, v := range m {
+			key := reflect.New(dst.Type().Key()).Elem()
+			if err := unmarshalValue(key, k); err != nil {
+				return err
+			}
+			val := reflect.New(dst.Type().Elem()).Elem()
+			if err := unmarshalValue(val, v); err != nil {
+				return err
+			}
+			dm.SetMapIndex(key, val)
+		}
+		dst.Set(dm)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch sv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetInt(sv.Int())
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dst.SetInt(int64(sv.Uint()))
+			return nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch sv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetUint(uint64(sv.Int()))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dst.SetUint(sv.Uint())
+			return nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch sv.Kind() {
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(sv.Float())
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetFloat(float64(sv.Int()))
+			return nil
+		}
+
+	case reflect.Bool:
+		switch sv.Kind() {
+		case reflect.Bool:
+			dst.SetBool(sv.Bool())
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetBool(sv.Int() != 0)
+			return nil
+		}
+
+	case reflect.String:
+		if s, ok := src.(string); ok {
+			dst.SetString(s)
+			return nil
+		}
+	}
+
+	return NewAerospikeError(PARSE_ERROR, "Can not assign value of type "+sv.Type().String()+" to "+dst.Type().String())
+}
@@ -14,6 +14,8 @@
 
 package aerospike
 
+import "time"
+
 // MultiPolicy contains parameters for policy attributes used in
 // query and scan operations.
 type MultiPolicy struct {
@@ -30,10 +32,26 @@ type MultiPolicy struct {
 	// Records received from multiple server nodes will be placed in a queue.
 	// A separate goroutine consumes these records in parallel.
 	// If the queue is full, the producer goroutines will block until records are consumed.
+	// A larger queue lets the client keep reading from the sockets ahead of a slow
+	// consumer, so the server does not time out the scan or query, at the cost of
+	// memory: up to this many records are queued for each node read in parallel,
+	// and for the merged channel. Zero makes the channels unbuffered.
 	RecordQueueSize int //= 5000
 
 	// Blocks until on-going migrations are over
 	WaitUntilMigrationsAreOver bool //=false
+
+	// TotalTimeout limits the duration of the whole scan or query.
+	// Once it has elapsed, the recordset is closed, the commands on all nodes
+	// are stopped and a TIMEOUT error is sent on the Errors channel.
+	// Default (0) means no limit.
+	TotalTimeout time.Duration //= 0
+
+	// RecordsPerSecond limits the rate at which each node returns records.
+	// It requires Aerospike server 4.7 or later for scans, and 6.0 or later for
+	// queries with filters; it is ignored with a warning on older servers.
+	// Default (0) means no limit.
+	RecordsPerSecond int //= 0
 }
 
 // NewMultiPolicy initializes a MultiPolicy instance with default values.
@@ -40,30 +40,64 @@ type Node struct {
 
 	connections *AtomicQueue //ArrayBlockingQueue<*Connection>
 	health      *AtomicInt   //AtomicInteger
-
-	partitionGeneration int
-	refreshCount        int
-	referenceCount      int
-	responded           bool
-	useNewInfo          bool
-	active              *AtomicBool
-	mutex               sync.RWMutex
+	stats       *nodeStats
+
+	partitionGeneration   int
+	refreshCount          int
+	referenceCount        int
+	responded             bool
+	useNewInfo            bool
+	supportsPredExp       bool
+	supportsTruncate      bool
+	supportsBatchIndex    bool
+	supportsCompression   bool
+	supportsPartitionScan bool
+	supportsBatchAny      bool
+	supportsPQuery        bool
+	supportsScanRPS       bool
+	supportsQueryRPS      bool
+	compressionWarning    sync.Once
+	rpsWarning            sync.Once
+	racks                 map[string]int // rack id of the node per namespace
+	features              []string       // features advertised by the node
+	tlsName               string
+	active                *AtomicBool
+	mutex                 sync.RWMutex
+
+	// session token new connections authenticate with, and the time it must be renewed
+	sessionToken      []byte
+	sessionExpiration time.Time
+	sessionMutex      sync.RWMutex
 }
 
 // NewNode initializes a server node with connection parameters.
 func newNode(cluster *Cluster, nv *nodeValidator) *Node {
 	return &Node{
-		cluster:    cluster,
-		name:       nv.name,
-		aliases:    nv.aliases,
-		address:    nv.address,
-		useNewInfo: nv.useNewInfo,
+		cluster:               cluster,
+		name:                  nv.name,
+		aliases:               nv.aliases,
+		address:               nv.address,
+		useNewInfo:            nv.useNewInfo,
+		supportsPredExp:       nv.supportsPredExp,
+		supportsTruncate:      nv.supportsTruncate,
+		supportsBatchIndex:    nv.supportsBatchIndex,
+		supportsCompression:   nv.supportsCompression,
+		supportsPartitionScan: nv.supportsPartitionScan,
+		supportsBatchAny:      nv.supportsBatchAny,
+		supportsPQuery:        nv.supportsPQuery,
+		supportsScanRPS:       nv.supportsScanRPS,
+		supportsQueryRPS:      nv.supportsQueryRPS,
+		features:              nv.features,
+		tlsName:               nv.tlsName,
+		sessionToken:          nv.sessionToken,
+		sessionExpiration:     nv.sessionExpiration,
 
 		// Assign host to first IP alias because the server identifies nodes
 		// by IP address (not hostname).
 		host:                nv.aliases[0],
 		connections:         NewAtomicQueue(cluster.connectionQueueSize),
 		health:              NewAtomicInt(_FULL_HEALTH),
+		stats:               newNodeStats(),
 		partitionGeneration: -1,
 		referenceCount:      0,
 		responded:           false,
@@ -82,7 +116,23 @@ func (nd *Node) Refresh() ([]*Host, error) {
 		return nil, err
 	}
 
-	infoMap, err := RequestInfo(conn, "node", "partition-generation", "services")
+	// renew the session before it expires, so that new connections can still authenticate
+	if nd.sessionExpired() {
+		if err := nd.login(conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	commands := []string{"node", "partition-generation", "features"}
+	if !nd.cluster.seedOnly {
+		commands = append(commands, nd.friendsInfoName())
+	}
+	if nd.cluster.rackAware {
+		commands = append(commands, "racks:")
+	}
+
+	infoMap, err := RequestInfo(conn, commands...)
 	if err != nil {
 		conn.Close()
 		nd.DecreaseHealth()
@@ -102,10 +152,119 @@ func (nd *Node) Refresh() ([]*Host, error) {
 	if err := nd.updatePartitions(conn, infoMap); err != nil {
 		return nil, err
 	}
+
+	if nd.cluster.rackAware {
+		nd.updateRacks(infoMap)
+	}
+
+	if features, exists := infoMap["features"]; exists {
+		nd.mutex.Lock()
+		nd.features = parseFeatures(features)
+		nd.mutex.Unlock()
+	}
+
 	nd.PutConnection(conn)
 	return friends, nil
 }
 
+// refreshPartitions requests the partition generation of the node, and
+// updates the partition map if it has changed since the last refresh.
+func (nd *Node) refreshPartitions() error {
+	conn, err := nd.GetConnection(1 * time.Second)
+	if err != nil {
+		return err
+	}
+
+	infoMap, err := RequestInfo(conn, "partition-generation")
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if err := nd.updatePartitions(conn, infoMap); err != nil {
+		conn.Close()
+		return err
+	}
+
+	nd.PutConnection(conn)
+	return nil
+}
+
+// updateRacks parses the response of the "racks:" info command, which lists
+// the nodes of each rack per namespace, and caches the racks of the node:
+// ns=<namespace>:rack_<id>=<node name>,<node name>:rack_<id>=...;ns=...
+func (nd *Node) updateRacks(infoMap map[string]string) {
+	response, exists := infoMap["racks:"]
+	if !exists {
+		Logger.Warn("Node %s: racks info not returned, rack awareness is disabled for this node", nd.String())
+		return
+	}
+
+	racks := make(map[string]int)
+	for _, nsRacks := range strings.Split(response, ";") {
+		parts := strings.Split(nsRacks, ":")
+		if len(parts) < 2 || !strings.HasPrefix(parts[0], "ns=") {
+			continue
+		}
+		namespace := strings.TrimPrefix(parts[0], "ns=")
+
+		for _, rack := range parts[1:] {
+			kv := strings.SplitN(rack, "=", 2)
+			if len(kv) != 2 || !strings.HasPrefix(kv[0], "rack_") {
+				continue
+			}
+
+			rackId, err := strconv.Atoi(strings.TrimPrefix(kv[0], "rack_"))
+			if err != nil {
+				Logger.Warn("Node %s: invalid rack id in racks info: %s", nd.String(), kv[0])
+				continue
+			}
+
+			for _, name := range strings.Split(kv[1], ",") {
+				if name == nd.name {
+					racks[namespace] = rackId
+				}
+			}
+		}
+	}
+
+	nd.mutex.Lock()
+	nd.racks = racks
+	nd.mutex.Unlock()
+}
+
+// hasRack returns true if the node is on the rack for the namespace.
+func (nd *Node) hasRack(namespace string, rackId int) bool {
+	nd.mutex.RLock()
+	id, exists := nd.racks[namespace]
+	nd.mutex.RUnlock()
+
+	return exists && id == rackId
+}
+
+// Features returns the features advertised by the node in the response
+// of the "features" info command, as of the last cluster tend.
+func (nd *Node) Features() []string {
+	nd.mutex.RLock()
+	defer nd.mutex.RUnlock()
+
+	return append([]string{}, nd.features...)
+}
+
+// SupportsFeature returns true if the node advertises the feature,
+// e.g. "pscans", "batch-any" or "compression".
+func (nd *Node) SupportsFeature(feature string) bool {
+	nd.mutex.RLock()
+	defer nd.mutex.RUnlock()
+
+	for _, f := range nd.features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
 func (nd *Node) verifyNodeName(infoMap map[string]string) error {
 	infoName, exists := infoMap["node"]
 
@@ -122,21 +281,46 @@ func (nd *Node) verifyNodeName(infoMap map[string]string) error {
 	return nil
 }
 
+// friendsInfoName returns the info command that lists the node's peers.
+// With TLS, the peers-tls commands are used as they also carry the TLS name of the peers.
+func (nd *Node) friendsInfoName() string {
+	if nd.cluster.tlsConfig != nil {
+		if nd.cluster.useServicesAlternate {
+			return "peers-tls-alt"
+		}
+		return "peers-tls-std"
+	}
+
+	if nd.cluster.useServicesAlternate {
+		return "services-alternate"
+	}
+	return "services"
+}
+
 func (nd *Node) addFriends(infoMap map[string]string) ([]*Host, error) {
-	friendString, exists := infoMap["services"]
+	friendString, exists := infoMap[nd.friendsInfoName()]
 	var friends []*Host
 
 	if !exists || len(friendString) == 0 {
 		return friends, nil
 	}
 
-	friendNames := strings.Split(friendString, ";")
+	var aliases []*Host
+	if nd.cluster.tlsConfig != nil {
+		var err error
+		if aliases, err = parsePeers(friendString); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, friend := range strings.Split(friendString, ";") {
+			friendInfo := strings.Split(friend, ":")
+			host := friendInfo[0]
+			port, _ := strconv.Atoi(friendInfo[1])
+			aliases = append(aliases, NewHost(host, port))
+		}
+	}
 
-	for _, friend := range friendNames {
-		friendInfo := strings.Split(friend, ":")
-		host := friendInfo[0]
-		port, _ := strconv.Atoi(friendInfo[1])
-		alias := NewHost(host, port)
+	for _, alias := range aliases {
 		node := nd.cluster.findAlias(alias)
 
 		if node != nil {
@@ -197,24 +381,215 @@ func (nd *Node) GetConnection(timeout time.Duration) (conn *Connection, err erro
 		conn.Close()
 	}
 
-	if conn, err = NewConnection(nd.address, nd.cluster.connectionTimeout); err != nil {
+	if conn, err = nd.cluster.newConnection(nd.address, nd.cluster.connectionTimeout, nd.tlsName); err != nil {
 		return nil, err
 	}
-	if conn.SetTimeout(timeout) != nil {
+	conn.node = nd
+	nd.stats.connectionsOpen.IncrementAndGet()
+
+	if err = nd.authenticate(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if err = conn.SetTimeout(timeout); err != nil {
+		conn.Close()
 		return nil, err
 	}
 	return conn, nil
 }
 
+// authenticate authenticates a new connection with the session token of the node,
+// or logs in again if the node has no valid session.
+func (nd *Node) authenticate(conn *Connection) error {
+	user, _ := nd.cluster.credentials()
+	if user == "" {
+		return nil
+	}
+
+	nd.sessionMutex.RLock()
+	token := nd.sessionToken
+	nd.sessionMutex.RUnlock()
+
+	if token == nil || nd.sessionExpired() {
+		return nd.login(conn)
+	}
+
+	err := newAdminCommand().authenticateSession(conn, user, token)
+	if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == EXPIRED_SESSION {
+		return nd.login(conn)
+	}
+	return err
+}
+
+// login logs in on the connection, and keeps the session token for the new connections.
+func (nd *Node) login(conn *Connection) error {
+	token, expiration, err := nd.cluster.login(conn)
+	if err != nil {
+		return err
+	}
+
+	nd.sessionMutex.Lock()
+	nd.sessionToken = token
+	nd.sessionExpiration = expiration
+	nd.sessionMutex.Unlock()
+	return nil
+}
+
+// sessionExpired returns true if the session token of the node must be renewed.
+func (nd *Node) sessionExpired() bool {
+	nd.sessionMutex.RLock()
+	defer nd.sessionMutex.RUnlock()
+	return nd.sessionToken != nil && !nd.sessionExpiration.IsZero() && !time.Now().Before(nd.sessionExpiration)
+}
+
 // PutConnection puts back a connection to the pool.
 // If connection pool is full, the connection will be
 // closed and discarded.
 func (nd *Node) PutConnection(conn *Connection) {
+	conn.lastUsed = time.Now()
+	if !nd.active.Get() || !nd.connections.Offer(conn) {
+		conn.Close()
+	}
+}
+
+// pingIdleConnections periodically sends a status info command on pooled
+// connections that have been idle for longer than interval, and closes
+// those that fail to respond. It returns when the node is closed.
+func (nd *Node) pingIdleConnections(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !nd.active.Get() {
+			return
+		}
+		nd.pingConnections(interval)
+	}
+}
+
+// pingConnections pings the pooled connections that have been idle for longer
+// than interval. Connections are taken out of the pool one at a time, and are
+// put back without changing the time they were last used, so that pings
+// do not keep them from being closed as idle.
+func (nd *Node) pingConnections(interval time.Duration) {
+	for n := nd.connections.Len(); n > 0; n-- {
+		t := nd.connections.Poll()
+		if t == nil {
+			return
+		}
+		conn := t.(*Connection)
+
+		if time.Since(conn.lastUsed) < interval {
+			// connections are put back at the end of the pool, so
+			// the ones behind this one have been used more recently
+			nd.requeueConnection(conn)
+			return
+		}
+
+		if err := conn.SetTimeout(nd.cluster.connectionTimeout); err != nil {
+			conn.Close()
+			continue
+		}
+
+		if _, err := RequestInfo(conn, "status"); err != nil {
+			Logger.Debug("Node %s: idle connection failed to respond: %s", nd.String(), err.Error())
+			conn.Close()
+			continue
+		}
+		nd.requeueConnection(conn)
+	}
+}
+
+// requeueConnection puts a connection back into the pool like PutConnection,
+// but keeps the time it was last used.
+func (nd *Node) requeueConnection(conn *Connection) {
 	if !nd.active.Get() || !nd.connections.Offer(conn) {
 		conn.Close()
 	}
 }
 
+// closeIdleConnections closes pooled connections which have been idle for
+// longer than maxIdle, as long as more than minConnections are open.
+func (nd *Node) closeIdleConnections(maxIdle time.Duration, minConnections int) {
+	// take all connections out of the pool first, so that
+	// the ones put back are not polled again
+	conns := []*Connection{}
+	for t := nd.connections.Poll(); t != nil; t = nd.connections.Poll() {
+		conns = append(conns, t.(*Connection))
+	}
+
+	closed := 0
+	for _, conn := range conns {
+		if time.Since(conn.lastUsed) > maxIdle && nd.stats.connectionsOpen.Get() > minConnections {
+			conn.Close()
+			closed++
+			continue
+		}
+
+		// PutConnection would reset the idle time
+		if !nd.connections.Offer(conn) {
+			conn.Close()
+		}
+	}
+
+	if closed > 0 {
+		Logger.Debug("Node %s: closed %d idle connections", nd.String(), closed)
+	}
+}
+
+// Stats returns a snapshot of the connection pool and command counters of the node.
+func (nd *Node) Stats() NodeStats {
+	res := NodeStats{
+		Name:              nd.name,
+		Address:           nd.address,
+		ConnectionsOpen:   nd.stats.connectionsOpen.Get(),
+		ConnectionsPooled: nd.connections.Len(),
+		Commands:          nd.stats.commands.Get(),
+		CommandsInFlight:  nd.stats.commandsInFlight.Get(),
+		Retries:           nd.stats.retries.Get(),
+		Timeouts:          nd.stats.timeouts.Get(),
+	}
+
+	// counters are read independently, so the pool may momentarily
+	// seem larger than the number of open connections
+	if res.ConnectionsInUse = res.ConnectionsOpen - res.ConnectionsPooled; res.ConnectionsInUse < 0 {
+		res.ConnectionsInUse = 0
+	}
+
+	if count := nd.stats.latencyCount.Get(); count > 0 {
+		res.AverageLatency = time.Duration(nd.stats.latencySum.Get() / int64(count))
+	}
+
+	res.P99Latency = nd.stats.latencies.percentile99()
+	res.AdaptiveTimeout = time.Duration(nd.stats.adaptiveTimeout.Get())
+
+	return res
+}
+
+// adaptiveTimeout returns the socket timeout for a command on the node, computed
+// from the 99th percentile of the node's recent latency as determined by the policy.
+// It returns zero if adaptive timeouts are disabled, or the node has not completed
+// enough commands yet.
+func (nd *Node) adaptiveTimeout(policy *BasePolicy) time.Duration {
+	if policy.AdaptiveTimeoutMultiplier <= 0 {
+		return 0
+	}
+
+	p99 := nd.stats.latencies.percentile99()
+	if p99 == 0 {
+		return 0
+	}
+
+	timeout := time.Duration(float64(p99) * policy.AdaptiveTimeoutMultiplier)
+	if timeout < policy.MinAdaptiveTimeout {
+		timeout = policy.MinAdaptiveTimeout
+	}
+
+	nd.stats.adaptiveTimeout.Set(int64(timeout))
+	return timeout
+}
+
 // RestoreHealth marks the node as healthy.
 func (nd *Node) RestoreHealth() {
 	// There can be cases where health is full, but active is false.
@@ -298,6 +673,27 @@ func (nd *Node) Equals(other *Node) bool {
 	return nd.name == other.name
 }
 
+// RequestInfo sends the info commands to the node,
+// and returns the responses keyed by command.
+func (nd *Node) RequestInfo(commands ...string) (map[string]string, error) {
+	return nd.requestInfo(_DEFAULT_TIMEOUT, commands...)
+}
+
+func (nd *Node) requestInfo(timeout time.Duration, commands ...string) (map[string]string, error) {
+	conn, err := nd.GetConnection(timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := RequestInfo(conn, commands...)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	nd.PutConnection(conn)
+	return response, nil
+}
+
 // MigrationInProgress determines if the node is participating in a data migration
 func (nd *Node) MigrationInProgress() (bool, error) {
 	values, err := RequestNodeStats(nd)
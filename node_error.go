@@ -41,3 +41,6 @@ func newAerospikeNodeError(node *Node, code ResultCode, messages ...string) *Nod
 
 // Node returns the node where the error occured.
 func (ne *NodeError) Node() *Node { return ne.node }
+
+// Unwrap returns the error which occured on the node.
+func (ne *NodeError) Unwrap() error { return ne.error }
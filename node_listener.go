@@ -0,0 +1,108 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// NodeEvent describes a change of the nodes of the cluster during a tend.
+type NodeEvent struct {
+	// Added holds the nodes which joined the cluster, and Removed
+	// the nodes which left it.
+	Added, Removed []*Node
+
+	// Before and After hold all the nodes of the cluster
+	// before and after the change.
+	Before, After []*Node
+}
+
+// NodeListener is notified by the cluster tend goroutine when nodes join or
+// leave the cluster, with the changes since the previous notification.
+type NodeListener func(event *NodeEvent)
+
+// SetNodeListener sets the listener to notify of node changes.
+// Pass nil to remove the listener.
+func (clstr *Cluster) SetNodeListener(listener NodeListener) {
+	clstr.listenerMutex.Lock()
+	clstr.nodeListener = listener
+	clstr.listenerMutex.Unlock()
+}
+
+// notifyNodeChanges compares the nodes of the cluster before and after
+// a tend, and queues the changes for the listener.
+func (clstr *Cluster) notifyNodeChanges(before, after []*Node) {
+	clstr.listenerMutex.Lock()
+	defer clstr.listenerMutex.Unlock()
+
+	if clstr.nodeListener == nil {
+		return
+	}
+
+	added := nodesDiff(after, before)
+	removed := nodesDiff(before, after)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	clstr.nodeEvents = append(clstr.nodeEvents, &NodeEvent{
+		Added:   added,
+		Removed: removed,
+		Before:  before,
+		After:   after,
+	})
+
+	// a single goroutine delivers the events in order,
+	// so that a slow listener does not stall the tend
+	if !clstr.notifying {
+		clstr.notifying = true
+		go clstr.deliverNodeEvents()
+	}
+}
+
+func (clstr *Cluster) deliverNodeEvents() {
+	for {
+		clstr.listenerMutex.Lock()
+		if len(clstr.nodeEvents) == 0 {
+			clstr.notifying = false
+			clstr.listenerMutex.Unlock()
+			return
+		}
+		event := clstr.nodeEvents[0]
+		clstr.nodeEvents = clstr.nodeEvents[1:]
+		listener := clstr.nodeListener
+		clstr.listenerMutex.Unlock()
+
+		if listener != nil {
+			listener(event)
+		}
+	}
+}
+
+// nodesDiff returns the nodes in a which are not in b.
+func nodesDiff(a, b []*Node) []*Node {
+	var res []*Node
+	for _, node := range a {
+		found := false
+		for _, other := range b {
+			// Note: using pointer equality for performance.
+			if node == other {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			res = append(res, node)
+		}
+	}
+	return res
+}
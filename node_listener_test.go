@@ -0,0 +1,64 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NodeListener Test", func() {
+
+	nodeA, nodeB, nodeC := &Node{name: "A"}, &Node{name: "B"}, &Node{name: "C"}
+
+	It("must return the nodes which are only in the first list", func() {
+		Expect(nodesDiff([]*Node{nodeA, nodeB, nodeC}, []*Node{nodeB})).To(Equal([]*Node{nodeA, nodeC}))
+		Expect(nodesDiff([]*Node{nodeA}, []*Node{nodeA, nodeB})).To(BeEmpty())
+		Expect(nodesDiff(nil, []*Node{nodeA})).To(BeEmpty())
+		Expect(nodesDiff([]*Node{nodeA}, nil)).To(Equal([]*Node{nodeA}))
+	})
+
+	It("must notify the listener of the nodes which join and leave the cluster, in order", func() {
+		cluster := &Cluster{}
+		events := make(chan *NodeEvent, 4)
+		cluster.SetNodeListener(func(event *NodeEvent) {
+			events <- event
+		})
+
+		// no event is sent while the nodes do not change
+		cluster.notifyNodeChanges([]*Node{nodeA}, []*Node{nodeA})
+
+		cluster.notifyNodeChanges([]*Node{nodeA}, []*Node{nodeA, nodeB})
+		cluster.notifyNodeChanges([]*Node{nodeA, nodeB}, []*Node{nodeB, nodeC})
+
+		var event *NodeEvent
+		Eventually(events).Should(Receive(&event))
+		Expect(event.Added).To(Equal([]*Node{nodeB}))
+		Expect(event.Removed).To(BeEmpty())
+		Expect(event.Before).To(Equal([]*Node{nodeA}))
+		Expect(event.After).To(Equal([]*Node{nodeA, nodeB}))
+
+		Eventually(events).Should(Receive(&event))
+		Expect(event.Added).To(Equal([]*Node{nodeC}))
+		Expect(event.Removed).To(Equal([]*Node{nodeA}))
+		Expect(event.Before).To(Equal([]*Node{nodeA, nodeB}))
+		Expect(event.After).To(Equal([]*Node{nodeB, nodeC}))
+
+		Consistently(events, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+})
@@ -0,0 +1,210 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+)
+
+const (
+	// number of recent command latencies kept per node to estimate its latency percentiles
+	_LATENCY_WINDOW_SIZE = 256
+	// number of samples needed before the percentiles are estimated,
+	// and after which they are estimated again
+	_LATENCY_MIN_SAMPLES = 32
+)
+
+// nodeStats holds the counters a node maintains about its connections
+// and the commands sent to it. All counters are updated atomically.
+type nodeStats struct {
+	connectionsOpen  *AtomicInt
+	commands         *AtomicInt
+	commandsInFlight *AtomicInt
+	retries          *AtomicInt
+	timeouts         *AtomicInt
+
+	// sum of the latencies of successful commands, in nanoseconds
+	latencySum   *AtomicInt64
+	latencyCount *AtomicInt
+
+	// recent latencies of single record and batch commands
+	latencies *latencyWindow
+	// last socket timeout computed from the latencies, in nanoseconds
+	adaptiveTimeout *AtomicInt64
+}
+
+func newNodeStats() *nodeStats {
+	return &nodeStats{
+		connectionsOpen:  NewAtomicInt(0),
+		commands:         NewAtomicInt(0),
+		commandsInFlight: NewAtomicInt(0),
+		retries:          NewAtomicInt(0),
+		timeouts:         NewAtomicInt(0),
+		latencySum:       NewAtomicInt64(0),
+		latencyCount:     NewAtomicInt(0),
+		latencies:        &latencyWindow{},
+		adaptiveTimeout:  NewAtomicInt64(0),
+	}
+}
+
+// recordLatency adds the latency of a successful command to the node counters.
+func (ns *nodeStats) recordLatency(latency time.Duration) {
+	ns.latencySum.AddAndGet(int64(latency))
+	ns.latencyCount.IncrementAndGet()
+}
+
+// latencyWindow keeps the latencies of the most recent commands on a node,
+// and estimates their 99th percentile.
+type latencyWindow struct {
+	mutex   sync.Mutex
+	samples [_LATENCY_WINDOW_SIZE]time.Duration
+	count   int
+
+	// estimated percentile, and the sample count when it was estimated
+	p99      time.Duration
+	p99Count int
+}
+
+func (lw *latencyWindow) add(latency time.Duration) {
+	lw.mutex.Lock()
+	lw.samples[lw.count%_LATENCY_WINDOW_SIZE] = latency
+	lw.count++
+	lw.mutex.Unlock()
+}
+
+// percentile99 returns the 99th percentile of the recent latencies,
+// or zero if there are not enough samples yet. The percentile is
+// estimated again once _LATENCY_MIN_SAMPLES new samples have been added.
+func (lw *latencyWindow) percentile99() time.Duration {
+	lw.mutex.Lock()
+	defer lw.mutex.Unlock()
+
+	if lw.count < _LATENCY_MIN_SAMPLES {
+		return 0
+	}
+
+	if lw.p99Count == 0 || lw.count-lw.p99Count >= _LATENCY_MIN_SAMPLES {
+		n := lw.count
+		if n > _LATENCY_WINDOW_SIZE {
+			n = _LATENCY_WINDOW_SIZE
+		}
+
+		sorted := make(durations, n)
+		copy(sorted, lw.samples[:n])
+		sort.Sort(sorted)
+
+		lw.p99 = sorted[(n*99+99)/100-1]
+		lw.p99Count = lw.count
+	}
+	return lw.p99
+}
+
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
+// NodeStats is a snapshot of the connection pool and command
+// counters of a server node.
+type NodeStats struct {
+	// Name of the node.
+	Name string
+	// Address of the node.
+	Address string
+
+	// ConnectionsOpen is the number of connections to the node which are currently open.
+	ConnectionsOpen int
+	// ConnectionsPooled is the number of idle connections in the node's connection pool.
+	ConnectionsPooled int
+	// ConnectionsInUse is the number of open connections which are not in the pool.
+	ConnectionsInUse int
+
+	// Commands is the total number of command attempts sent to the node.
+	Commands int
+	// CommandsInFlight is the number of commands currently executing on the node.
+	CommandsInFlight int
+	// Retries is the number of command attempts which were retries of a failed attempt.
+	Retries int
+	// Timeouts is the number of commands which timed out on the node.
+	Timeouts int
+	// AverageLatency is the average latency of the successful commands on the node.
+	AverageLatency time.Duration
+	// P99Latency is the 99th percentile of the latency of the recent single record
+	// and batch commands on the node. It is zero until enough commands have completed.
+	P99Latency time.Duration
+	// AdaptiveTimeout is the last socket timeout computed from the latency of the node,
+	// for policies with AdaptiveTimeoutMultiplier set. It is zero if none has been computed.
+	AdaptiveTimeout time.Duration
+}
+
+// ClientStats is a snapshot of the connection pool and command
+// counters of all the nodes in the cluster.
+type ClientStats struct {
+	// Nodes holds the stats of each active node in the cluster.
+	Nodes []NodeStats
+
+	// ConnectionsOpen is the number of open connections to all nodes.
+	ConnectionsOpen int
+	// ConnectionsPooled is the number of idle connections in all node pools.
+	ConnectionsPooled int
+	// ConnectionsInUse is the number of open connections which are not in the pools.
+	ConnectionsInUse int
+
+	// Commands is the total number of command attempts sent to all nodes.
+	Commands int
+	// CommandsInFlight is the number of commands currently executing on all nodes.
+	CommandsInFlight int
+	// Retries is the total number of command retries on all nodes.
+	Retries int
+	// Timeouts is the total number of commands which timed out on all nodes.
+	Timeouts int
+	// AverageLatency is the average latency of the successful commands on all nodes.
+	AverageLatency time.Duration
+}
+
+func newClientStats(nodes []*Node) *ClientStats {
+	res := &ClientStats{
+		Nodes: make([]NodeStats, 0, len(nodes)),
+	}
+
+	var latencySum int64
+	var latencyCount int
+	for _, node := range nodes {
+		ns := node.Stats()
+		res.Nodes = append(res.Nodes, ns)
+
+		res.ConnectionsOpen += ns.ConnectionsOpen
+		res.ConnectionsPooled += ns.ConnectionsPooled
+		res.ConnectionsInUse += ns.ConnectionsInUse
+		res.Commands += ns.Commands
+		res.CommandsInFlight += ns.CommandsInFlight
+		res.Retries += ns.Retries
+		res.Timeouts += ns.Timeouts
+
+		latencySum += node.stats.latencySum.Get()
+		latencyCount += node.stats.latencyCount.Get()
+	}
+
+	if latencyCount > 0 {
+		res.AverageLatency = time.Duration(latencySum / int64(latencyCount))
+	}
+
+	return res
+}
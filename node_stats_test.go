@@ -0,0 +1,100 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node Stats Test", func() {
+
+	Context("Latency window", func() {
+
+		It("must not estimate the percentile without enough samples", func() {
+			lw := &latencyWindow{}
+			for i := 1; i < _LATENCY_MIN_SAMPLES; i++ {
+				lw.add(time.Duration(i) * time.Millisecond)
+			}
+			Expect(lw.percentile99()).To(Equal(time.Duration(0)))
+
+			lw.add(time.Millisecond)
+			Expect(lw.percentile99()).To(Equal(31 * time.Millisecond))
+		})
+
+		It("must return the 99th percentile of the most recent samples", func() {
+			lw := &latencyWindow{}
+			for i := 1; i <= 100; i++ {
+				lw.add(time.Duration(i) * time.Millisecond)
+			}
+			Expect(lw.percentile99()).To(Equal(99 * time.Millisecond))
+
+			// the old samples are replaced once the window is full
+			for i := 0; i < _LATENCY_WINDOW_SIZE; i++ {
+				lw.add(time.Millisecond)
+			}
+			Expect(lw.percentile99()).To(Equal(time.Millisecond))
+		})
+
+		It("must estimate the percentile again only after enough new samples", func() {
+			lw := &latencyWindow{}
+			for i := 0; i < _LATENCY_MIN_SAMPLES; i++ {
+				lw.add(time.Millisecond)
+			}
+			Expect(lw.percentile99()).To(Equal(time.Millisecond))
+
+			for i := 0; i < _LATENCY_MIN_SAMPLES-1; i++ {
+				lw.add(time.Second)
+			}
+			Expect(lw.percentile99()).To(Equal(time.Millisecond))
+
+			lw.add(time.Second)
+			Expect(lw.percentile99()).To(Equal(time.Second))
+		})
+
+	})
+
+	Context("Adaptive timeouts", func() {
+
+		It("must widen the timeout when the commands on a slow node time out", func() {
+			policy := NewPolicy()
+			policy.MaxRetries = 0
+			policy.AdaptiveTimeoutMultiplier = 2
+			policy.MinAdaptiveTimeout = time.Millisecond
+
+			node := newScriptedNode()
+			for i := 0; i < _LATENCY_WINDOW_SIZE; i++ {
+				node.stats.latencies.add(time.Millisecond)
+			}
+			Expect(node.adaptiveTimeout(policy)).To(Equal(2 * time.Millisecond))
+
+			timeoutErr := NewAerospikeError(TIMEOUT)
+			for _, expected := range []time.Duration{4 * time.Millisecond, 8 * time.Millisecond} {
+				for i := 0; i < _LATENCY_MIN_SAMPLES; i++ {
+					cmd := &scriptedCommand{policy: policy, node: node, errs: []error{timeoutErr}}
+					Expect(cmd.Execute()).To(Equal(timeoutErr))
+				}
+				Expect(node.adaptiveTimeout(policy)).To(Equal(expected))
+			}
+			Expect(node.stats.timeouts.Get()).To(Equal(2 * _LATENCY_MIN_SAMPLES))
+		})
+
+	})
+
+})
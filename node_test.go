@@ -0,0 +1,197 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// serveStatus answers the status info commands sent on conn until it is closed,
+// and reports each of them on requests.
+func serveStatus(conn net.Conn, requests chan<- struct{}) {
+	defer conn.Close()
+
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		body := make([]byte, binary.BigEndian.Uint64(header)&0xFFFFFFFFFFFF)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+		requests <- struct{}{}
+
+		response := []byte("status\tok\n")
+		binary.BigEndian.PutUint64(header, uint64(len(response)))
+		header[0], header[1] = 2, 1
+		if _, err := conn.Write(append(header, response...)); err != nil {
+			return
+		}
+	}
+}
+
+var _ = Describe("Node Test", func() {
+
+	Context("Idle connections", func() {
+
+		It("must ping only idle connections, and keep the time they were last used", func() {
+			node := &Node{
+				cluster:     &Cluster{connectionTimeout: time.Second},
+				connections: NewAtomicQueue(4),
+				active:      NewAtomicBool(true),
+			}
+
+			requests := make(chan struct{}, 4)
+			idleSince := time.Now().Add(-time.Minute)
+			client, server := net.Pipe()
+			go serveStatus(server, requests)
+			idle := &Connection{conn: client, lastUsed: idleSince}
+
+			// nothing answers on the other end, so pinging it would fail
+			busySince := time.Now()
+			client, server = net.Pipe()
+			defer server.Close()
+			busy := &Connection{conn: client, lastUsed: busySince}
+
+			Expect(node.connections.Offer(idle)).To(BeTrue())
+			Expect(node.connections.Offer(busy)).To(BeTrue())
+
+			node.pingConnections(time.Second)
+
+			Expect(requests).To(HaveLen(1))
+			Expect(node.connections.Len()).To(Equal(2))
+			Expect(node.connections.Poll()).To(BeIdenticalTo(idle))
+			Expect(node.connections.Poll()).To(BeIdenticalTo(busy))
+			Expect(idle.conn).ToNot(BeNil())
+			Expect(idle.lastUsed).To(Equal(idleSince))
+			Expect(busy.conn).ToNot(BeNil())
+			Expect(busy.lastUsed).To(Equal(busySince))
+			idle.Close()
+			busy.Close()
+		})
+
+	})
+
+	Context("Racks", func() {
+
+		newRackNode := func(name string) *Node {
+			return &Node{
+				name:   name,
+				host:   NewHost("127.0.0.1", 3000),
+				active: NewAtomicBool(true),
+			}
+		}
+
+		It("must cache the racks of the node per namespace", func() {
+			node := newRackNode("BB9000000000001")
+			node.updateRacks(map[string]string{
+				"racks:": "ns=test:rack_1=BB9000000000002:rack_2=BB9000000000001,BB9000000000003;" +
+					"ns=bar:rack_10=BB9000000000001;ns=baz:rack_3=BB9000000000002",
+			})
+
+			Expect(node.racks).To(Equal(map[string]int{"test": 2, "bar": 10}))
+			Expect(node.hasRack("test", 2)).To(BeTrue())
+			Expect(node.hasRack("test", 1)).To(BeFalse())
+			Expect(node.hasRack("bar", 10)).To(BeTrue())
+			// the node is not on any rack of the namespace
+			Expect(node.hasRack("baz", 3)).To(BeFalse())
+			Expect(node.hasRack("baz", 0)).To(BeFalse())
+			Expect(node.hasRack("unknown", 0)).To(BeFalse())
+		})
+
+		It("must skip malformed entries of the racks info", func() {
+			node := newRackNode("BB9000000000001")
+			node.updateRacks(map[string]string{
+				"racks:": ";test:rack_1=BB9000000000001;ns=a;ns=b:rack_x=BB9000000000001:rack_1;" +
+					"ns=c:node_1=BB9000000000001;ns=d:rack_=BB9000000000001:rack_4=BB9000000000001;" +
+					"ns=e:rack_5=BB90000000000011",
+			})
+
+			Expect(node.racks).To(Equal(map[string]int{"d": 4}))
+		})
+
+		It("must replace the racks of the node, and keep them if the info is not returned", func() {
+			node := newRackNode("BB9000000000001")
+			node.updateRacks(map[string]string{"racks:": "ns=test:rack_1=BB9000000000001"})
+			Expect(node.hasRack("test", 1)).To(BeTrue())
+
+			node.updateRacks(map[string]string{"racks:": "ns=test:rack_2=BB9000000000001"})
+			Expect(node.hasRack("test", 1)).To(BeFalse())
+			Expect(node.hasRack("test", 2)).To(BeTrue())
+
+			node.updateRacks(map[string]string{})
+			Expect(node.hasRack("test", 2)).To(BeTrue())
+
+			node.updateRacks(map[string]string{"racks:": ""})
+			Expect(node.racks).To(BeEmpty())
+		})
+
+		It("must read from the replica on the rack, and from any replica otherwise", func() {
+			master := newRackNode("BB9000000000001")
+			master.racks = map[string]int{"test": 1}
+			prole := newRackNode("BB9000000000002")
+			prole.racks = map[string]int{"test": 2}
+
+			cluster := &Cluster{
+				rackAware:         true,
+				partitionWriteMap: map[string][]*Node{"test": {master}},
+				partitionProleMap: map[string][]*Node{"test": {prole}},
+				replicaIndex:      NewAtomicInt(0),
+			}
+			partition := NewPartition("test", 0)
+
+			for _, rack := range []struct {
+				id   int
+				node *Node
+			}{{1, master}, {2, prole}} {
+				cluster.rackId = rack.id
+				for i := 0; i < 2; i++ {
+					node, err := cluster.getRackNode(partition)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(node).To(BeIdenticalTo(rack.node))
+				}
+			}
+
+			// neither node is on the rack
+			cluster.rackId = 3
+			nodes := map[*Node]int{}
+			for i := 0; i < 4; i++ {
+				node, err := cluster.getRackNode(partition)
+				Expect(err).ToNot(HaveOccurred())
+				nodes[node]++
+			}
+			Expect(nodes).To(Equal(map[*Node]int{master: 2, prole: 2}))
+
+			// the node on the rack is not active
+			cluster.rackId = 2
+			prole.active.Set(false)
+			for i := 0; i < 2; i++ {
+				node, err := cluster.getRackNode(partition)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(node).To(BeIdenticalTo(master))
+			}
+		})
+	})
+
+})
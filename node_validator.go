@@ -18,6 +18,7 @@ import (
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go/logger"
@@ -30,12 +31,37 @@ type nodeValidator struct {
 	aliases    []*Host
 	address    string
 	useNewInfo bool //= true
+
+	supportsPredExp    bool //= false
+	supportsTruncate   bool //= false
+	supportsBatchIndex bool //= false
+
+	supportsCompression bool //= false
+
+	supportsPartitionScan bool //= false
+	supportsBatchAny      bool //= false
+	supportsPQuery        bool //= false
+
+	supportsScanRPS  bool //= false
+	supportsQueryRPS bool //= false
+
+	// features advertised by the node
+	features []string
+
+	cluster *Cluster
+	tlsName string
+
+	// session obtained by the login, which the node reuses for its connections
+	sessionToken      []byte
+	sessionExpiration time.Time
 }
 
 // Generates a node validator
-func newNodeValidator(host *Host, timeout time.Duration) (*nodeValidator, error) {
+func newNodeValidator(cluster *Cluster, host *Host, timeout time.Duration) (*nodeValidator, error) {
 	newNodeValidator := &nodeValidator{
 		useNewInfo: true,
+		cluster:    cluster,
+		tlsName:    host.TLSName,
 	}
 
 	if err := newNodeValidator.setAliases(host); err != nil {
@@ -57,6 +83,7 @@ func (ndv *nodeValidator) setAliases(host *Host) error {
 	aliases := make([]*Host, len(addresses))
 	for idx, addr := range addresses {
 		aliases[idx] = NewHost(addr, host.Port)
+		aliases[idx].TLSName = host.TLSName
 	}
 	ndv.aliases = aliases
 	Logger.Debug("Node Validator has %d nodes.", len(aliases))
@@ -66,7 +93,7 @@ func (ndv *nodeValidator) setAliases(host *Host) error {
 func (ndv *nodeValidator) setAddress(timeout time.Duration) error {
 	for _, alias := range ndv.aliases {
 		address := net.JoinHostPort(alias.Name, strconv.Itoa(alias.Port))
-		conn, err := NewConnection(address, time.Second)
+		conn, err := ndv.cluster.newConnection(address, time.Second, ndv.tlsName)
 		if err != nil {
 			return err
 		}
@@ -77,7 +104,11 @@ func (ndv *nodeValidator) setAddress(timeout time.Duration) error {
 			return err
 		}
 
-		infoMap, err := RequestInfo(conn, "node", "build")
+		if ndv.sessionToken, ndv.sessionExpiration, err = ndv.cluster.login(conn); err != nil {
+			return err
+		}
+
+		infoMap, err := RequestInfo(conn, "node", "build", "features")
 		if err != nil {
 			return err
 		}
@@ -93,12 +124,53 @@ func (ndv *nodeValidator) setAddress(timeout time.Duration) error {
 					return err
 				}
 				ndv.useNewInfo = v1 > 2 || (v1 == 2 && (v2 > 6 || (v2 == 6 && v3 >= 6)))
+
+				// Check predicate expression support for >= 3.12 build
+				ndv.supportsPredExp = v1 > 3 || (v1 == 3 && v2 >= 12)
+
+				// Check truncate support for >= 3.12 build
+				ndv.supportsTruncate = v1 > 3 || (v1 == 3 && v2 >= 12)
+
+				// Check batch index protocol support for >= 3.6 build
+				ndv.supportsBatchIndex = v1 > 3 || (v1 == 3 && v2 >= 6)
+
+				// Check records per second support for scans >= 4.7, and queries >= 6.0 builds
+				ndv.supportsScanRPS = v1 > 4 || (v1 == 4 && v2 >= 7)
+				ndv.supportsQueryRPS = v1 >= 6
+			}
+
+			// Check compression, partition scan, partition query and batch write support advertised in the features list
+			if features, exists := infoMap["features"]; exists {
+				ndv.features = parseFeatures(features)
+				for _, feature := range ndv.features {
+					switch feature {
+					case "compression":
+						ndv.supportsCompression = true
+					case "pscans":
+						ndv.supportsPartitionScan = true
+					case "pquery":
+						ndv.supportsPQuery = true
+					case "batch-any":
+						ndv.supportsBatchAny = true
+					}
+				}
 			}
 		}
 	}
 	return nil
 }
 
+// parseFeatures splits the response of the "features" info command.
+func parseFeatures(response string) []string {
+	features := []string{}
+	for _, feature := range strings.Split(response, ";") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			features = append(features, feature)
+		}
+	}
+	return features
+}
+
 // parses a version string
 var r = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+).*`)
 
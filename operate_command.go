@@ -25,17 +25,63 @@ func newOperateCommand(cluster *Cluster, policy *WritePolicy, key *Key, operatio
 	if policy == nil {
 		policy = NewWritePolicy(0, 0)
 	}
+	readCmd := newReadCommand(cluster, policy, key, nil)
+	readCmd.opResults = policy.RespondPerEachOp
+
+	for _, op := range operations {
+		if opValue, ok := op.BinValue.(*cdtOpValue); ok && opValue.keyValuePairs {
+			if readCmd.keyValuePairBins == nil {
+				readCmd.keyValuePairBins = map[string]bool{}
+			}
+			readCmd.keyValuePairBins[*op.BinName] = true
+		}
+
+		if opValue, ok := op.BinValue.(*cdtOpValue); ok && opValue.int64Result {
+			if readCmd.int64Bins == nil {
+				readCmd.int64Bins = map[string]bool{}
+			}
+			readCmd.int64Bins[*op.BinName] = true
+		}
+	}
+
 	return &operateCommand{
-		readCommand: newReadCommand(cluster, policy, key, nil),
+		readCommand: readCmd,
 		policy:      policy,
 		operations:  operations,
 	}
 }
 
+// operations may write to the record, so they are always sent to the master node.
+func (cmd *operateCommand) getNode(ifc command) (*Node, error) {
+	return cmd.cluster.GetNode(cmd.partition)
+}
+
 func (cmd *operateCommand) writeBuffer(ifc command) error {
 	return cmd.setOperate(cmd.policy, cmd.key, cmd.operations)
 }
 
+// operateResults assigns the results received from the server to the operations
+// they belong to. The server only returns results for operations on a bin,
+// in the order of the operations.
+func (cmd *operateCommand) operateResults() OperateResults {
+	results := make(OperateResults, len(cmd.operations))
+	values := cmd.opValues
+	for i, op := range cmd.operations {
+		if op.BinName == nil || len(values) == 0 {
+			continue
+		}
+		results[i] = values[0]
+		values = values[1:]
+	}
+	return results
+}
+
 func (cmd *operateCommand) Execute() error {
+	if err := cmd.cluster.validateExpiration(cmd.policy.Expiration); err != nil {
+		return err
+	}
+	if err := cmd.cluster.validateOperations(cmd.operations); err != nil {
+		return err
+	}
 	return cmd.execute(cmd)
 }
@@ -21,10 +21,16 @@ var (
 	READ        OperationType = 1
 	READ_HEADER OperationType = 1
 	WRITE       OperationType = 2
+	CDT_READ    OperationType = 3
+	CDT_MODIFY  OperationType = 4
 	ADD         OperationType = 5
 	APPEND      OperationType = 9
 	PREPEND     OperationType = 10
 	TOUCH       OperationType = 11
+	BIT_READ    OperationType = 12
+	BIT_MODIFY  OperationType = 13
+	HLL_READ    OperationType = 15
+	HLL_MODIFY  OperationType = 16
 )
 
 // Operation contasins operation definition.
@@ -41,12 +47,24 @@ type Operation struct {
 	BinValue Value
 }
 
+// OpResults holds the results of multiple operations on the same bin
+// in an Operate command, in the order of the operations.
+type OpResults []interface{}
+
+// OperateResults holds the result of each operation of an OperateWithResults
+// command, at the index of the operation. Writes which return no value, like PutOp,
+// and operations which are not applied to a bin like TouchOp, have a nil result.
+// Writes which return a value keep it; ListAppendOp returns the size of the list.
+type OperateResults []interface{}
+
 // GetOpForBin creates read bin database operation.
 func GetOpForBin(binName string) *Operation {
 	return &Operation{OpType: READ, BinName: &binName, BinValue: NewNullValue()}
 }
 
 // GetOp creates read all record bins database operation.
+// Combined with write operations in Operate, it returns the whole
+// record after the write operations are applied.
 func GetOp() *Operation {
 	return &Operation{OpType: READ, BinValue: NewNullValue()}
 }
@@ -77,6 +95,8 @@ func AddOp(bin *Bin) *Operation {
 }
 
 // TouchOp creates touch database operation.
+// The touch resets the record's expiration to WritePolicy.Expiration,
+// and can be combined with other write operations in a single Operate call.
 func TouchOp() *Operation {
 	return &Operation{OpType: TOUCH, BinValue: NewNullValue()}
 }
@@ -18,7 +18,11 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"net"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
 	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
@@ -27,6 +31,24 @@ import (
 type packer struct {
 	buffer *bytes.Buffer
 	offset int
+
+	// msgpack packs lists and maps the way the other clients pack them;
+	// see WritePolicy.UseMsgpack.
+	msgpack bool
+}
+
+// PackValue returns the bytes v is serialized to when it is sent to the server
+// as an element of a list or a map. The other Aerospike clients read values packed
+// the same way, so the output can be used to assert the serialization of custom
+// types is stable.
+// Maps created with NewMapValue are packed in Go's random map iteration order;
+// maps created with NewOrderedMapValue are packed with their entries sorted by key.
+func PackValue(v Value) ([]byte, error) {
+	packer := newPacker()
+	if err := packer.PackObject(v); err != nil {
+		return nil, err
+	}
+	return packer.buffer.Bytes(), nil
 }
 
 func packValueArray(val []Value) ([]byte, error) {
@@ -53,6 +75,57 @@ func packAnyMap(val map[interface{}]interface{}) ([]byte, error) {
 	return packer.buffer.Bytes(), nil
 }
 
+// msgpackValue repacks list and map values with their string headers
+// in their shortest form and their map entries sorted by key, which is
+// how the Java and Python clients pack them. Other values are returned as is.
+func msgpackValue(v Value) Value {
+	packer := newPacker()
+	packer.msgpack = true
+
+	switch vl := v.(type) {
+	case *ListValue:
+		if err := packer.PackList(vl.list); err != nil {
+			return v
+		}
+		return &ListValue{list: vl.list, bytes: packer.buffer.Bytes()}
+	case *ValueArray:
+		if err := packer.packValueArray(vl.array); err != nil {
+			return v
+		}
+		return &ValueArray{array: vl.array, bytes: packer.buffer.Bytes()}
+	case *MapValue:
+		if err := packer.PackOrderedMap(vl.vmap, vl.order); err != nil {
+			return v
+		}
+		return &MapValue{vmap: vl.vmap, order: vl.order, bytes: packer.buffer.Bytes()}
+	}
+	return v
+}
+
+// msgpackBins returns copies of the bins with their values repacked by msgpackValue.
+func msgpackBins(bins []*Bin) []*Bin {
+	res := make([]*Bin, len(bins))
+	for i := range bins {
+		res[i] = &Bin{Name: bins[i].Name, Value: msgpackValue(bins[i].Value)}
+	}
+	return res
+}
+
+// msgpackOperations returns copies of the operations with the values of
+// their writes repacked by msgpackValue.
+func msgpackOperations(operations []*Operation) []*Operation {
+	res := make([]*Operation, len(operations))
+	for i := range operations {
+		res[i] = operations[i]
+		if operations[i].OpType == WRITE && operations[i].BinValue != nil {
+			op := *operations[i]
+			op.BinValue = msgpackValue(op.BinValue)
+			res[i] = &op
+		}
+	}
+	return res
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 
 func newPacker() *packer {
@@ -94,6 +167,10 @@ func (pckr *packer) PackArrayBegin(size int) {
 }
 
 func (pckr *packer) PackMap(theMap map[interface{}]interface{}) error {
+	if pckr.msgpack {
+		return pckr.packSortedMap(theMap)
+	}
+
 	pckr.PackMapBegin(len(theMap))
 	for k, v := range theMap {
 		if err := pckr.PackObject(k); err != nil {
@@ -106,6 +183,51 @@ func (pckr *packer) PackMap(theMap map[interface{}]interface{}) error {
 	return nil
 }
 
+// PackOrderedMap packs the map with the order flag the server expects
+// as the first entry, followed by the map entries sorted by key.
+func (pckr *packer) PackOrderedMap(theMap map[interface{}]interface{}, order MapOrder) error {
+	if order == UNORDERED {
+		return pckr.PackMap(theMap)
+	}
+
+	pckr.PackMapBegin(len(theMap) + 1)
+	pckr.PackExtHeader(0, byte(order))
+	pckr.PackNil()
+	return pckr.packMapEntries(theMap)
+}
+
+// packSortedMap packs the map with its entries sorted by key.
+func (pckr *packer) packSortedMap(theMap map[interface{}]interface{}) error {
+	pckr.PackMapBegin(len(theMap))
+	return pckr.packMapEntries(theMap)
+}
+
+func (pckr *packer) packMapEntries(theMap map[interface{}]interface{}) error {
+	keys := make([]interface{}, 0, len(theMap))
+	for k := range theMap {
+		keys = append(keys, k)
+	}
+	sort.Sort(mapKeys(keys))
+
+	for _, k := range keys {
+		if err := pckr.PackObject(k); err != nil {
+			return err
+		}
+		if err := pckr.PackObject(theMap[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PackExtHeader packs a msgpack ext8 header with the given
+// payload length and ext type.
+func (pckr *packer) PackExtHeader(length int, extType byte) {
+	pckr.PackAByte(0xc7)
+	pckr.PackAByte(byte(length))
+	pckr.PackAByte(extType)
+}
+
 func (pckr *packer) PackMapBegin(size int) {
 	if size < 16 {
 		pckr.PackAByte(0x80 | byte(size))
@@ -125,6 +247,8 @@ func (pckr *packer) PackBytes(b []byte) {
 func (pckr *packer) PackByteArrayBegin(length int) {
 	if length < 32 {
 		pckr.PackAByte(0xa0 | byte(length))
+	} else if pckr.msgpack && length < 256 {
+		pckr.PackByte(0xd9, byte(length))
 	} else if length < 65536 {
 		pckr.PackShort(0xda, int16(length))
 	} else {
@@ -182,6 +306,12 @@ func (pckr *packer) PackObject(obj interface{}) error {
 	case nil:
 		pckr.PackNil()
 		return nil
+	case time.Time:
+		pckr.PackALong(v.UnixNano())
+		return nil
+	case net.IP:
+		pckr.PackBytes([]byte(v))
+		return nil
 	case bool:
 		pckr.PackBool(v)
 		return nil
@@ -201,6 +331,11 @@ func (pckr *packer) PackObject(obj interface{}) error {
 	switch reflect.TypeOf(obj).Kind() {
 	case reflect.Array, reflect.Slice:
 		s := reflect.ValueOf(obj)
+		if s.Kind() == reflect.Array && s.Type().Elem().Kind() == reflect.Uint8 {
+			pckr.PackBytes(byteArrayToBytes(s))
+			return nil
+		}
+
 		l := s.Len()
 		arr := make([]interface{}, l)
 		for i := 0; i < l; i++ {
@@ -323,6 +458,13 @@ func (pckr *packer) PackString(val string) {
 	pckr.buffer.WriteString(val)
 }
 
+func (pckr *packer) PackGeoJSON(val string) {
+	size := len(val) + 1
+	pckr.PackByteArrayBegin(size)
+	pckr.buffer.WriteByte(byte(ParticleType.GEOJSON))
+	pckr.buffer.WriteString(val)
+}
+
 func (pckr *packer) PackByteArray(src []byte, srcOffset int, srcLength int) {
 	pckr.buffer.Write(src[srcOffset : srcOffset+srcLength])
 }
@@ -351,6 +493,11 @@ func (pckr *packer) PackShort(valType int, val int16) {
 	Buffer.Int16ToBytes(val, pckr.buffer.Bytes(), pos)
 }
 
+func (pckr *packer) PackRawShort(val int16) {
+	pos := pckr.grow(_b2)
+	Buffer.Int16ToBytes(val, pckr.buffer.Bytes(), pos)
+}
+
 func (pckr *packer) PackByte(valType int, val byte) {
 	pckr.buffer.WriteByte(byte(valType))
 	pckr.buffer.WriteByte(val)
@@ -383,3 +530,146 @@ func (pckr *packer) PackFloat64(val float64) {
 func (pckr *packer) PackAByte(val byte) {
 	pckr.buffer.WriteByte(val)
 }
+
+// mapKeys sorts map keys in the order the server sorts them:
+// nil, bool, integer, string, list, map, blob, double.
+// Keys of any other type are sorted last, by type and then by value,
+// so that the order is total and the packed map is deterministic.
+type mapKeys []interface{}
+
+func (mk mapKeys) Len() int           { return len(mk) }
+func (mk mapKeys) Swap(i, j int)      { mk[i], mk[j] = mk[j], mk[i] }
+func (mk mapKeys) Less(i, j int) bool { return compareMapKeys(mk[i], mk[j]) < 0 }
+
+// compareMapKeys returns -1, 0 or 1 when a sorts before, the same as or after b.
+func compareMapKeys(a, b interface{}) int {
+	if v, ok := a.(Value); ok {
+		a = v.GetObject()
+	}
+	if v, ok := b.(Value); ok {
+		b = v.GetObject()
+	}
+
+	ra, rb := mapKeyRank(a), mapKeyRank(b)
+	if ra != rb {
+		return compareInts(int64(ra), int64(rb))
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch ra {
+	case 0:
+		return 0
+	case 1:
+		if va.Bool() == vb.Bool() {
+			return 0
+		}
+		if vb.Bool() {
+			return -1
+		}
+		return 1
+	case 2:
+		return compareInts(mapKeyInt(va), mapKeyInt(vb))
+	case 3:
+		return strings.Compare(va.String(), vb.String())
+	case 4:
+		for i := 0; i < va.Len() && i < vb.Len(); i++ {
+			if c := compareMapKeys(va.Index(i).Interface(), vb.Index(i).Interface()); c != 0 {
+				return c
+			}
+		}
+		return compareInts(int64(va.Len()), int64(vb.Len()))
+	case 5:
+		if c := compareInts(int64(va.Len()), int64(vb.Len())); c != 0 {
+			return c
+		}
+		ka, kb := sortedMapKeys(va), sortedMapKeys(vb)
+		for i := range ka {
+			if c := compareMapKeys(ka[i].Interface(), kb[i].Interface()); c != 0 {
+				return c
+			}
+		}
+		for i := range ka {
+			if c := compareMapKeys(va.MapIndex(ka[i]).Interface(), vb.MapIndex(kb[i]).Interface()); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case 6:
+		return bytes.Compare(mapKeyBytes(va), mapKeyBytes(vb))
+	case 7:
+		fa, fb := va.Float(), vb.Float()
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+
+	if c := strings.Compare(va.Type().String(), vb.Type().String()); c != 0 {
+		return c
+	}
+	return strings.Compare(fmt.Sprintf("%#v", a), fmt.Sprintf("%#v", b))
+}
+
+func mapKeyRank(key interface{}) int {
+	if key == nil {
+		return 0
+	}
+
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.Bool:
+		return 1
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return 2
+	case reflect.String:
+		return 3
+	case reflect.Array, reflect.Slice:
+		// byte arrays and slices are packed as blobs
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return 6
+		}
+		return 4
+	case reflect.Map:
+		return 5
+	case reflect.Float32, reflect.Float64:
+		return 7
+	}
+	return 8
+}
+
+func sortedMapKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return compareMapKeys(keys[i].Interface(), keys[j].Interface()) < 0
+	})
+	return keys
+}
+
+func mapKeyBytes(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+	return byteArrayToBytes(v)
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func mapKeyInt(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
@@ -405,4 +405,35 @@ var _ = Describe("Packing Test", func() {
 			Expect(testPackingFor(vStr)).To(Equal(retStr))
 		})
 	})
+
+	Context("Public Packing API", func() {
+
+		It("should pack values to stable bytes and unpack them back", func() {
+			b, err := PackValue(NewValue([]interface{}{1, "a"}))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(Equal([]byte{0x92, 0x01, 0xa2, 0x03, 0x61}))
+
+			b, err = PackValue(NewOrderedMapValue(map[interface{}]interface{}{"b": 2, "a": 1}, KEY_ORDERED))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b).To(Equal([]byte{0x83, 0xc7, 0x00, 0x01, 0xc0, 0xa2, 0x03, 0x61, 0x01, 0xa2, 0x03, 0x62, 0x02}))
+
+			v, err := UnpackValue(b)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(v).To(Equal(map[interface{}]interface{}{"a": 1, "b": 2}))
+		})
+
+		It("should return an error for invalid packed values", func() {
+			b, err := PackValue(NewValue([]interface{}{1, 2, 3}))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = UnpackValue(nil)
+			Expect(err).To(HaveOccurred())
+
+			_, err = UnpackValue(b[:2])
+			Expect(err).To(HaveOccurred())
+
+			_, err = UnpackValue(append(b, 1))
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,29 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// PartialResultError is returned by BatchExists when some of the nodes
+// could not be reached. The results resolved from the other nodes are
+// still valid, and are carried in the error.
+type PartialResultError struct {
+	error
+
+	// Exists is aligned positionally with the requested keys.
+	// Positions listed in FailedIndexes are not resolved and set to false.
+	Exists []bool
+
+	// FailedIndexes holds the positions of keys whose node failed to respond.
+	FailedIndexes []int
+}
@@ -0,0 +1,220 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sync"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// version of the continuation token format
+const _PARTITION_TOKEN_VERSION = 1
+
+// PartitionFilter determines the range of partitions scanned by Client.ScanPartitions.
+// While the scan runs, the filter records which partitions have been scanned completely,
+// so that an interrupted scan can be resumed with the filter returned by Remaining.
+type PartitionFilter struct {
+	// Begin is the id of the first partition to scan.
+	Begin int
+	// Count is the number of partitions to scan.
+	Count int
+
+	mutex sync.RWMutex
+	done  map[int]bool
+	// digests of the last records returned from the partitions
+	// which have not been completed, to resume queries after them
+	digests map[int][]byte
+}
+
+// NewPartitionFilterAll creates a filter for all the partitions of a namespace.
+func NewPartitionFilterAll() *PartitionFilter {
+	return NewPartitionFilterByRange(0, _PARTITIONS)
+}
+
+// NewPartitionFilterById creates a filter for a single partition.
+func NewPartitionFilterById(partitionId int) *PartitionFilter {
+	return NewPartitionFilterByRange(partitionId, 1)
+}
+
+// NewPartitionFilterByRange creates a filter for count partitions starting from begin.
+func NewPartitionFilterByRange(begin, count int) *PartitionFilter {
+	return &PartitionFilter{
+		Begin:   begin,
+		Count:   count,
+		done:    make(map[int]bool),
+		digests: make(map[int][]byte),
+	}
+}
+
+// IsDone returns true if the partition has been scanned completely.
+func (pf *PartitionFilter) IsDone(partitionId int) bool {
+	pf.mutex.RLock()
+	defer pf.mutex.RUnlock()
+	return pf.done[partitionId]
+}
+
+// Cursor returns the id of the last partition for which it and all the partitions
+// before it in the filter have been scanned completely. It returns Begin - 1 if
+// the first partition has not been scanned completely yet.
+func (pf *PartitionFilter) Cursor() int {
+	pf.mutex.RLock()
+	defer pf.mutex.RUnlock()
+
+	cursor := pf.Begin - 1
+	for id := pf.Begin; id < pf.Begin+pf.Count && pf.done[id]; id++ {
+		cursor = id
+	}
+	return cursor
+}
+
+// Remaining returns a filter to resume the scan with. It starts after the cursor,
+// and skips the partitions after the cursor which have been scanned completely.
+// It returns nil once all the partitions of the filter have been scanned completely.
+func (pf *PartitionFilter) Remaining() *PartitionFilter {
+	cursor := pf.Cursor()
+	if cursor == pf.Begin+pf.Count-1 {
+		return nil
+	}
+	res := NewPartitionFilterByRange(cursor+1, pf.Begin+pf.Count-cursor-1)
+
+	pf.mutex.RLock()
+	for id := range pf.done {
+		if id > cursor {
+			res.done[id] = true
+		}
+	}
+	for id, digest := range pf.digests {
+		if id > cursor && !pf.done[id] {
+			res.digests[id] = digest
+		}
+	}
+	pf.mutex.RUnlock()
+
+	return res
+}
+
+// String implements the Stringer interface.
+func (pf *PartitionFilter) String() string {
+	return fmt.Sprintf("partitions %d-%d, cursor: %d", pf.Begin, pf.Begin+pf.Count-1, pf.Cursor())
+}
+
+func (pf *PartitionFilter) validate() error {
+	if pf == nil {
+		return NewAerospikeError(PARAMETER_ERROR, "Partition filter is nil")
+	}
+	if pf.Begin < 0 || pf.Count <= 0 || pf.Begin+pf.Count > _PARTITIONS {
+		return NewAerospikeError(PARAMETER_ERROR, fmt.Sprintf("Invalid partition range: begin %d, count %d", pf.Begin, pf.Count))
+	}
+	return nil
+}
+
+func (pf *PartitionFilter) markDone(partitionId int) {
+	pf.mutex.Lock()
+	if pf.done == nil {
+		pf.done = make(map[int]bool)
+	}
+	pf.done[partitionId] = true
+	delete(pf.digests, partitionId)
+	pf.mutex.Unlock()
+}
+
+// setDigest records the digest of the last record returned from the partition of the key.
+func (pf *PartitionFilter) setDigest(key *Key) {
+	partitionId := NewPartitionByKey(key).PartitionId
+	pf.mutex.Lock()
+	if pf.digests == nil {
+		pf.digests = make(map[int][]byte)
+	}
+	pf.digests[partitionId] = key.Digest()
+	pf.mutex.Unlock()
+}
+
+// digest returns the digest of the last record returned from the partition, or nil.
+func (pf *PartitionFilter) digest(partitionId int) []byte {
+	pf.mutex.RLock()
+	defer pf.mutex.RUnlock()
+	return pf.digests[partitionId]
+}
+
+// isComplete returns true if all the partitions of the filter have been completed.
+func (pf *PartitionFilter) isComplete() bool {
+	return pf.Cursor() == pf.Begin+pf.Count-1
+}
+
+// token serializes the progress of the filter into an opaque, URL safe string.
+// The format is: version, begin and count as 2 byte integers, a bitmap of the
+// completed partitions, then the partition id and digest of each pending partition.
+func (pf *PartitionFilter) token() string {
+	pf.mutex.RLock()
+	defer pf.mutex.RUnlock()
+
+	buf := make([]byte, 5+(pf.Count+7)/8, 5+(pf.Count+7)/8+len(pf.digests)*(2+20))
+	buf[0] = _PARTITION_TOKEN_VERSION
+	buf[1], buf[2] = byte(pf.Begin>>8), byte(pf.Begin)
+	buf[3], buf[4] = byte(pf.Count>>8), byte(pf.Count)
+
+	for id := range pf.done {
+		i := id - pf.Begin
+		buf[5+i/8] |= 1 << uint(i%8)
+	}
+
+	for id := pf.Begin; id < pf.Begin+pf.Count; id++ {
+		if digest := pf.digests[id]; digest != nil {
+			buf = append(buf, byte(id>>8), byte(id))
+			buf = append(buf, digest...)
+		}
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// newPartitionFilterFromToken restores a filter serialized by token.
+func newPartitionFilterFromToken(token string) (*PartitionFilter, error) {
+	invalidErr := NewAerospikeError(PARAMETER_ERROR, "Invalid continuation token")
+
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) < 5 || buf[0] != _PARTITION_TOKEN_VERSION {
+		return nil, invalidErr
+	}
+
+	pf := NewPartitionFilterByRange(int(buf[1])<<8|int(buf[2]), int(buf[3])<<8|int(buf[4]))
+	if pf.validate() != nil {
+		return nil, invalidErr
+	}
+
+	offset := 5 + (pf.Count+7)/8
+	if len(buf) < offset || (len(buf)-offset)%(2+20) != 0 {
+		return nil, invalidErr
+	}
+
+	for i := 0; i < pf.Count; i++ {
+		if buf[5+i/8]&(1<<uint(i%8)) != 0 {
+			pf.done[pf.Begin+i] = true
+		}
+	}
+
+	for ; offset < len(buf); offset += 2 + 20 {
+		id := int(buf[offset])<<8 | int(buf[offset+1])
+		if id < pf.Begin || id >= pf.Begin+pf.Count {
+			return nil, invalidErr
+		}
+		pf.digests[id] = append([]byte(nil), buf[offset+2:offset+2+20]...)
+	}
+
+	return pf, nil
+}
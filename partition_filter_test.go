@@ -0,0 +1,151 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"encoding/base64"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartitionFilter Test", func() {
+
+	It("must record the progress of a filter which was not created by a constructor", func() {
+		key, err := NewKey("test", "test", 1)
+		Expect(err).ToNot(HaveOccurred())
+		partitionId := NewPartitionByKey(key).PartitionId
+
+		filter := &PartitionFilter{Begin: partitionId, Count: 2}
+		filter.setDigest(key)
+		Expect(filter.digest(partitionId)).To(Equal(key.Digest()))
+
+		filter.markDone(partitionId)
+		Expect(filter.IsDone(partitionId)).To(BeTrue())
+		Expect(filter.digest(partitionId)).To(BeNil())
+		Expect(filter.Cursor()).To(Equal(partitionId))
+	})
+
+	It("must not return a remaining filter once all the partitions are done", func() {
+		filter := NewPartitionFilterByRange(_PARTITIONS-2, 2)
+
+		filter.markDone(_PARTITIONS - 1)
+		remaining := filter.Remaining()
+		Expect(remaining.Begin).To(Equal(_PARTITIONS - 2))
+		Expect(remaining.Count).To(Equal(2))
+		Expect(remaining.IsDone(_PARTITIONS - 1)).To(BeTrue())
+
+		filter.markDone(_PARTITIONS - 2)
+		Expect(filter.Remaining()).To(BeNil())
+		Expect(filter.Remaining().validate()).To(HaveOccurred())
+	})
+
+	Context("Continuation tokens", func() {
+
+		// newFilter returns a filter with completed partitions and partitions with a digest
+		newFilter := func() *PartitionFilter {
+			filter := NewPartitionFilterByRange(1000, 20)
+			filter.markDone(1000)
+			filter.markDone(1009)
+			filter.markDone(1019)
+			filter.digests[1001] = []byte("01234567890123456789")
+			filter.digests[1010] = []byte("abcdefghijabcdefghij")
+			return filter
+		}
+
+		// tokenBytes returns the decoded token of the filter
+		tokenBytes := func(filter *PartitionFilter) []byte {
+			buf, err := base64.RawURLEncoding.DecodeString(filter.token())
+			Expect(err).ToNot(HaveOccurred())
+			return buf
+		}
+
+		encode := func(buf []byte) string {
+			return base64.RawURLEncoding.EncodeToString(buf)
+		}
+
+		It("must restore the progress of the filter from its token", func() {
+			filter := newFilter()
+
+			res, err := newPartitionFilterFromToken(filter.token())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.Begin).To(Equal(1000))
+			Expect(res.Count).To(Equal(20))
+			Expect(res.done).To(Equal(filter.done))
+			Expect(res.digests).To(Equal(filter.digests))
+			Expect(res.Cursor()).To(Equal(1000))
+			Expect(res.token()).To(Equal(filter.token()))
+		})
+
+		It("must restore filters without progress and filters of all the partitions", func() {
+			for _, filter := range []*PartitionFilter{NewPartitionFilterById(7), NewPartitionFilterAll()} {
+				res, err := newPartitionFilterFromToken(filter.token())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.Begin).To(Equal(filter.Begin))
+				Expect(res.Count).To(Equal(filter.Count))
+				Expect(res.done).To(BeEmpty())
+				Expect(res.digests).To(BeEmpty())
+			}
+		})
+
+		It("must reject tokens of another version", func() {
+			buf := tokenBytes(newFilter())
+			buf[0] = _PARTITION_TOKEN_VERSION + 1
+
+			_, err := newPartitionFilterFromToken(encode(buf))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must reject tokens of an invalid length", func() {
+			buf := tokenBytes(newFilter())
+
+			for _, l := range []int{0, 4, 6, len(buf) - 1, len(buf) - 21} {
+				_, err := newPartitionFilterFromToken(encode(buf[:l]))
+				Expect(err).To(HaveOccurred())
+			}
+			_, err := newPartitionFilterFromToken(encode(append(buf, 0)))
+			Expect(err).To(HaveOccurred())
+
+			_, err = newPartitionFilterFromToken("not a token!")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("must reject tokens with partitions out of the range of the filter", func() {
+			// a digest of a partition before the range
+			buf := tokenBytes(newFilter())
+			buf[8], buf[9] = byte(999>>8), byte(999&0xff)
+			_, err := newPartitionFilterFromToken(encode(buf))
+			Expect(err).To(HaveOccurred())
+
+			// a digest of a partition after the range
+			buf[8], buf[9] = byte(1020>>8), byte(1020&0xff)
+			_, err = newPartitionFilterFromToken(encode(buf))
+			Expect(err).To(HaveOccurred())
+
+			// a range past the last partition
+			buf = tokenBytes(NewPartitionFilterById(7))
+			buf[1], buf[2] = byte(_PARTITIONS>>8), byte(_PARTITIONS&0xff)
+			_, err = newPartitionFilterFromToken(encode(buf))
+			Expect(err).To(HaveOccurred())
+
+			// an empty range
+			buf = tokenBytes(NewPartitionFilterById(7))
+			buf[3], buf[4] = 0, 0
+			_, err = newPartitionFilterFromToken(encode(buf))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+})
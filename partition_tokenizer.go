@@ -14,4 +14,7 @@
 
 package aerospike
 
-const replicasName = "replicas-master"
+const (
+	replicasName      = "replicas-master"
+	replicasProleName = "replicas-prole"
+)
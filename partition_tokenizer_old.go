@@ -30,21 +30,22 @@ type partitionTokenizerOld struct {
 	offset int
 }
 
-func newPartitionTokenizerOld(conn *Connection) (*partitionTokenizerOld, error) {
+func newPartitionTokenizerOld(conn *Connection, name string) (*partitionTokenizerOld, error) {
 	pt := &partitionTokenizerOld{}
 
 	// Use low-level info methods and parse byte array directly for maximum performance.
 	// Send format:    replicas-master\n
 	// Receive format: replicas-master\t<ns1>:<base 64 encoded bitmap>;<ns2>:<base 64 encoded bitmap>... \n
-	infoMap, err := RequestInfo(conn, replicasName)
+	// The same format is used for replicas-prole.
+	infoMap, err := RequestInfo(conn, name)
 	if err != nil {
 		return nil, err
 	}
 
-	info := infoMap[replicasName]
+	info := infoMap[name]
 	pt.length = len(info)
 	if pt.length == 0 {
-		return nil, NewAerospikeError(PARSE_ERROR, replicasName+" is empty")
+		return nil, NewAerospikeError(PARSE_ERROR, name+" is empty")
 	}
 
 	pt.buffer = []byte(info)
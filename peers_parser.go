@@ -0,0 +1,169 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"strconv"
+	"strings"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+)
+
+// peersParser parses the peers-tls-std info response, which carries
+// the TLS name of each peer along with its addresses:
+//
+//	<generation>,<default port>,[[<node name>,<tls name>,[<address>[:<port>],...]],...]
+type peersParser struct {
+	s   string
+	pos int
+}
+
+// parsePeers returns the hosts listed in a peers info response.
+// Each host carries the TLS name of its node.
+func parsePeers(s string) ([]*Host, error) {
+	p := &peersParser{s: s}
+
+	// generation is not used
+	if _, err := p.readUntil(','); err != nil {
+		return nil, err
+	}
+
+	portStr, err := p.readUntil(',')
+	if err != nil {
+		return nil, err
+	}
+
+	defaultPort := 0
+	if portStr != "" {
+		if defaultPort, err = strconv.Atoi(portStr); err != nil {
+			return nil, p.error()
+		}
+	}
+
+	if err := p.expect('['); err != nil {
+		return nil, err
+	}
+
+	hosts := []*Host{}
+	for !p.peek(']') {
+		if err := p.expect('['); err != nil {
+			return nil, err
+		}
+
+		// node name is not used
+		if _, err := p.readUntil(','); err != nil {
+			return nil, err
+		}
+
+		tlsName, err := p.readUntil(',')
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect('['); err != nil {
+			return nil, err
+		}
+
+		for !p.peek(']') {
+			host, err := p.readHost(defaultPort)
+			if err != nil {
+				return nil, err
+			}
+			host.TLSName = tlsName
+			hosts = append(hosts, host)
+
+			if p.peek(',') {
+				p.pos++
+			}
+		}
+		p.pos++ // addresses ']'
+
+		if err := p.expect(']'); err != nil {
+			return nil, err
+		}
+
+		if p.peek(',') {
+			p.pos++
+		}
+	}
+
+	return hosts, nil
+}
+
+func (p *peersParser) error() error {
+	return NewAerospikeError(PARSE_ERROR, "Invalid peers info response at position "+strconv.Itoa(p.pos)+": "+p.s)
+}
+
+func (p *peersParser) peek(c byte) bool {
+	return p.pos < len(p.s) && p.s[p.pos] == c
+}
+
+func (p *peersParser) expect(c byte) error {
+	if !p.peek(c) {
+		return p.error()
+	}
+	p.pos++
+	return nil
+}
+
+// readUntil returns the text up to the delimiter, and skips the delimiter.
+func (p *peersParser) readUntil(delim byte) (string, error) {
+	idx := strings.IndexByte(p.s[p.pos:], delim)
+	if idx < 0 {
+		return "", p.error()
+	}
+
+	res := p.s[p.pos : p.pos+idx]
+	p.pos += idx + 1
+	return res, nil
+}
+
+// readHost reads an address in the form of host, host:port, [ipv6] or [ipv6]:port.
+func (p *peersParser) readHost(defaultPort int) (*Host, error) {
+	var name string
+	if p.peek('[') {
+		p.pos++
+		idx := strings.IndexByte(p.s[p.pos:], ']')
+		if idx < 0 {
+			return nil, p.error()
+		}
+		name = p.s[p.pos : p.pos+idx]
+		p.pos += idx + 1
+	} else {
+		idx := strings.IndexAny(p.s[p.pos:], ":,]")
+		if idx < 0 {
+			return nil, p.error()
+		}
+		name = p.s[p.pos : p.pos+idx]
+		p.pos += idx
+	}
+
+	port := defaultPort
+	if p.peek(':') {
+		p.pos++
+		idx := strings.IndexAny(p.s[p.pos:], ",]")
+		if idx < 0 {
+			return nil, p.error()
+		}
+
+		var err error
+		if port, err = strconv.Atoi(p.s[p.pos : p.pos+idx]); err != nil {
+			return nil, p.error()
+		}
+		p.pos += idx
+	}
+
+	return NewHost(name, port), nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func newTLSHost(name string, port int, tlsName string) *Host {
+	host := NewHost(name, port)
+	host.TLSName = tlsName
+	return host
+}
+
+var _ = Describe("Peers Parser Test", func() {
+
+	It("must parse the hosts of valid peers responses", func() {
+		cases := []struct {
+			response string
+			hosts    []*Host
+		}{
+			{"3,3000,[]", []*Host{}},
+			{
+				"5,3000,[[BB9020011AC4202,,[172.17.0.3]]]",
+				[]*Host{NewHost("172.17.0.3", 3000)},
+			},
+			{
+				"5,,[[BB9020011AC4202,,[172.17.0.3:3100]]]",
+				[]*Host{NewHost("172.17.0.3", 3100)},
+			},
+			{
+				"7,4333,[[BB9020011AC4202,node2.tls,[10.0.0.2,10.0.1.2:4334]],[BB9030011AC4202,node3.tls,[10.0.0.3]]]",
+				[]*Host{
+					newTLSHost("10.0.0.2", 4333, "node2.tls"),
+					newTLSHost("10.0.1.2", 4334, "node2.tls"),
+					newTLSHost("10.0.0.3", 4333, "node3.tls"),
+				},
+			},
+			{
+				"2,3000,[[BB9020011AC4202,,[[2001:db8::1],[2001:db8::2]:3100]]]",
+				[]*Host{NewHost("2001:db8::1", 3000), NewHost("2001:db8::2", 3100)},
+			},
+			{
+				"2,3000,[[BB9020011AC4202,,[]]]",
+				[]*Host{},
+			},
+		}
+
+		for _, c := range cases {
+			hosts, err := parsePeers(c.response)
+			Expect(err).ToNot(HaveOccurred(), c.response)
+			Expect(hosts).To(Equal(c.hosts), c.response)
+		}
+	})
+
+	It("must reject truncated and malformed peers responses", func() {
+		responses := []string{
+			"",
+			"3",
+			"3,3000",
+			"3,3000,",
+			"3,3000,[",
+			"3,3000,[[BB9020011AC4202",
+			"3,3000,[[BB9020011AC4202,,",
+			"3,3000,[[BB9020011AC4202,,[172.17.0.3",
+			"3,3000,[[BB9020011AC4202,,[172.17.0.3]",
+			"3,3000,[[BB9020011AC4202,,[[2001:db8::1",
+			"3,3000,[[BB9020011AC4202,,[172.17.0.3:port]]]",
+			"3,port,[]",
+			"3,3000,BB9020011AC4202",
+			"3,3000,[BB9020011AC4202,,[172.17.0.3]]",
+		}
+
+		for _, response := range responses {
+			_, err := parsePeers(response)
+			Expect(err).To(HaveOccurred(), response)
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARSE_ERROR), response)
+		}
+	})
+
+})
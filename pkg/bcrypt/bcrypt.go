@@ -0,0 +1,236 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bcrypt implements the bcrypt password hashing function,
+// which the Aerospike server uses to store the passwords of its users.
+package bcrypt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	// SaltSize is the size of a decoded salt in bytes.
+	SaltSize = 16
+
+	// encoded sizes of the salt and of the hash
+	encodedSaltSize = 22
+	encodedHashSize = 31
+
+	// passwords longer than this are truncated, including the terminating zero
+	maxKeySize = 72
+)
+
+// magic text which is encrypted by the expensive key setup
+var magicText = []byte("OrpheanBeholderScryDoubt")
+
+// bcrypt uses its own base64 alphabet, without padding
+const alphabet = "./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+var decodeMap [256]byte
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = 0xff
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decodeMap[alphabet[i]] = byte(i)
+	}
+}
+
+// HashPassword hashes the password with the salt, which must be in the
+// "$2a$<cost>$<22 characters>" format, and returns the hash in the same format,
+// with the 31 characters of the hash appended.
+func HashPassword(password string, salt string) (string, error) {
+	if len(salt) < 7+encodedSaltSize || salt[0] != '$' || salt[1] != '2' || salt[3] != '$' || salt[6] != '$' {
+		return "", errors.New("bcrypt: invalid salt")
+	}
+
+	minor := salt[2]
+	if minor != 'a' && minor != 'b' && minor != 'y' {
+		return "", fmt.Errorf("bcrypt: unsupported version 2%c", minor)
+	}
+
+	cost, err := strconv.Atoi(salt[4:6])
+	if err != nil || cost < 4 || cost > 31 {
+		return "", errors.New("bcrypt: invalid cost")
+	}
+
+	saltBytes, err := decode(salt[7:7+encodedSaltSize], SaltSize)
+	if err != nil {
+		return "", err
+	}
+
+	key := append([]byte(password), 0)
+	if len(key) > maxKeySize {
+		key = key[:maxKeySize]
+	}
+
+	c := newCipher(key, saltBytes, uint(cost))
+
+	text := make([]uint32, len(magicText)/4)
+	for i := range text {
+		text[i] = streamToWord(magicText[i*4:i*4+4], new(int))
+	}
+
+	for i := 0; i < 64; i++ {
+		for j := 0; j < len(text); j += 2 {
+			text[j], text[j+1] = c.encrypt(text[j], text[j+1])
+		}
+	}
+
+	hash := make([]byte, 0, len(text)*4)
+	for _, w := range text {
+		hash = append(hash, byte(w>>24), byte(w>>16), byte(w>>8), byte(w))
+	}
+
+	// the last byte of the encrypted text is not used
+	return salt[:7] + encode(saltBytes) + encode(hash[:23]), nil
+}
+
+// cipher is the state of the Blowfish cipher.
+type cipher struct {
+	p              [18]uint32
+	s0, s1, s2, s3 [256]uint32
+}
+
+// newCipher runs the expensive key setup of bcrypt.
+func newCipher(key []byte, salt []byte, cost uint) *cipher {
+	c := &cipher{
+		p:  initialP,
+		s0: initialS0,
+		s1: initialS1,
+		s2: initialS2,
+		s3: initialS3,
+	}
+
+	c.expandKey(key, salt)
+	for i := uint64(0); i < 1<<cost; i++ {
+		c.expandKey(key, nil)
+		c.expandKey(salt, nil)
+	}
+	return c
+}
+
+// expandKey mixes the key into the P-array, and replaces the P-array and
+// the S-boxes with the encrypted salt. Without salt, zeros are encrypted.
+func (c *cipher) expandKey(key []byte, salt []byte) {
+	j := 0
+	for i := range c.p {
+		c.p[i] ^= streamToWord(key, &j)
+	}
+
+	j = 0
+	var l, r uint32
+	next := func() {
+		if salt != nil {
+			l ^= streamToWord(salt, &j)
+			r ^= streamToWord(salt, &j)
+		}
+		l, r = c.encrypt(l, r)
+	}
+
+	for i := 0; i < len(c.p); i += 2 {
+		next()
+		c.p[i], c.p[i+1] = l, r
+	}
+
+	for _, s := range []*[256]uint32{&c.s0, &c.s1, &c.s2, &c.s3} {
+		for i := 0; i < len(s); i += 2 {
+			next()
+			s[i], s[i+1] = l, r
+		}
+	}
+}
+
+func (c *cipher) f(x uint32) uint32 {
+	return ((c.s0[x>>24] + c.s1[byte(x>>16)]) ^ c.s2[byte(x>>8)]) + c.s3[byte(x)]
+}
+
+// encrypt encrypts a 64 bit block with 16 Feistel rounds.
+func (c *cipher) encrypt(l, r uint32) (uint32, uint32) {
+	for i := 0; i < 16; i += 2 {
+		l ^= c.p[i]
+		r ^= c.f(l)
+		r ^= c.p[i+1]
+		l ^= c.f(r)
+	}
+	l ^= c.p[16]
+	r ^= c.p[17]
+	return r, l
+}
+
+// streamToWord reads the next 4 bytes of data as a big endian word,
+// starting at offset j and wrapping around at the end of data.
+func streamToWord(data []byte, j *int) uint32 {
+	var w uint32
+	for i := 0; i < 4; i++ {
+		w = w<<8 | uint32(data[*j])
+		*j = (*j + 1) % len(data)
+	}
+	return w
+}
+
+// encode encodes the bytes with the bcrypt base64 alphabet, without padding.
+func encode(src []byte) string {
+	res := make([]byte, 0, (len(src)*8+5)/6)
+	for i := 0; i < len(src); i += 3 {
+		var v uint
+		n := len(src) - i
+		if n > 3 {
+			n = 3
+		}
+		for j := 0; j < 3; j++ {
+			v <<= 8
+			if j < n {
+				v |= uint(src[i+j])
+			}
+		}
+		for j := 0; j <= n; j++ {
+			res = append(res, alphabet[(v>>uint(18-6*j))&0x3f])
+		}
+	}
+	return string(res)
+}
+
+// decode decodes size bytes from the bcrypt base64 encoded string.
+func decode(src string, size int) ([]byte, error) {
+	res := make([]byte, 0, size+2)
+	for i := 0; i < len(src); i += 4 {
+		var v uint
+		n := len(src) - i
+		if n > 4 {
+			n = 4
+		}
+		for j := 0; j < 4; j++ {
+			v <<= 6
+			if j < n {
+				d := decodeMap[src[i+j]]
+				if d == 0xff {
+					return nil, fmt.Errorf("bcrypt: invalid character %q in salt", src[i+j])
+				}
+				v |= uint(d)
+			}
+		}
+		for j := 0; j < n-1; j++ {
+			res = append(res, byte(v>>uint(16-8*j)))
+		}
+	}
+	if len(res) < size {
+		return nil, errors.New("bcrypt: salt is too short")
+	}
+	return res[:size], nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bcrypt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	tests := []struct {
+		password string
+		salt     string
+		hash     string
+	}{
+		{"U*U", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"},
+		{"", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.7uG0VCzI2bS7j6ymqJi9CdcdxiRTWNy"},
+		{"admin", "$2a$10$7EqJtq98hPqEX7fNZaFWoO", "$2a$10$7EqJtq98hPqEX7fNZaFWoO1mVO/4MLpGzsqojz6E9Gef6iXDjXdDa"},
+		{"0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", "$2a$06$DCq7YPn5Rq63x1Lad4cll.", "$2a$06$DCq7YPn5Rq63x1Lad4cll.iOwYnU8NPe8ya.PVBumRpOY/penNDg."},
+		// passwords are truncated to 72 bytes
+		{strings.Repeat("x", 80), "$2a$04$abcdefghijklmnopqrstuu", "$2a$04$abcdefghijklmnopqrstuubzadhGtS2zEF.gu0yd0opP6cVzb.e0i"},
+	}
+
+	for _, test := range tests {
+		hash, err := HashPassword(test.password, test.salt)
+		if err != nil {
+			t.Fatalf("HashPassword(%q, %q) failed: %s", test.password, test.salt, err)
+		}
+		if hash != test.hash {
+			t.Errorf("HashPassword(%q, %q) = %q, want %q", test.password, test.salt, hash, test.hash)
+		}
+	}
+}
+
+func TestHashPasswordInvalidSalt(t *testing.T) {
+	for _, salt := range []string{"", "$2a$10$short", "$3a$10$7EqJtq98hPqEX7fNZaFWoO", "$2a$99$7EqJtq98hPqEX7fNZaFWoO", "$2a$10$7EqJtq98hPqEX7fNZaFW!O"} {
+		if _, err := HashPassword("admin", salt); err == nil {
+			t.Errorf("HashPassword with salt %q should fail", salt)
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package aerospike
 
 import (
+	"math/rand"
 	"time"
 )
 
@@ -39,6 +40,28 @@ type BasePolicy struct {
 	// Default to no timeout (0).
 	Timeout time.Duration
 
+	// SocketTimeout determines how long each socket read or write may block
+	// without any data being transferred. The socket deadline is refreshed after
+	// every partial read or write, so large records on slow links do not time out
+	// while data is still flowing. Timeout still bounds the whole transaction.
+	// Default to no socket timeout (0); the deadline of Timeout is then fixed.
+	SocketTimeout time.Duration
+
+	// AdaptiveTimeoutMultiplier enables adaptive socket timeouts when set.
+	// The socket timeout of each attempt is then the 99th percentile of the recent
+	// latency of the node, multiplied by AdaptiveTimeoutMultiplier, so that commands
+	// on healthy nodes fail fast while slow nodes are still tolerated.
+	// Attempts which time out count as samples at their timeout, so the timeout
+	// widens again if the latency of a node rises above it.
+	// Timeout, if set, still caps the socket timeout, and is used until the node has
+	// completed enough commands to estimate its latency.
+	// Adaptive timeouts apply to single record and batch commands. Scans and queries,
+	// whose duration depends on the number of records, keep the fixed Timeout.
+	AdaptiveTimeoutMultiplier float64 //= 0;
+
+	// MinAdaptiveTimeout is the lower bound of the adaptive socket timeout.
+	MinAdaptiveTimeout time.Duration //= 10ms;
+
 	// MaxRetries determines maximum number of retries before aborting the current transaction.
 	// A retry is attempted when there is a network error other than timeout.
 	// If maxRetries is exceeded, the abort will occur even if the timeout
@@ -48,6 +71,53 @@ type BasePolicy struct {
 	// SleepBetweenReplies determines duration to sleep between retries if a transaction fails and the
 	// timeout was not exceeded.  Enter zero to skip sleep.
 	SleepBetweenRetries time.Duration //= 500ms;
+
+	// MaxRetryBackoff enables exponential backoff between retries when set.
+	// The sleep duration starts at SleepBetweenRetries and doubles on each retry,
+	// capped at MaxRetryBackoff. Zero keeps the fixed SleepBetweenRetries sleep.
+	MaxRetryBackoff time.Duration //= 0;
+
+	// RetryJitter randomizes the sleep between retries by a factor in [0.5, 1.0)
+	// to avoid many clients retrying at the same time. It applies with and
+	// without MaxRetryBackoff.
+	RetryJitter bool //= false;
+
+	// BackpressureBackoff determines how long to wait before retrying a command
+	// which an overloaded node rejected with the DEVICE_OVERLOAD or QUERY_QUEUEFULL
+	// result codes. It is usually much longer than SleepBetweenRetries, so that the
+	// retries do not add to the load of the node. The retries still count towards
+	// MaxRetries and Timeout.
+	// Zero does not retry such commands; a *BackpressureError is returned right away.
+	BackpressureBackoff time.Duration //= 0;
+
+	// ShouldRetry, when set, decides whether a command is retried after a failed
+	// attempt, instead of the built-in rules. attempt is the number of the failed
+	// attempt, starting at 1, and err its error; write errors which may have been
+	// applied on the server are marked as in doubt. MaxRetries is then ignored, so
+	// the callback must give up eventually; Timeout and NoRetryInDoubt still apply.
+	// Scans and queries are not retried once the server returned an error, since
+	// they may have returned records already.
+	ShouldRetry func(attempt int, err error) bool //= nil;
+
+	// UseCompression compresses commands larger than 128 bytes, and asks the
+	// server to compress its responses. It only applies to nodes which
+	// support compression; other nodes are sent uncompressed commands.
+	UseCompression bool //= false;
+
+	// CompressionAlgorithm determines how commands are compressed.
+	CompressionAlgorithm CompressionAlgorithm //= COMPRESSION_ZLIB;
+
+	// ReplicaPolicy determines the node to read single records from.
+	// Writes are always sent to the master node.
+	ReplicaPolicy ReplicaPolicy //= MASTER;
+
+	// ReadModeAP determines how many replicas to consult when reading
+	// from AP (availability) mode namespaces.
+	ReadModeAP ReadModeAP //= ReadModeAPOne;
+
+	// ReadModeSC determines the read consistency guarantee when reading
+	// from SC (strong consistency) mode namespaces.
+	ReadModeSC ReadModeSC //= ReadModeSCSession;
 }
 
 // NewPolicy generates a new BasePolicy instance with default values.
@@ -57,6 +127,7 @@ func NewPolicy() *BasePolicy {
 		Timeout:             0 * time.Millisecond,
 		MaxRetries:          2,
 		SleepBetweenRetries: 500 * time.Millisecond,
+		MinAdaptiveTimeout:  10 * time.Millisecond,
 	}
 }
 
@@ -64,3 +135,27 @@ var _ Policy = &BasePolicy{}
 
 // GetBasePolicy returns embedded BasePolicy in all types that embed this struct.
 func (p *BasePolicy) GetBasePolicy() *BasePolicy { return p }
+
+// retryDelay returns the duration to sleep before the specified retry.
+// retry is zero for the first retry.
+func (p *BasePolicy) retryDelay(retry int) time.Duration {
+	delay := p.SleepBetweenRetries
+	if delay <= 0 {
+		return delay
+	}
+
+	if p.MaxRetryBackoff > 0 {
+		for i := 0; i < retry && delay < p.MaxRetryBackoff; i++ {
+			delay *= 2
+		}
+
+		if delay > p.MaxRetryBackoff {
+			delay = p.MaxRetryBackoff
+		}
+	}
+
+	if p.RetryJitter {
+		delay = time.Duration(float64(delay) * (0.5 + rand.Float64()/2))
+	}
+	return delay
+}
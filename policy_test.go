@@ -0,0 +1,72 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy Test", func() {
+
+	Context("Retry delay", func() {
+
+		It("must sleep the same duration between retries without backoff", func() {
+			policy := NewPolicy()
+			policy.SleepBetweenRetries = 10 * time.Millisecond
+
+			for retry := 0; retry < 5; retry++ {
+				Expect(policy.retryDelay(retry)).To(Equal(10 * time.Millisecond))
+			}
+
+			policy.SleepBetweenRetries = 0
+			Expect(policy.retryDelay(3)).To(Equal(time.Duration(0)))
+		})
+
+		It("must double the sleep on each retry up to the maximum backoff", func() {
+			policy := NewPolicy()
+			policy.SleepBetweenRetries = 10 * time.Millisecond
+			policy.MaxRetryBackoff = 50 * time.Millisecond
+
+			expected := []time.Duration{10, 20, 40, 50, 50}
+			for retry := range expected {
+				Expect(policy.retryDelay(retry)).To(Equal(expected[retry] * time.Millisecond))
+			}
+		})
+
+		It("must apply the jitter with and without backoff", func() {
+			policy := NewPolicy()
+			policy.SleepBetweenRetries = 10 * time.Millisecond
+			policy.RetryJitter = true
+
+			for _, maxBackoff := range []time.Duration{0, 40 * time.Millisecond} {
+				policy.MaxRetryBackoff = maxBackoff
+
+				delay := 10 * time.Millisecond
+				if maxBackoff > 0 {
+					delay = maxBackoff
+				}
+
+				for i := 0; i < 100; i++ {
+					res := policy.retryDelay(2)
+					Expect(res).To(BeNumerically(">=", delay/2))
+					Expect(res).To(BeNumerically("<", delay))
+				}
+			}
+		})
+	})
+})
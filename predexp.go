@@ -0,0 +1,283 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"strconv"
+
+	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
+)
+
+// predicate expression tags in the wire protocol
+const (
+	_AS_PREDEXP_AND uint16 = 1
+	_AS_PREDEXP_OR  uint16 = 2
+	_AS_PREDEXP_NOT uint16 = 3
+
+	_AS_PREDEXP_INTEGER_VALUE uint16 = 10
+	_AS_PREDEXP_STRING_VALUE  uint16 = 11
+
+	_AS_PREDEXP_INTEGER_BIN uint16 = 100
+	_AS_PREDEXP_STRING_BIN  uint16 = 101
+
+	_AS_PREDEXP_INTEGER_EQUAL     uint16 = 200
+	_AS_PREDEXP_INTEGER_UNEQUAL   uint16 = 201
+	_AS_PREDEXP_INTEGER_GREATER   uint16 = 202
+	_AS_PREDEXP_INTEGER_GREATEREQ uint16 = 203
+	_AS_PREDEXP_INTEGER_LESS      uint16 = 204
+	_AS_PREDEXP_INTEGER_LESSEQ    uint16 = 205
+
+	_AS_PREDEXP_STRING_EQUAL   uint16 = 210
+	_AS_PREDEXP_STRING_UNEQUAL uint16 = 211
+)
+
+// tag(2) + payload length(4)
+const _PREDEXP_HEADER_SIZE = 6
+
+// PredExp represents a predicate expression that the server evaluates
+// on each record selected by a query, in addition to the index filter.
+// Predicate expressions are built in postfix notation: operands are
+// listed before the operator that consumes them.
+//
+// For example, "age > 30 AND status == active" is expressed as:
+//
+//	stmt.SetPredExp(
+//		NewPredExpIntegerBin("age"),
+//		NewPredExpIntegerValue(30),
+//		NewPredExpIntegerGreater(),
+//		NewPredExpStringBin("status"),
+//		NewPredExpStringValue("active"),
+//		NewPredExpStringEqual(),
+//		NewPredExpAnd(2),
+//	)
+type PredExp interface {
+	String() string
+	marshaledSize() int
+	marshal(buf []byte, offset int) int
+}
+
+func marshalPredExpHeader(buf []byte, offset int, tag uint16, size int) int {
+	Buffer.Int16ToBytes(int16(tag), buf, offset)
+	Buffer.Int32ToBytes(int32(size), buf, offset+2)
+	return offset + _PREDEXP_HEADER_SIZE
+}
+
+// ---------------- predExpAnd / predExpOr
+
+type predExpLogical struct {
+	tag   uint16
+	nexpr uint16
+}
+
+// NewPredExpAnd creates an AND predicate over the preceding nexpr expressions.
+func NewPredExpAnd(nexpr uint16) PredExp {
+	return &predExpLogical{tag: _AS_PREDEXP_AND, nexpr: nexpr}
+}
+
+// NewPredExpOr creates an OR predicate over the preceding nexpr expressions.
+func NewPredExpOr(nexpr uint16) PredExp {
+	return &predExpLogical{tag: _AS_PREDEXP_OR, nexpr: nexpr}
+}
+
+func (e *predExpLogical) String() string {
+	if e.tag == _AS_PREDEXP_OR {
+		return "OR(" + strconv.Itoa(int(e.nexpr)) + ")"
+	}
+	return "AND(" + strconv.Itoa(int(e.nexpr)) + ")"
+}
+
+func (e *predExpLogical) marshaledSize() int {
+	return _PREDEXP_HEADER_SIZE + 2
+}
+
+func (e *predExpLogical) marshal(buf []byte, offset int) int {
+	offset = marshalPredExpHeader(buf, offset, e.tag, 2)
+	Buffer.Int16ToBytes(int16(e.nexpr), buf, offset)
+	return offset + 2
+}
+
+// ---------------- predExpNot
+
+type predExpNot struct{}
+
+// NewPredExpNot creates a NOT predicate negating the preceding expression.
+func NewPredExpNot() PredExp {
+	return &predExpNot{}
+}
+
+func (e *predExpNot) String() string {
+	return "NOT"
+}
+
+func (e *predExpNot) marshaledSize() int {
+	return _PREDEXP_HEADER_SIZE
+}
+
+func (e *predExpNot) marshal(buf []byte, offset int) int {
+	return marshalPredExpHeader(buf, offset, _AS_PREDEXP_NOT, 0)
+}
+
+// ---------------- predExpIntegerValue
+
+type predExpIntegerValue struct {
+	val int64
+}
+
+// NewPredExpIntegerValue creates an integer constant operand.
+func NewPredExpIntegerValue(val int64) PredExp {
+	return &predExpIntegerValue{val: val}
+}
+
+func (e *predExpIntegerValue) String() string {
+	return strconv.FormatInt(e.val, 10)
+}
+
+func (e *predExpIntegerValue) marshaledSize() int {
+	return _PREDEXP_HEADER_SIZE + 8
+}
+
+func (e *predExpIntegerValue) marshal(buf []byte, offset int) int {
+	offset = marshalPredExpHeader(buf, offset, _AS_PREDEXP_INTEGER_VALUE, 8)
+	Buffer.Int64ToBytes(e.val, buf, offset)
+	return offset + 8
+}
+
+// ---------------- predExpStringValue
+
+type predExpStringValue struct {
+	val string
+}
+
+// NewPredExpStringValue creates a string constant operand.
+func NewPredExpStringValue(val string) PredExp {
+	return &predExpStringValue{val: val}
+}
+
+func (e *predExpStringValue) String() string {
+	return strconv.Quote(e.val)
+}
+
+func (e *predExpStringValue) marshaledSize() int {
+	return _PREDEXP_HEADER_SIZE + len(e.val)
+}
+
+func (e *predExpStringValue) marshal(buf []byte, offset int) int {
+	offset = marshalPredExpHeader(buf, offset, _AS_PREDEXP_STRING_VALUE, len(e.val))
+	return offset + copy(buf[offset:], e.val)
+}
+
+// ---------------- predExpBin
+
+type predExpBin struct {
+	tag  uint16
+	name string
+}
+
+// NewPredExpIntegerBin creates an operand holding the integer value of the bin.
+func NewPredExpIntegerBin(name string) PredExp {
+	return &predExpBin{tag: _AS_PREDEXP_INTEGER_BIN, name: name}
+}
+
+// NewPredExpStringBin creates an operand holding the string value of the bin.
+func NewPredExpStringBin(name string) PredExp {
+	return &predExpBin{tag: _AS_PREDEXP_STRING_BIN, name: name}
+}
+
+func (e *predExpBin) String() string {
+	return e.name
+}
+
+func (e *predExpBin) marshaledSize() int {
+	return _PREDEXP_HEADER_SIZE + 1 + len(e.name)
+}
+
+func (e *predExpBin) marshal(buf []byte, offset int) int {
+	offset = marshalPredExpHeader(buf, offset, e.tag, 1+len(e.name))
+	buf[offset] = byte(len(e.name))
+	offset++
+	return offset + copy(buf[offset:], e.name)
+}
+
+// ---------------- predExpCompare
+
+type predExpCompare struct {
+	tag uint16
+}
+
+// NewPredExpIntegerEqual creates an integer equality comparison of the two preceding operands.
+func NewPredExpIntegerEqual() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_INTEGER_EQUAL}
+}
+
+// NewPredExpIntegerUnequal creates an integer inequality comparison of the two preceding operands.
+func NewPredExpIntegerUnequal() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_INTEGER_UNEQUAL}
+}
+
+// NewPredExpIntegerGreater creates an integer greater than comparison of the two preceding operands.
+func NewPredExpIntegerGreater() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_INTEGER_GREATER}
+}
+
+// NewPredExpIntegerGreaterEq creates an integer greater than or equal comparison of the two preceding operands.
+func NewPredExpIntegerGreaterEq() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_INTEGER_GREATEREQ}
+}
+
+// NewPredExpIntegerLess creates an integer less than comparison of the two preceding operands.
+func NewPredExpIntegerLess() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_INTEGER_LESS}
+}
+
+// NewPredExpIntegerLessEq creates an integer less than or equal comparison of the two preceding operands.
+func NewPredExpIntegerLessEq() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_INTEGER_LESSEQ}
+}
+
+// NewPredExpStringEqual creates a string equality comparison of the two preceding operands.
+func NewPredExpStringEqual() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_STRING_EQUAL}
+}
+
+// NewPredExpStringUnequal creates a string inequality comparison of the two preceding operands.
+func NewPredExpStringUnequal() PredExp {
+	return &predExpCompare{tag: _AS_PREDEXP_STRING_UNEQUAL}
+}
+
+func (e *predExpCompare) String() string {
+	switch e.tag {
+	case _AS_PREDEXP_INTEGER_EQUAL, _AS_PREDEXP_STRING_EQUAL:
+		return "="
+	case _AS_PREDEXP_INTEGER_UNEQUAL, _AS_PREDEXP_STRING_UNEQUAL:
+		return "!="
+	case _AS_PREDEXP_INTEGER_GREATER:
+		return ">"
+	case _AS_PREDEXP_INTEGER_GREATEREQ:
+		return ">="
+	case _AS_PREDEXP_INTEGER_LESS:
+		return "<"
+	case _AS_PREDEXP_INTEGER_LESSEQ:
+		return "<="
+	}
+	return "?"
+}
+
+func (e *predExpCompare) marshaledSize() int {
+	return _PREDEXP_HEADER_SIZE
+}
+
+func (e *predExpCompare) marshal(buf []byte, offset int) int {
+	return marshalPredExpHeader(buf, offset, e.tag, 0)
+}
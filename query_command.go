@@ -26,6 +26,17 @@ type queryCommand struct {
 	policy    *QueryPolicy
 	statement *Statement
 
+	// scanPolicy, if set, determines the scan options
+	// sent for statements without filters
+	scanPolicy *ScanPolicy
+
+	// partitions to query, the filter to record their progress in,
+	// and the maximum number of records the node returns;
+	// nil partitions query the whole node
+	partitions []int
+	filter     *PartitionFilter
+	maxRecords int64
+
 	// RecordSet recordSet;
 	// Records chan *Record
 	// Errors  chan error
@@ -78,6 +89,11 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 	}
 
 	if len(cmd.statement.Filters) > 0 {
+		if len(cmd.statement.Filters) == 1 && cmd.statement.Filters[0].idxType != ICT_DEFAULT {
+			cmd.dataOffset += int(_FIELD_HEADER_SIZE) + 1
+			fieldCount++
+		}
+
 		cmd.dataOffset += int(_FIELD_HEADER_SIZE)
 		filterSize++ // num filters
 
@@ -97,6 +113,44 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		fieldCount++
 	}
 
+	// queries without filters are scans
+	supportsRecordsPerSecond := cmd.node.supportsQueryRPS
+	if len(cmd.statement.Filters) == 0 {
+		supportsRecordsPerSecond = cmd.node.supportsScanRPS
+	}
+	recordsPerSecond := cmd.recordsPerSecond(cmd.policy.RecordsPerSecond, supportsRecordsPerSecond)
+	if recordsPerSecond > 0 {
+		cmd.dataOffset += 4 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	// partitions with the digest of the last record returned
+	// are resumed after that record
+	var partitionIds []int
+	var digests [][]byte
+	for _, id := range cmd.partitions {
+		if digest := cmd.filter.digest(id); digest != nil {
+			digests = append(digests, digest)
+		} else {
+			partitionIds = append(partitionIds, id)
+		}
+	}
+
+	if len(partitionIds) > 0 {
+		cmd.dataOffset += len(partitionIds)*2 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if len(digests) > 0 {
+		cmd.dataOffset += len(digests)*20 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
+	if cmd.maxRecords > 0 {
+		cmd.dataOffset += 8 + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
 	if len(cmd.statement.BinNames) > 0 {
 		cmd.dataOffset += int(_FIELD_HEADER_SIZE)
 		binNameSize++ // num bin names
@@ -108,6 +162,15 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		fieldCount++
 	}
 
+	predExpSize := 0
+	if len(cmd.statement.predExps) > 0 {
+		for _, predExp := range cmd.statement.predExps {
+			predExpSize += predExp.marshaledSize()
+		}
+		cmd.dataOffset += predExpSize + int(_FIELD_HEADER_SIZE)
+		fieldCount++
+	}
+
 	if cmd.statement.TaskId == 0 {
 		cmd.statement.TaskId = time.Now().UnixNano()
 	}
@@ -151,6 +214,12 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 	}
 
 	if len(cmd.statement.Filters) > 0 {
+		if len(cmd.statement.Filters) == 1 && cmd.statement.Filters[0].idxType != ICT_DEFAULT {
+			cmd.writeFieldHeader(1, INDEX_TYPE)
+			cmd.dataBuffer[cmd.dataOffset] = byte(cmd.statement.Filters[0].idxType)
+			cmd.dataOffset++
+		}
+
 		cmd.writeFieldHeader(filterSize, INDEX_RANGE)
 		cmd.dataBuffer[cmd.dataOffset] = byte(len(cmd.statement.Filters))
 		cmd.dataOffset++
@@ -165,13 +234,49 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		// Calling query with no filters is more efficiently handled by a primary index scan.
 		cmd.writeFieldHeader(2, SCAN_OPTIONS)
 		priority := byte(cmd.policy.Priority)
+		scanPercent := 100
+		if cmd.scanPolicy != nil {
+			priority = byte(cmd.scanPolicy.Priority)
+			scanPercent = cmd.scanPolicy.ScanPercent
+		}
 		priority <<= 4
+
+		if cmd.scanPolicy != nil && cmd.scanPolicy.FailOnClusterChange {
+			priority |= 0x08
+		}
 		cmd.dataBuffer[cmd.dataOffset] = priority
 		cmd.dataOffset++
-		cmd.dataBuffer[cmd.dataOffset] = byte(100)
+		cmd.dataBuffer[cmd.dataOffset] = byte(scanPercent)
 		cmd.dataOffset++
 	}
 
+	if recordsPerSecond > 0 {
+		cmd.writeFieldInt32(int32(recordsPerSecond), RECORDS_PER_SECOND)
+	}
+
+	if len(partitionIds) > 0 {
+		cmd.writeFieldHeader(len(partitionIds)*2, PID_ARRAY)
+		// partition ids are sent in little endian order
+		for _, id := range partitionIds {
+			cmd.dataBuffer[cmd.dataOffset] = byte(id)
+			cmd.dataBuffer[cmd.dataOffset+1] = byte(id >> 8)
+			cmd.dataOffset += 2
+		}
+	}
+
+	if len(digests) > 0 {
+		cmd.writeFieldHeader(len(digests)*20, DIGEST_ARRAY)
+		for _, digest := range digests {
+			cmd.dataOffset += copy(cmd.dataBuffer[cmd.dataOffset:], digest)
+		}
+	}
+
+	if cmd.maxRecords > 0 {
+		cmd.writeFieldHeader(8, MAX_RECORDS)
+		Buffer.Int64ToBytes(cmd.maxRecords, cmd.dataBuffer, cmd.dataOffset)
+		cmd.dataOffset += 8
+	}
+
 	if len(cmd.statement.BinNames) > 0 {
 		cmd.writeFieldHeader(binNameSize, QUERY_BINLIST)
 		cmd.dataBuffer[cmd.dataOffset] = byte(len(cmd.statement.BinNames))
@@ -184,6 +289,13 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 		}
 	}
 
+	if len(cmd.statement.predExps) > 0 {
+		cmd.writeFieldHeader(predExpSize, PREDEXP)
+		for _, predExp := range cmd.statement.predExps {
+			cmd.dataOffset = predExp.marshal(cmd.dataBuffer, cmd.dataOffset)
+		}
+	}
+
 	cmd.writeFieldHeader(8, TRAN_ID)
 	Buffer.Int64ToBytes(int64(cmd.statement.TaskId), cmd.dataBuffer, cmd.dataOffset)
 	cmd.dataOffset += 8
@@ -206,11 +318,3 @@ func (cmd *queryCommand) writeBuffer(ifc command) (err error) {
 
 	return nil
 }
-
-func (cmd *queryCommand) parseResult(ifc command, conn *Connection) error {
-	// close the channel
-	defer close(cmd.Records)
-	defer close(cmd.Errors)
-
-	return cmd.baseMultiCommand.parseResult(ifc, conn)
-}
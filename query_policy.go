@@ -17,6 +17,19 @@ package aerospike
 // QueryPolicy encapsulates parameters for policy attributes used in query operations.
 type QueryPolicy struct {
 	*MultiPolicy
+
+	// FailOnPredExpUnsupported determines if a query with predicate expressions
+	// fails before being sent when any of the nodes does not support them.
+	FailOnPredExpUnsupported bool //= false
+
+	// MaxRecords determines the maximum number of records the query returns.
+	// If set, the query returns a page of at most MaxRecords records, and the
+	// query can be resumed after them with Recordset.ContinuationToken and
+	// Statement.SetContinuation.
+	// Paginated queries are sent to the nodes by partition, and require servers
+	// which support partition queries.
+	// Default is 0, which returns all the records.
+	MaxRecords int64 //= 0
 }
 
 // NewQueryPolicy generates a new QueryPolicy instance with default values.
@@ -17,6 +17,7 @@ package aerospike
 import (
 
 	// . "github.com/aerospike/aerospike-client-go/logger"
+	"fmt"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go/types"
@@ -43,6 +44,17 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 			return false, err
 		}
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
+		info3 := int(cmd.dataBuffer[3])
+
+		// Partition queries report each completed partition with the
+		// partition id in the generation field, without key or bins.
+		// Partitions with an error are not marked as completed.
+		if cmd.filter != nil && (info3&_INFO3_PARTITION_DONE) == _INFO3_PARTITION_DONE {
+			if resultCode == 0 {
+				cmd.filter.markDone(int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 6))))
+			}
+			continue
+		}
 
 		if resultCode != 0 {
 			if resultCode == KEY_NOT_FOUND_ERROR {
@@ -53,15 +65,13 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 			return false, err
 		}
 
-		info3 := int(cmd.dataBuffer[3])
-
 		// If cmd is the end marker of the response, do not proceed further
 		if (info3 & _INFO3_LAST) == _INFO3_LAST {
 			return false, nil
 		}
 
 		generation := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 6)))
-		expiration := TTL(int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 10))))
+		expiration := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 10)))
 		fieldCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 18)))
 		opCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 20)))
 
@@ -111,6 +121,15 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 			return false, NewAerospikeError(QUERY_TERMINATED)
 		}
 
+		// aggregation errors are sent back in a FAILURE bin
+		if cmd.statement.functionName != "" && cmd.statement.returnData {
+			if failure, exists := bins["FAILURE"]; exists {
+				err := NewAerospikeError(UDF_BAD_RESPONSE, fmt.Sprintf("%v", failure))
+				cmd.Errors <- newNodeError(cmd.node, err)
+				continue
+			}
+		}
+
 		// If the channel is full and it blocks, we don't want this command to
 		// block forever, or panic in case the channel is closed in the meantime.
 	L:
@@ -118,6 +137,10 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 			select {
 			// send back the result on the async channel
 			case cmd.Records <- newRecord(cmd.node, key, bins, nil, generation, expiration):
+				// the query resumes after the last record returned from the partition
+				if cmd.filter != nil {
+					cmd.filter.setDigest(key)
+				}
 				break L
 			case <-time.After(time.Millisecond):
 				if !cmd.IsValid() {
@@ -132,5 +155,12 @@ func (cmd *queryRecordCommand) parseRecordResults(ifc command, receiveSize int)
 }
 
 func (cmd *queryRecordCommand) Execute() error {
-	return cmd.execute(cmd)
+	// close the channels, even if the command was aborted
+	// before the results were parsed
+	defer close(cmd.Records)
+	defer close(cmd.Errors)
+
+	err := cmd.execute(cmd)
+	cmd.reportTotalTimeout(err)
+	return err
 }
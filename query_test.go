@@ -15,12 +15,15 @@
 package aerospike_test
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"math"
 	"math/rand"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -41,6 +44,41 @@ function filter_by_name(stream,name)
  return stream : filter(filter_name) : map(map_profile)
 end`
 
+const udfCount = `
+local function one(record)
+ return 1
+end
+
+local function add(a, b)
+ return a + b
+end
+
+function count(stream)
+ return stream : map(one) : reduce(add)
+end`
+
+const udfGroupCount = `
+local function one(record)
+ return 1
+end
+
+local function add(a, b)
+ return a + b
+end
+
+local function group(count)
+ local res = map()
+ res["group"] = "all"
+ res["count"] = count
+ local l = list()
+ list.append(l, res)
+ return l
+end
+
+function count(stream)
+ return stream : map(one) : reduce(add) : map(group)
+end`
+
 // ALL tests are isolated by SetName and Key, which are 50 random charachters
 var _ = Describe("Query operations", func() {
 	rand.Seed(time.Now().UnixNano())
@@ -127,6 +165,18 @@ var _ = Describe("Query operations", func() {
 		Expect(len(keys)).To(Equal(0))
 	})
 
+	It("must Query each node separately and get all records back", func() {
+		for _, node := range client.GetNodes() {
+			stm := NewStatement(ns, set)
+			recordset, err := client.QueryNode(nil, node, stm)
+			Expect(err).ToNot(HaveOccurred())
+
+			checkResults(recordset, 0)
+		}
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
 	It("must Cancel Query abruptly", func() {
 		stm := NewStatement(ns, set)
 		recordset, err := client.Query(nil, stm)
@@ -137,6 +187,57 @@ var _ = Describe("Query operations", func() {
 		Expect(len(keys)).To(BeNumerically("<=", keyCount/2))
 	})
 
+	It("must Query a range in pages with continuation tokens", func() {
+		policy := NewQueryPolicy()
+		policy.MaxRecords = 30
+
+		token := ""
+		pages := 0
+		for {
+			stm := NewStatement(ns, set)
+			stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16))
+			Expect(stm.SetContinuation(token)).ToNot(HaveOccurred())
+
+			recordset, err := client.Query(policy, stm)
+			Expect(err).ToNot(HaveOccurred())
+
+			cnt := 0
+			for rec := range recordset.Records {
+				_, exists := keys[string(rec.Key.Digest())]
+				Expect(exists).To(BeTrue())
+				delete(keys, string(rec.Key.Digest()))
+				cnt++
+			}
+			Expect(cnt).To(BeNumerically("<=", policy.MaxRecords))
+
+			token, err = recordset.ContinuationToken()
+			Expect(err).ToNot(HaveOccurred())
+
+			pages++
+			if token == "" {
+				break
+			}
+		}
+
+		Expect(pages).To(BeNumerically(">=", keyCount/30))
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must not return a continuation token for queries which are not paginated", func() {
+		recordset, err := client.Query(nil, NewStatement(ns, set))
+		Expect(err).ToNot(HaveOccurred())
+		for range recordset.Records {
+		}
+
+		_, err = recordset.ContinuationToken()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("must reject invalid continuation tokens", func() {
+		stm := NewStatement(ns, set)
+		Expect(stm.SetContinuation("invalid token")).To(HaveOccurred())
+	})
+
 	It("must Query a specific range and get only relevant records back", func() {
 		stm := NewStatement(ns, set)
 		stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16/2))
@@ -163,6 +264,103 @@ var _ = Describe("Query operations", func() {
 		Expect(cnt).To(BeNumerically(">", 0))
 	})
 
+	It("must Query a range and apply predicate expressions on other bins", func() {
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16))
+		stm.SetPredExp(
+			NewPredExpIntegerBin(bin1.Name),
+			NewPredExpIntegerValue(int64(bin1.Value.GetObject().(int))),
+			NewPredExpIntegerEqual(),
+			NewPredExpStringBin(bin4.Name),
+			NewPredExpStringValue("constValue"),
+			NewPredExpStringEqual(),
+			NewPredExpAnd(2),
+		)
+
+		qpolicy := NewQueryPolicy()
+		qpolicy.FailOnPredExpUnsupported = true
+		recordset, err := client.Query(qpolicy, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Query points within a GeoJSON region and radius", func() {
+		const geoBin = "location"
+		idxTask, err := client.CreateIndex(wpolicy, ns, set, set+geoBin, geoBin, GEO2DSPHERE)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+
+		points := map[string]string{}
+		for i := 0; i < 10; i++ {
+			key, err := NewKey(ns, set, randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			point := fmt.Sprintf(`{ "type": "Point", "coordinates": [%f, %f] }`, -122.0+0.001*float64(i), 37.5)
+			points[string(key.Digest())] = point
+			err = client.PutBins(wpolicy, key, NewBin(geoBin, NewGeoJSONValue(point)))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		region := `{ "type": "Polygon", "coordinates": [[[-122.5, 37.0], [-121.0, 37.0], [-121.0, 38.0], [-122.5, 38.0], [-122.5, 37.0]]] }`
+		for _, filter := range []*Filter{
+			NewGeoWithinRegionFilter(geoBin, region),
+			NewGeoWithinRadiusFilter(geoBin, 37.5, -122.0, 5000),
+		} {
+			stm := NewStatement(ns, set, geoBin)
+			stm.Addfilter(filter)
+			recordset, err := client.Query(nil, stm)
+			Expect(err).ToNot(HaveOccurred())
+
+			cnt := 0
+			for rec := range recordset.Records {
+				Expect(rec.Bins[geoBin]).To(Equal(points[string(rec.Key.Digest())]))
+				cnt++
+			}
+			Expect(cnt).To(Equal(len(points)))
+		}
+	})
+
+	It("must Query records whose list bin contains a value using a collection index", func() {
+		const listBin = "listBin"
+		idxTask, err := client.CreateComplexIndex(wpolicy, ns, set, set+listBin, listBin, NUMERIC, ICT_LIST)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
+
+		for i := 0; i < 10; i++ {
+			key, err := NewKey(ns, set, randString(50))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.PutBins(wpolicy, key, NewBin(listBin, []interface{}{i, i + 100}))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		stm := NewStatement(ns, set, listBin)
+		stm.Addfilter(NewContainsFilter(listBin, ICT_LIST, 105))
+		recordset, err := client.Query(nil, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt := 0
+		for rec := range recordset.Records {
+			Expect(rec.Bins[listBin]).To(Equal([]interface{}{5, 105}))
+			cnt++
+		}
+		Expect(cnt).To(Equal(1))
+
+		stm = NewStatement(ns, set, listBin)
+		stm.Addfilter(NewContainsRangeFilter(listBin, ICT_LIST, 100, 104))
+		recordset, err = client.Query(nil, stm)
+		Expect(err).ToNot(HaveOccurred())
+
+		cnt = 0
+		for range recordset.Records {
+			cnt++
+		}
+		Expect(cnt).To(Equal(5))
+	})
+
 	It("must Query a specific range by applying a udf filter and get only relevant records back", func() {
 		regTask, err := client.RegisterUDF(nil, []byte(udfFilter), "udfFilter.lua", LUA)
 		Expect(err).ToNot(HaveOccurred())
@@ -189,6 +387,107 @@ var _ = Describe("Query operations", func() {
 		Expect(cnt).To(BeNumerically(">", 0))
 	})
 
+	It("must Query a range and return the results reduced on each node with QueryAggregate", func() {
+		regTask, err := client.RegisterUDF(nil, []byte(udfCount), "udfCount.lua", LUA)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-regTask.OnComplete()).ToNot(HaveOccurred())
+
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16))
+		resultSet, err := client.QueryAggregate(nil, stm, "udfCount", "count")
+		Expect(err).ToNot(HaveOccurred())
+
+		// sum up the counts of all nodes
+		total := 0
+		for res := range resultSet.Results {
+			total += res.(int)
+		}
+		Expect(total).To(Equal(keyCount))
+	})
+
+	It("must decode the results of each node with QueryAggregateInto", func() {
+		regTask, err := client.RegisterUDF(nil, []byte(udfGroupCount), "udfGroupCount.lua", LUA)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-regTask.OnComplete()).ToNot(HaveOccurred())
+
+		type groupCount struct {
+			Group string `as:"group"`
+			Count int    `as:"count"`
+		}
+
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16))
+		resChan := make(chan *groupCount, 100)
+		errChan, err := client.QueryAggregateInto(nil, stm, resChan, "udfGroupCount", "count")
+		Expect(err).ToNot(HaveOccurred())
+
+		// each node returns a list with a single group
+		total := 0
+		for resChan != nil || errChan != nil {
+			select {
+			case res, open := <-resChan:
+				if !open {
+					resChan = nil
+					continue
+				}
+				Expect(res.Group).To(Equal("all"))
+				total += res.Count
+
+			case err, open := <-errChan:
+				if !open {
+					errChan = nil
+					continue
+				}
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+		Expect(total).To(Equal(keyCount))
+	})
+
+	It("must stop QueryAggregateIntoWithContext once its context is cancelled", func() {
+		regTask, err := client.RegisterUDF(nil, []byte(udfGroupCount), "udfGroupCount.lua", LUA)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(<-regTask.OnComplete()).ToNot(HaveOccurred())
+
+		type groupCount struct {
+			Group string `as:"group"`
+			Count int    `as:"count"`
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16))
+
+		// the results are never consumed
+		resChan := make(chan *groupCount)
+		errChan, err := client.QueryAggregateIntoWithContext(ctx, nil, stm, resChan, "udfGroupCount", "count")
+		Expect(err).ToNot(HaveOccurred())
+		cancel()
+
+		var cancelErr error
+		Eventually(errChan).Should(Receive(&cancelErr))
+		Expect(cancelErr.(AerospikeError).ResultCode()).To(Equal(COMMAND_CANCELLED))
+		Eventually(errChan).Should(BeClosed())
+		Eventually(resChan).Should(BeClosed())
+	})
+
+	It("must register an inline module and run its aggregation with QueryAggregateInline", func() {
+		stm := NewStatement(ns, set)
+		stm.Addfilter(NewRangeFilter(bin3.Name, 0, math.MaxInt16))
+
+		// the second call finds the module already registered
+		for i := 0; i < 2; i++ {
+			resultSet, err := client.QueryAggregateInline(nil, stm, []byte(udfCount), "udfCountInline", "count")
+			Expect(err).ToNot(HaveOccurred())
+
+			total := 0
+			for res := range resultSet.Results {
+				total += res.(int)
+			}
+			Expect(total).To(Equal(keyCount))
+		}
+	})
+
 	It("must Query specific equality filters and get only relevant records back", func() {
 		// save a record with requested value
 		key, err := NewKey(ns, set, randString(50))
@@ -18,6 +18,7 @@ import (
 	. "github.com/aerospike/aerospike-client-go/logger"
 
 	. "github.com/aerospike/aerospike-client-go/types"
+	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
 	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
 )
 
@@ -27,6 +28,20 @@ type readCommand struct {
 	policy   Policy
 	binNames []string
 	record   *Record
+
+	// collect multiple results for the same bin into OpResults,
+	// instead of keeping the last one
+	opResults bool
+
+	// collect the results of all operations in the order they were received
+	collectOpValues bool
+	opValues        []interface{}
+
+	// names of the bins whose map results are returned as ordered key/value pairs
+	keyValuePairBins map[string]bool
+
+	// names of the bins whose integer results are returned as int64
+	int64Bins map[string]bool
 }
 
 func newReadCommand(cluster *Cluster, policy Policy, key *Key, binNames []string) *readCommand {
@@ -48,6 +63,10 @@ func (cmd *readCommand) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *readCommand) getNode(ifc command) (*Node, error) {
+	return cmd.getReadNode(cmd.policy)
+}
+
 func (cmd *readCommand) writeBuffer(ifc command) error {
 	return cmd.setRead(cmd.key, cmd.binNames)
 }
@@ -67,7 +86,7 @@ func (cmd *readCommand) parseResult(ifc command, conn *Connection) error {
 	headerLength := int(cmd.dataBuffer[8])
 	resultCode := ResultCode(cmd.dataBuffer[13] & 0xFF)
 	generation := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 14)))
-	expiration := TTL(int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 18))))
+	expiration := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 18)))
 	fieldCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 26))) // almost certainly 0
 	opCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 28)))
 	receiveSize := int((sz & 0xFFFFFFFFFFFF) - int64(headerLength))
@@ -133,6 +152,10 @@ func (cmd *readCommand) parseRecord(
 	var duplicates []BinMap
 	receiveOffset := 0
 
+	if cmd.collectOpValues {
+		cmd.opValues = make([]interface{}, 0, opCount)
+	}
+
 	// There can be fields in the response (setname etc).
 	// But for now, ignore them. Expose them to the API if needed in the future.
 	// Logger.Debug("field count: %d, databuffer: %v", fieldCount, cmd.dataBuffer)
@@ -154,9 +177,21 @@ func (cmd *readCommand) parseRecord(
 		receiveOffset += 4 + 4 + nameSize
 
 		particleBytesSize := int(opSize - (4 + nameSize))
-		value, _ := bytesToParticle(particleType, cmd.dataBuffer, receiveOffset, particleBytesSize)
+		var value interface{}
+		if particleType == ParticleType.MAP && cmd.keyValuePairBins[name] {
+			value, _ = newUnpacker(cmd.dataBuffer, receiveOffset, particleBytesSize).UnpackMapPairs()
+		} else {
+			value, _ = bytesToParticle(particleType, cmd.dataBuffer, receiveOffset, particleBytesSize)
+		}
+		if v, ok := value.(int); ok && cmd.int64Bins[name] {
+			value = int64(v)
+		}
 		receiveOffset += particleBytesSize
 
+		if cmd.collectOpValues {
+			cmd.opValues = append(cmd.opValues, value)
+		}
+
 		var vmap BinMap
 
 		if version > 0 || duplicates != nil {
@@ -185,7 +220,16 @@ func (cmd *readCommand) parseRecord(
 			}
 			vmap = bins
 		}
-		vmap[name] = value
+
+		if prev, exists := vmap[name]; exists && cmd.opResults {
+			if res, ok := prev.(OpResults); ok {
+				vmap[name] = append(res, value)
+			} else {
+				vmap[name] = OpResults{prev, value}
+			}
+		} else {
+			vmap[name] = value
+		}
 	}
 
 	// Remove nil duplicates just in case there were holes in the version number space.
@@ -44,6 +44,10 @@ func (cmd *readHeaderCommand) getPolicy(ifc command) Policy {
 	return cmd.policy
 }
 
+func (cmd *readHeaderCommand) getNode(ifc command) (*Node, error) {
+	return cmd.getReadNode(cmd.policy)
+}
+
 func (cmd *readHeaderCommand) writeBuffer(ifc command) error {
 	return cmd.setReadHeader(cmd.key)
 }
@@ -58,7 +62,7 @@ func (cmd *readHeaderCommand) parseResult(ifc command, conn *Connection) error {
 
 	if resultCode == 0 {
 		generation := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 14)))
-		expiration := TTL(int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 18))))
+		expiration := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 18)))
 		cmd.record = newRecord(cmd.node, cmd.key, nil, nil, generation, expiration)
 	} else {
 		if ResultCode(resultCode) == KEY_NOT_FOUND_ERROR {
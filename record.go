@@ -16,6 +16,11 @@ package aerospike
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
 )
 
 // Record is the container struct for database records.
@@ -42,10 +47,16 @@ type Record struct {
 	Generation int
 
 	// Expiration is TTL (Time-To-Live).
-	// Number of seconds until record expires.
+	// Number of seconds until record expires, at the time it was read.
+	// Use ExpirationTime for records which never expire.
 	Expiration int
+
+	// voidTime is the expiration sent by the server, in seconds
+	// from the citrusleaf epoch. Zero means the record never expires.
+	voidTime int
 }
 
+// newRecord creates a record; expiration is the void time sent by the server.
 func newRecord(node *Node, key *Key, bins BinMap, duplicates []BinMap, generation int, expiration int) *Record {
 	r := &Record{
 		Node:       node,
@@ -53,7 +64,8 @@ func newRecord(node *Node, key *Key, bins BinMap, duplicates []BinMap, generatio
 		Bins:       bins,
 		Duplicates: duplicates,
 		Generation: generation,
-		Expiration: expiration,
+		Expiration: TTL(expiration),
+		voidTime:   expiration,
 	}
 
 	// always assign a map of length zero if Bins is nil
@@ -64,8 +76,115 @@ func newRecord(node *Node, key *Key, bins BinMap, duplicates []BinMap, generatio
 	return r
 }
 
+// ExpirationTime returns the time the record expires.
+// It returns the zero time if the record never expires.
+func (rc *Record) ExpirationTime() time.Time {
+	if rc.voidTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(CITRUSLEAF_EPOCH+int64(rc.voidTime), 0)
+}
+
+// TimeToLive returns the time remaining until the record expires,
+// or zero if it has already expired.
+// The bool result is false if the record never expires.
+func (rc *Record) TimeToLive() (time.Duration, bool) {
+	expiration := rc.ExpirationTime()
+	if expiration.IsZero() {
+		return 0, false
+	}
+
+	if ttl := expiration.Sub(time.Now()); ttl > 0 {
+		return ttl, true
+	}
+	return 0, true
+}
+
 // String implements the Stringer interface.
 // Returns string representation of record.
 func (rc *Record) String() string {
 	return fmt.Sprintf("%v %v", *rc.Key, rc.Bins)
 }
+
+// GetInt returns the value of an integer bin as int64, whatever integer type
+// it was returned as. The bool result is false if the bin does not exist
+// or does not hold an integer.
+func (rc *Record) GetInt(binName string) (int64, bool) {
+	switch v := rc.Bins[binName].(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint:
+		if uint64(v) <= math.MaxInt64 {
+			return int64(v), true
+		}
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// GetString returns the value of a string bin.
+// The bool result is false if the bin does not exist or does not hold a string.
+func (rc *Record) GetString(binName string) (string, bool) {
+	v, ok := rc.Bins[binName].(string)
+	return v, ok
+}
+
+// GetFloat returns the value of a floating point bin as float64.
+// The bool result is false if the bin does not exist or does not hold a float.
+func (rc *Record) GetFloat(binName string) (float64, bool) {
+	switch v := rc.Bins[binName].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// GetList returns the value of a list bin.
+// The bool result is false if the bin does not exist or does not hold a list.
+func (rc *Record) GetList(binName string) ([]interface{}, bool) {
+	v, ok := rc.Bins[binName].([]interface{})
+	return v, ok
+}
+
+// GetIP returns the value of a blob bin holding an IPv4 or IPv6 address,
+// as stored from a net.IP value.
+// The bool result is false if the bin does not exist or does not hold an address.
+func (rc *Record) GetIP(binName string) (net.IP, bool) {
+	v, ok := rc.Bins[binName].([]byte)
+	if !ok || (len(v) != net.IPv4len && len(v) != net.IPv6len) {
+		return nil, false
+	}
+	return net.IP(v), true
+}
+
+// GetUUID returns the value of a 16 byte blob bin, as stored from a [16]byte
+// array or a UUID type based on it.
+// The bool result is false if the bin does not exist or does not hold 16 bytes.
+func (rc *Record) GetUUID(binName string) ([16]byte, bool) {
+	var res [16]byte
+	v, ok := rc.Bins[binName].([]byte)
+	if !ok || len(v) != len(res) {
+		return res, false
+	}
+	copy(res[:], v)
+	return res, true
+}
@@ -15,6 +15,10 @@
 package aerospike
 
 import (
+	"context"
+	"time"
+
+	. "github.com/aerospike/aerospike-client-go/types"
 	. "github.com/aerospike/aerospike-client-go/types/atomic"
 )
 
@@ -29,6 +33,12 @@ type Recordset struct {
 	chans    []chan *Record
 	errs     []chan error
 	commands []multiCommand
+
+	// cancels the total timeout of the scan or query
+	cancel context.CancelFunc
+
+	// partitions of a paginated query, and their progress
+	filter *PartitionFilter
 }
 
 // NewRecordset generates a new RecordSet instance.
@@ -50,12 +60,63 @@ func (rcs *Recordset) IsActive() bool {
 func (rcs *Recordset) Close() {
 	rcs.active.Set(false)
 
+	if rcs.cancel != nil {
+		rcs.cancel()
+	}
+
 	for i := range rcs.commands {
 		// send signal to close
 		rcs.commands[i].Stop()
 	}
 }
 
+// ContinuationToken returns an opaque token to resume a paginated query after the
+// records it has returned. Pass the token to Statement.SetContinuation to query the
+// next page. An empty token is returned once all the records have been returned.
+// The token must be retrieved after all the records have been read from the
+// Records channel, and the channel has been closed.
+// Only queries with QueryPolicy.MaxRecords set, or resumed with a continuation,
+// return a token.
+func (rcs *Recordset) ContinuationToken() (string, error) {
+	if rcs.filter == nil {
+		return "", NewAerospikeError(PARAMETER_ERROR, "Continuation tokens are only returned by paginated queries")
+	}
+
+	if rcs.filter.isComplete() {
+		return "", nil
+	}
+	return rcs.filter.Remaining().token(), nil
+}
+
+// totalTimeoutContext returns a context which expires after the total timeout
+// of the scan or query, and closes the recordset once it does.
+// Returns nil if there is no total timeout.
+func (rcs *Recordset) totalTimeoutContext(timeout time.Duration) context.Context {
+	if timeout <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	rcs.cancel = cancel
+
+	go func() {
+		<-ctx.Done()
+		if ctx.Err() == context.DeadlineExceeded {
+			rcs.Close()
+		}
+	}()
+
+	return ctx
+}
+
+// releaseTotalTimeout stops the timer of the total timeout, and the goroutine
+// waiting for it, once the scan or query has finished.
+func (rcs *Recordset) releaseTotalTimeout() {
+	if rcs.cancel != nil {
+		rcs.cancel()
+	}
+}
+
 // drains a records channel into the results chan
 func (rcs *Recordset) drainRecords(recChan chan *Record) {
 	// drain the results chan
@@ -72,3 +133,28 @@ func (rcs *Recordset) drainErrors(errChan chan error) {
 		rcs.Errors <- err
 	}
 }
+
+// Partition fans the Records channel out into n channels, so that records can
+// be processed by n goroutines in parallel. Each record is delivered to exactly
+// one of the returned channels. All channels are closed when the Records channel
+// is closed. Errors are still sent on the Errors channel.
+// Records must not be read directly after calling Partition.
+func (rcs *Recordset) Partition(n int) []<-chan *Record {
+	if n < 1 {
+		n = 1
+	}
+
+	res := make([]<-chan *Record, n)
+	for i := range res {
+		out := make(chan *Record)
+		res[i] = out
+
+		go func(out chan *Record) {
+			for rec := range rcs.Records {
+				out <- rec
+			}
+			close(out)
+		}(out)
+	}
+	return res
+}
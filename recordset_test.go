@@ -0,0 +1,60 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recordset Test", func() {
+
+	Context("Total timeout", func() {
+
+		It("must release the total timeout once the merged channels are closed", func() {
+			rs := NewRecordset(1)
+			ctx := rs.totalTimeoutContext(time.Hour)
+
+			recChan, errChan := make(chan *Record, 1), make(chan error, 1)
+			rs.Records, rs.Errors = (&Client{}).mergeResultChannels(1, []chan *Record{recChan}, []chan error{errChan}, rs.releaseTotalTimeout)
+
+			recChan <- &Record{}
+			close(recChan)
+			close(errChan)
+			Expect(ctx.Err()).ToNot(HaveOccurred())
+
+			for range rs.Records {
+			}
+			Expect(ctx.Err()).To(Equal(context.Canceled))
+
+			// the recordset is not closed, since the timeout has not elapsed
+			Expect(rs.IsActive()).To(BeTrue())
+		})
+
+		It("must close the recordset once the total timeout has elapsed", func() {
+			rs := NewRecordset(1)
+			ctx := rs.totalTimeoutContext(time.Millisecond)
+
+			<-ctx.Done()
+			Expect(ctx.Err()).To(Equal(context.DeadlineExceeded))
+			Eventually(rs.IsActive).Should(BeFalse())
+		})
+
+	})
+
+})
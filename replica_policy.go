@@ -0,0 +1,73 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+// ReplicaPolicy determines the node to send single record read commands to.
+// Write commands are always sent to the node holding the master partition.
+type ReplicaPolicy int
+
+const (
+	// MASTER reads from the node containing the key's master partition.
+	// This is the default.
+	MASTER ReplicaPolicy = iota
+
+	// MASTER_PROLES distributes reads across the nodes containing the key's master
+	// and replicated partitions in round-robin fashion. Reads go to the master
+	// node if the replica node is down.
+	MASTER_PROLES
+
+	// RANDOM distributes reads across all nodes in the cluster.
+	// Useful when the replication factor equals the number of nodes.
+	RANDOM
+
+	// PREFER_RACK reads from the node containing the key's master or replicated
+	// partition which is on the same rack as the client, as set by
+	// ClientPolicy.RackId. If no such node is available, reads go to any replica,
+	// as with MASTER_PROLES. Requires ClientPolicy.RackAware to be set.
+	PREFER_RACK
+)
+
+// ReadModeAP determines how many replicas to consult for a read
+// in AP (availability) mode namespaces.
+type ReadModeAP int
+
+const (
+	// ReadModeAPOne involves a single node in the read. This is the default.
+	ReadModeAPOne ReadModeAP = iota
+
+	// ReadModeAPAll involves all the duplicates of the record in the read.
+	ReadModeAPAll
+)
+
+// ReadModeSC determines the read consistency guarantee
+// in SC (strong consistency) mode namespaces.
+type ReadModeSC int
+
+const (
+	// ReadModeSCSession ensures this client will only see an increasing sequence
+	// of record versions. This is the default.
+	ReadModeSCSession ReadModeSC = iota
+
+	// ReadModeSCLinearize ensures all clients will only see an increasing
+	// sequence of record versions.
+	ReadModeSCLinearize
+
+	// ReadModeSCAllowReplica allows reads from the master or any replica.
+	ReadModeSCAllowReplica
+
+	// ReadModeSCAllowUnavailable allows reads from the master, any replica,
+	// or an unavailable partition.
+	ReadModeSCAllowUnavailable
+)
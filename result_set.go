@@ -0,0 +1,73 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	"sync"
+)
+
+// ResultSet encapsulates the results of aggregation queries.
+type ResultSet struct {
+	// Results is a channel on which the value reduced by the aggregation
+	// on each node will be sent back.
+	Results chan interface{}
+	// Errors is a channel on which all errors will be sent back.
+	Errors chan error
+
+	recordset *Recordset
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// newResultSet returns a ResultSet which sends the values of the aggregation
+// records of recordset on its Results channel.
+func newResultSet(recordset *Recordset) *ResultSet {
+	rs := &ResultSet{
+		Results:   make(chan interface{}, cap(recordset.Records)),
+		Errors:    recordset.Errors,
+		recordset: recordset,
+		closed:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(rs.Results)
+
+		for rec := range recordset.Records {
+			select {
+			case rs.Results <- rec.Bins["SUCCESS"]:
+			case <-rs.closed:
+				// discard the records the nodes have sent already
+				for range recordset.Records {
+				}
+				return
+			}
+		}
+	}()
+
+	return rs
+}
+
+// IsActive returns true if the aggregation hasn't been finished or cancelled.
+func (rs *ResultSet) IsActive() bool {
+	return rs.recordset.IsActive()
+}
+
+// Close stops the aggregation on all the nodes.
+func (rs *ResultSet) Close() {
+	rs.closeOnce.Do(func() {
+		close(rs.closed)
+	})
+	rs.recordset.Close()
+}
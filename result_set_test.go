@@ -0,0 +1,51 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospike
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResultSet Test", func() {
+
+	It("must send the value reduced on each node", func() {
+		recordset := NewRecordset(2)
+		rs := newResultSet(recordset)
+
+		recordset.Records <- &Record{Bins: BinMap{"SUCCESS": 3}}
+		recordset.Records <- &Record{Bins: BinMap{"SUCCESS": 4}}
+		close(recordset.Records)
+
+		results := []interface{}{}
+		for res := range rs.Results {
+			results = append(results, res)
+		}
+		Expect(results).To(Equal([]interface{}{3, 4}))
+	})
+
+	It("must stop sending values once it is closed", func() {
+		recordset := NewRecordset(0)
+		rs := newResultSet(recordset)
+		rs.Close()
+		Expect(rs.IsActive()).To(BeFalse())
+
+		// the records sent after closing are discarded
+		recordset.Records <- &Record{Bins: BinMap{"SUCCESS": 3}}
+		close(recordset.Records)
+		Eventually(rs.Results).Should(BeClosed())
+	})
+
+})
@@ -30,6 +30,11 @@ type scanCommand struct {
 	// Records   chan *Record
 	// Errors    chan error
 	binNames []string
+
+	// partitions to scan, and the filter to record
+	// completed partitions in; nil to scan the whole node
+	partitions []int
+	filter     *PartitionFilter
 }
 
 func newScanCommand(
@@ -66,7 +71,7 @@ func (cmd *scanCommand) getPolicy(ifc command) Policy {
 }
 
 func (cmd *scanCommand) writeBuffer(ifc command) error {
-	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames)
+	return cmd.setScan(cmd.policy, &cmd.namespace, &cmd.setName, cmd.binNames, cmd.partitions)
 }
 
 func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
@@ -79,6 +84,17 @@ func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool,
 			return false, err
 		}
 		resultCode := ResultCode(cmd.dataBuffer[5] & 0xFF)
+		info3 := int(cmd.dataBuffer[3])
+
+		// Partition scans report each completed partition with the
+		// partition id in the generation field, without key or bins.
+		// Partitions with an error are not marked as completed.
+		if cmd.filter != nil && (info3&_INFO3_PARTITION_DONE) == _INFO3_PARTITION_DONE {
+			if resultCode == 0 {
+				cmd.filter.markDone(int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 6))))
+			}
+			continue
+		}
 
 		if resultCode != 0 {
 			if resultCode == KEY_NOT_FOUND_ERROR {
@@ -89,15 +105,13 @@ func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool,
 			return false, err
 		}
 
-		info3 := int(cmd.dataBuffer[3])
-
 		// If cmd is the end marker of the response, do not proceed further
 		if (info3 & _INFO3_LAST) == _INFO3_LAST {
 			return false, nil
 		}
 
 		generation := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 6)))
-		expiration := TTL(int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 10))))
+		expiration := int(uint32(Buffer.BytesToInt32(cmd.dataBuffer, 10)))
 		fieldCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 18)))
 		opCount := int(uint16(Buffer.BytesToInt16(cmd.dataBuffer, 20)))
 
@@ -165,14 +179,13 @@ func (cmd *scanCommand) parseRecordResults(ifc command, receiveSize int) (bool,
 	return true, nil
 }
 
-func (cmd *scanCommand) parseResult(ifc command, conn *Connection) error {
-	// close the channel
+func (cmd *scanCommand) Execute() error {
+	// close the channels, even if the command was aborted
+	// before the results were parsed
 	defer close(cmd.Records)
 	defer close(cmd.Errors)
 
-	return cmd.baseMultiCommand.parseResult(ifc, conn)
-}
-
-func (cmd *scanCommand) Execute() error {
-	return cmd.execute(cmd)
+	err := cmd.execute(cmd)
+	cmd.reportTotalTimeout(err)
+	return err
 }
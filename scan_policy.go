@@ -24,9 +24,15 @@ type ScanPolicy struct {
 	ScanPercent int //= 100;
 
 	// ConcurrentNodes determines how to issue scan requests (in parallel or sequentially).
+	// When true, MaxConcurrentNodes limits the number of nodes scanned in parallel.
+	// The number of scan threads each node uses is determined by the server
+	// configuration and the Priority of the policy.
 	ConcurrentNodes bool //= true;
 
-	// Indicates if bin data is retrieved. If false, only record digests are retrieved.
+	// IncludeBinData determines if bin data is retrieved. If false, only record
+	// metadata is retrieved: records have the digest of their key, their
+	// generation and expiration, but no bins. Bin names passed to the scan
+	// are ignored in this case.
 	IncludeBinData bool //= true;
 
 	// FailOnClusterChange determines scan termination if cluster is in fluctuating state.
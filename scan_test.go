@@ -18,6 +18,7 @@ import (
 	"flag"
 	"math"
 	"math/rand"
+	"sync"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go"
@@ -113,6 +114,63 @@ var _ = Describe("Scan operations", func() {
 		Expect(len(keys)).To(Equal(0))
 	})
 
+	It("must Scan several namespaces into a single recordset", func() {
+		_, err := client.ScanNamespaces(nil, nil, set)
+		Expect(err).To(HaveOccurred())
+
+		recordset, err := client.ScanNamespaces(nil, []string{ns}, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must return the original string, integer and blob keys sent with the records", func() {
+		set = randString(50)
+		userKeys := []interface{}{randString(50), rand.Int(), []byte(randString(50))}
+		keys = make(map[string]*Key, len(userKeys))
+		for _, userKey := range userKeys {
+			key, err := NewKey(ns, set, userKey)
+			Expect(err).ToNot(HaveOccurred())
+
+			keys[string(key.Digest())] = key
+			err = client.PutBins(wpolicy, key, bin1, bin2)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		recordset, err := client.ScanAll(nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Scan and get all records back with a small record queue", func() {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.RecordQueueSize = 1
+
+		recordset, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Scan and get all records back with a limited number of concurrent nodes", func() {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.MaxConcurrentNodes = 1
+
+		recordset, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
 	It("must Scan and get all records back from all nodes sequnetially", func() {
 		scanPolicy := NewScanPolicy()
 		scanPolicy.ConcurrentNodes = false
@@ -125,6 +183,143 @@ var _ = Describe("Scan operations", func() {
 		Expect(len(keys)).To(Equal(0))
 	})
 
+	It("must Scan only the metadata of the records without bin data", func() {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.IncludeBinData = false
+
+		recordset, err := client.ScanAll(scanPolicy, ns, set, bin1.Name)
+		Expect(err).ToNot(HaveOccurred())
+
+		for rec := range recordset.Records {
+			_, exists := keys[string(rec.Key.Digest())]
+			Expect(exists).To(BeTrue())
+			Expect(rec.Generation).To(BeNumerically(">", 0))
+			Expect(rec.Bins).To(BeEmpty())
+			delete(keys, string(rec.Key.Digest()))
+		}
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Scan partitions, and resume the scan from the cursor", func() {
+		filter := NewPartitionFilterByRange(0, 2048)
+		recordset, err := client.ScanPartitions(nil, filter, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		for rec := range recordset.Records {
+			Expect(NewPartitionByKey(rec.Key).PartitionId).To(BeNumerically("<", 2048))
+			delete(keys, string(rec.Key.Digest()))
+		}
+		for err := range recordset.Errors {
+			panic(err)
+		}
+
+		Expect(filter.Cursor()).To(Equal(2047))
+		Expect(filter.Remaining()).To(BeNil())
+
+		// scan the rest of the partitions
+		filter = NewPartitionFilterByRange(2048, 2048)
+		recordset, err = client.ScanPartitions(nil, filter, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		for rec := range recordset.Records {
+			delete(keys, string(rec.Key.Digest()))
+		}
+		Expect(filter.Cursor()).To(Equal(4095))
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must reject invalid partition ranges", func() {
+		_, err := client.ScanPartitions(nil, NewPartitionFilterByRange(4000, 100), ns, set)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("must Scan and fan out records to partitioned channels", func() {
+		recordset, err := client.ScanAll(nil, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		var mutex sync.Mutex
+		var wg sync.WaitGroup
+		for _, ch := range recordset.Partition(4) {
+			wg.Add(1)
+			go func(ch <-chan *Record) {
+				defer wg.Done()
+				for rec := range ch {
+					mutex.Lock()
+					delete(keys, string(rec.Key.Digest()))
+					mutex.Unlock()
+				}
+			}(ch)
+		}
+		wg.Wait()
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
+	It("must Scan and populate structs sent on a typed channel", func() {
+		type scanObject struct {
+			Bin1 int    `as:"Aerospike1"`
+			Bin2 string `as:"Aerospike2"`
+		}
+
+		objChan := make(chan *scanObject, 10)
+		errChan, err := client.ScanAllObjects(nil, ns, set, objChan)
+		Expect(err).ToNot(HaveOccurred())
+
+		go func() {
+			for err := range errChan {
+				panic(err)
+			}
+		}()
+
+		counter := 0
+		for obj := range objChan {
+			Expect(obj.Bin1).To(Equal(bin1.Value.GetObject()))
+			Expect(obj.Bin2).To(Equal(bin2.Value.GetObject()))
+			counter++
+		}
+
+		Expect(counter).To(Equal(keyCount))
+	})
+
+	It("must stop the Scan and report a timeout once the total timeout has elapsed", func() {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.RecordQueueSize = 1
+		scanPolicy.TotalTimeout = 50 * time.Millisecond
+
+		recordset, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		// the records are not consumed, so the scan blocks until the timeout
+		// has elapsed and the stopped commands report it
+		err = <-recordset.Errors
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Total timeout"))
+
+		counter := 0
+		for range recordset.Records {
+			counter++
+		}
+		Expect(counter).To(BeNumerically("<", keyCount))
+
+		for err := range recordset.Errors {
+			Expect(err.Error()).To(ContainSubstring("Total timeout"))
+		}
+		Eventually(recordset.IsActive).Should(BeFalse())
+	})
+
+	It("must Scan all records with a records per second limit", func() {
+		scanPolicy := NewScanPolicy()
+		scanPolicy.RecordsPerSecond = 10000
+
+		recordset, err := client.ScanAll(scanPolicy, ns, set)
+		Expect(err).ToNot(HaveOccurred())
+
+		checkResults(recordset, 0)
+
+		Expect(len(keys)).To(Equal(0))
+	})
+
 	It("must Cancel Scan", func() {
 		recordset, err := client.ScanAll(nil, ns, set)
 		Expect(err).ToNot(HaveOccurred())
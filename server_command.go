@@ -32,6 +32,14 @@ func newServerCommand(node *Node, policy *QueryPolicy, statement *Statement) *se
 	}
 }
 
+// newServerScanCommand creates a command to run a background UDF
+// on all records scanned on the node, using the scan options in policy.
+func newServerScanCommand(node *Node, policy *ScanPolicy, statement *Statement) *serverCommand {
+	cmd := newServerCommand(node, &QueryPolicy{MultiPolicy: policy.MultiPolicy}, statement)
+	cmd.scanPolicy = policy
+	return cmd
+}
+
 func (cmd *serverCommand) parseRecordResults(ifc command, receiveSize int) (bool, error) {
 	// Server commands (Query/Execute UDF) should only send back a return code.
 	// Keep parsing logic to empty socket buffer just in case server does
@@ -35,10 +35,25 @@ func newSingleCommand(cluster *Cluster, key *Key) *singleCommand {
 	}
 }
 
+// partitionCommand is implemented by the commands on a single partition,
+// which can be retried on the new owner after the partition has moved.
+type partitionCommand interface {
+	getPartition() *Partition
+}
+
+func (cmd *singleCommand) getPartition() *Partition {
+	return cmd.partition
+}
+
 func (cmd *singleCommand) getNode(ifc command) (*Node, error) {
 	return cmd.cluster.GetNode(cmd.partition)
 }
 
+// getReadNode returns the node to read the record from, according to the replica policy.
+func (cmd *singleCommand) getReadNode(policy Policy) (*Node, error) {
+	return cmd.cluster.getReadNode(cmd.partition, policy.GetBasePolicy().ReplicaPolicy)
+}
+
 func (cmd *singleCommand) emptySocket(conn *Connection) error {
 	// There should not be any more bytes.
 	// Empty the socket to be safe.
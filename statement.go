@@ -36,6 +36,9 @@ type Statement struct {
 	// aggregation function.
 	Filters []*Filter
 
+	// predExps determine additional predicates evaluated by the server (Optional)
+	predExps []PredExp
+
 	packageName  string
 	functionName string
 	functionArgs []Value
@@ -45,6 +48,9 @@ type Statement struct {
 
 	// determines if the query should return data
 	returnData bool
+
+	// partitions to resume a paginated query with; nil to start from the beginning
+	partitionFilter *PartitionFilter
 }
 
 // NewStatement initializes a new Statement instance.
@@ -64,6 +70,15 @@ func (stmt *Statement) Addfilter(filter *Filter) error {
 	return nil
 }
 
+// SetPredExp sets the predicate expressions the server evaluates on each record
+// selected by the query, in addition to the index filter.
+// Expressions must be passed in postfix notation. See PredExp.
+// Predicate expressions are only supported by Aerospike servers 3.12 and later.
+func (stmt *Statement) SetPredExp(predexp ...PredExp) error {
+	stmt.predExps = predexp
+	return nil
+}
+
 // SetAggregateFunction sets aggregation function parameters.
 // This function will be called on both the server
 // and client for each selected item.
@@ -74,6 +89,24 @@ func (stmt *Statement) SetAggregateFunction(packageName string, functionName str
 	stmt.returnData = returnData
 }
 
+// SetContinuation sets the continuation token returned by Recordset.ContinuationToken
+// after a page of a paginated query, so that the query resumes after the records
+// which have already been returned.
+// An empty token resets the statement to query from the beginning.
+func (stmt *Statement) SetContinuation(token string) error {
+	if token == "" {
+		stmt.partitionFilter = nil
+		return nil
+	}
+
+	filter, err := newPartitionFilterFromToken(token)
+	if err != nil {
+		return err
+	}
+	stmt.partitionFilter = filter
+	return nil
+}
+
 // IsScan determines is the Statement is a full namespace/set scan or a selective Query.
 func (stmt *Statement) IsScan() bool {
 	return stmt.Filters == nil
@@ -46,10 +46,7 @@ func (tskr *RegisterTask) IsDone() (bool, error) {
 		}
 
 		for _, response := range responseMap {
-			find := "filename=" + tskr.packageName
-			index := strings.Index(response, find)
-
-			if index < 0 {
+			if !udfListContains(response, tskr.packageName) {
 				return false, nil
 			}
 			done = true
@@ -58,6 +55,19 @@ func (tskr *RegisterTask) IsDone() (bool, error) {
 	return done, nil
 }
 
+// udfListContains checks if the response of the udf-list info
+// command contains the package with the exact given file name.
+func udfListContains(response string, packageName string) bool {
+	for _, udf := range strings.Split(response, ";") {
+		for _, field := range strings.Split(udf, ",") {
+			if field == "filename="+packageName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // OnComplete returns a channel that will be closed as soon as the task is finished.
 // If an error is encountered during operation, an error will be sent on the channel.
 func (tskr *RegisterTask) OnComplete() chan error {
@@ -14,10 +14,6 @@
 
 package aerospike
 
-import (
-	"strings"
-)
-
 // RemoveTask is used to poll for UDF registration completion.
 type RemoveTask struct {
 	*BaseTask
@@ -46,10 +42,7 @@ func (tskr *RemoveTask) IsDone() (bool, error) {
 		}
 
 		for _, response := range responseMap {
-			find := "filename=" + tskr.packageName
-			index := strings.Index(response, find)
-
-			if index >= 0 {
+			if udfListContains(response, tskr.packageName) {
 				return false, nil
 			}
 			done = true
@@ -17,6 +17,7 @@ package aerospike_test
 import (
 	"math/rand"
 	"reflect"
+	"sync"
 
 	. "github.com/onsi/gomega"
 
@@ -31,6 +32,29 @@ func (tb *testBLOB) EncodeBlob() ([]byte, error) {
 	return append([]byte(tb.name)), nil
 }
 
+// testLogger counts the log messages it receives
+type testLogger struct {
+	mutex    sync.Mutex
+	messages int
+}
+
+func (tl *testLogger) log() {
+	tl.mutex.Lock()
+	tl.messages++
+	tl.mutex.Unlock()
+}
+
+func (tl *testLogger) count() int {
+	tl.mutex.Lock()
+	defer tl.mutex.Unlock()
+	return tl.messages
+}
+
+func (tl *testLogger) Debug(format string, v ...interface{}) { tl.log() }
+func (tl *testLogger) Info(format string, v ...interface{})  { tl.log() }
+func (tl *testLogger) Warn(format string, v ...interface{})  { tl.log() }
+func (tl *testLogger) Error(format string, v ...interface{}) { tl.log() }
+
 // generates a random string of specified length
 func randString(size int) string {
 	const random_alpha_num = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
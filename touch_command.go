@@ -67,5 +67,8 @@ func (cmd *touchCommand) parseResult(ifc command, conn *Connection) error {
 }
 
 func (cmd *touchCommand) Execute() error {
+	if err := cmd.cluster.validateExpiration(cmd.policy.Expiration); err != nil {
+		return err
+	}
 	return cmd.execute(cmd)
 }
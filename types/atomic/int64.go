@@ -0,0 +1,45 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomic
+
+import "sync/atomic"
+
+// AtomicInt64 implements an int64 value with atomic semantics.
+// Unlike AtomicInt, its values do not overflow on 32 bit platforms.
+type AtomicInt64 struct {
+	val int64
+}
+
+// NewAtomicInt64 generates a new AtomicInt64 instance.
+func NewAtomicInt64(value int64) *AtomicInt64 {
+	return &AtomicInt64{
+		val: value,
+	}
+}
+
+// AddAndGet atomically adds the given value to the current value.
+func (ai *AtomicInt64) AddAndGet(delta int64) int64 {
+	return atomic.AddInt64(&ai.val, delta)
+}
+
+// Get atomically retrieves the current value.
+func (ai *AtomicInt64) Get() int64 {
+	return atomic.LoadInt64(&ai.val)
+}
+
+// Set atomically sets current value to the given value.
+func (ai *AtomicInt64) Set(newValue int64) {
+	atomic.StoreInt64(&ai.val, newValue)
+}
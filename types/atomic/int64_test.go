@@ -0,0 +1,57 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package atomic_test
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	. "github.com/aerospike/aerospike-client-go/types/atomic"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Atomic Int64", func() {
+	// atomic tests require actual parallelism
+	runtime.GOMAXPROCS(runtime.NumCPU())
+
+	var ai *AtomicInt64
+	wg := new(sync.WaitGroup)
+
+	BeforeEach(func() {
+		ai = NewAtomicInt64(0)
+	})
+
+	It("must add values concurrently beyond the range of 32 bit integers", func() {
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ai.AddAndGet(math.MaxInt32)
+			}()
+		}
+
+		wg.Wait()
+		Expect(ai.Get()).To(Equal(int64(100 * math.MaxInt32)))
+	})
+
+	It("must Set() and Get() values beyond the range of 32 bit integers", func() {
+		ai.Set(math.MaxInt64)
+		Expect(ai.Get()).To(Equal(int64(math.MaxInt64)))
+	})
+
+})
@@ -52,3 +52,8 @@ func (aq *AtomicQueue) Poll() interface{} {
 	}
 	return nil
 }
+
+// Len returns the number of items currently in the queue.
+func (aq *AtomicQueue) Len() int {
+	return len(aq.items)
+}
@@ -69,4 +69,15 @@ var _ = Describe("Atomic Queue", func() {
 		}
 	})
 
+	It("must report the number of elements in the queue with Len()", func() {
+		Expect(q.Len()).To(Equal(0))
+		for i := 0; i < 2*qcap; i++ {
+			q.Offer(&testStruct{i})
+		}
+		Expect(q.Len()).To(Equal(qcap))
+
+		q.Poll()
+		Expect(q.Len()).To(Equal(qcap - 1))
+	})
+
 })
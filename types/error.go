@@ -20,13 +20,17 @@ import (
 )
 
 // AerospikeError implements error interface for aerospike specific errors.
-// All errors returning from the library are of this type.
-// Errors resulting from Go's stdlib are not translated to this type, unless
-// they are a net.Timeout error.
+// All errors returning from the library are of this type, or wrap it, like NodeError.
+// Errors resulting from Go's stdlib are wrapped in this type with a client side
+// result code, e.g. NETWORK_ERROR or TIMEOUT, and can still be inspected
+// with errors.Is and errors.As.
+// Use the IsKeyNotFound, IsTimeout, IsGenerationError and IsResultCode helpers
+// to branch on the result code of an error.
 type AerospikeError struct {
 	error
 
 	resultCode ResultCode
+	inDoubt    bool
 }
 
 // ResultCode returns the ResultCode from AerospikeError object.
@@ -34,6 +38,25 @@ func (ase AerospikeError) ResultCode() ResultCode {
 	return ase.resultCode
 }
 
+// InDoubt determines if a write command may have been applied on the server,
+// even though it returned this error. This is the case when the connection
+// failed or timed out after the command was sent.
+func (ase AerospikeError) InDoubt() bool {
+	return ase.inDoubt
+}
+
+// Unwrap returns the underlying error, so that the errors
+// wrapped by WrapAerospikeError can be inspected.
+func (ase AerospikeError) Unwrap() error {
+	return ase.error
+}
+
+// MarkInDoubt returns a copy of the error which is marked as in doubt.
+func (ase AerospikeError) MarkInDoubt() AerospikeError {
+	ase.inDoubt = true
+	return ase
+}
+
 // New AerospikeError generates a new AerospikeError instance.
 // If no message is provided, the result code will be translated into the default
 // error message automatically.
@@ -45,3 +68,42 @@ func NewAerospikeError(code ResultCode, messages ...string) error {
 	err := errors.New(strings.Join(messages, " "))
 	return AerospikeError{error: err, resultCode: code}
 }
+
+// WrapAerospikeError generates a new AerospikeError instance with the result code,
+// which wraps err. If err is already an AerospikeError, it is returned as is.
+func WrapAerospikeError(code ResultCode, err error) error {
+	if _, ok := err.(AerospikeError); ok {
+		return err
+	}
+	return AerospikeError{error: err, resultCode: code}
+}
+
+// IsResultCode returns true if err is, or wraps, an AerospikeError with the result code.
+func IsResultCode(err error, code ResultCode) bool {
+	var ae AerospikeError
+	return errors.As(err, &ae) && ae.resultCode == code
+}
+
+// IsKeyNotFound returns true if the error was caused by a record which does not exist.
+func IsKeyNotFound(err error) bool {
+	return IsResultCode(err, KEY_NOT_FOUND_ERROR)
+}
+
+// IsTimeout returns true if the command timed out on the client or the server,
+// or its context deadline was exceeded.
+func IsTimeout(err error) bool {
+	return IsResultCode(err, TIMEOUT)
+}
+
+// IsGenerationError returns true if the error was caused by
+// a generation mismatch of a write command.
+func IsGenerationError(err error) bool {
+	return IsResultCode(err, GENERATION_ERROR)
+}
+
+// IsInDoubt returns true if err is, or wraps, an AerospikeError of a write
+// command which may have been applied on the server.
+func IsInDoubt(err error) bool {
+	var ae AerospikeError
+	return errors.As(err, &ae) && ae.inDoubt
+}
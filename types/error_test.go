@@ -0,0 +1,64 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	. "github.com/aerospike/aerospike-client-go/types"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AerospikeError", func() {
+
+	It("must report the result code through the helpers", func() {
+		Expect(IsKeyNotFound(NewAerospikeError(KEY_NOT_FOUND_ERROR))).To(BeTrue())
+		Expect(IsTimeout(NewAerospikeError(TIMEOUT))).To(BeTrue())
+		Expect(IsGenerationError(NewAerospikeError(GENERATION_ERROR))).To(BeTrue())
+		Expect(IsResultCode(NewAerospikeError(KEY_EXISTS_ERROR), KEY_EXISTS_ERROR)).To(BeTrue())
+
+		Expect(IsKeyNotFound(NewAerospikeError(TIMEOUT))).To(BeFalse())
+		Expect(IsTimeout(errors.New("timeout"))).To(BeFalse())
+		Expect(IsGenerationError(nil)).To(BeFalse())
+	})
+
+	It("must find the AerospikeError in wrapped errors", func() {
+		err := fmt.Errorf("put failed: %w", NewAerospikeError(GENERATION_ERROR).(AerospikeError).MarkInDoubt())
+		Expect(IsGenerationError(err)).To(BeTrue())
+		Expect(IsInDoubt(err)).To(BeTrue())
+		Expect(IsInDoubt(NewAerospikeError(GENERATION_ERROR))).To(BeFalse())
+	})
+
+	It("must keep the wrapped error inspectable", func() {
+		err := WrapAerospikeError(NETWORK_ERROR, io.EOF)
+		Expect(IsResultCode(err, NETWORK_ERROR)).To(BeTrue())
+		Expect(errors.Is(err, io.EOF)).To(BeTrue())
+		Expect(err.Error()).To(Equal(io.EOF.Error()))
+
+		err = WrapAerospikeError(TIMEOUT, fmt.Errorf("command aborted: %w", context.DeadlineExceeded))
+		Expect(IsTimeout(err)).To(BeTrue())
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+
+	It("must not wrap an AerospikeError again", func() {
+		err := NewAerospikeError(KEY_NOT_FOUND_ERROR)
+		Expect(WrapAerospikeError(NETWORK_ERROR, err)).To(Equal(err))
+	})
+})
@@ -19,6 +19,12 @@ package types
 type ResultCode int
 
 const (
+	// Command was cancelled by its context.
+	COMMAND_CANCELLED ResultCode = -10
+
+	// Network error while sending the command or receiving its result.
+	NETWORK_ERROR ResultCode = -9
+
 	// End of Recordset in Query or Scan.
 	END_OF_RECORDSET ResultCode = -8
 
@@ -81,6 +87,11 @@ const (
 	// Server is not accepting requests.
 	SERVER_NOT_AVAILABLE ResultCode = 11
 
+	// Partition is not available on the node the command was sent to,
+	// e.g. because it has moved to another node after a cluster change.
+	// Returned by the server with the same code as SERVER_NOT_AVAILABLE.
+	PARTITION_UNAVAILABLE ResultCode = 11
+
 	// Operation is not supported with configured bin type (single-bin or
 	// multi-bin).
 	BIN_TYPE_ERROR ResultCode = 12
@@ -112,6 +123,13 @@ const (
 	// Bin name length greater than 14 characters.
 	BIN_NAME_TOO_LONG ResultCode = 21
 
+	// Feature is only available in the enterprise edition of the server,
+	// e.g. durable deletes.
+	ENTERPRISE_ONLY ResultCode = 25
+
+	// Transaction was not performed because the filter expression was false.
+	FILTERED_OUT ResultCode = 27
+
 	// There are no more records left for query.
 	QUERY_END ResultCode = 50
 
@@ -139,6 +157,9 @@ const (
 	// Security credential is invalid.
 	INVALID_CREDENTIAL ResultCode = 63
 
+	// Login session expired.
+	EXPIRED_SESSION ResultCode = 66
+
 	// Role name is invalid.
 	INVALID_ROLE ResultCode = 70
 
@@ -216,6 +237,12 @@ func KeepConnection(resultCode int) bool {
 // Return result code as a string.
 func ResultCodeToString(resultCode ResultCode) string {
 	switch ResultCode(resultCode) {
+	case COMMAND_CANCELLED:
+		return "Command cancelled"
+
+	case NETWORK_ERROR:
+		return "Network error"
+
 	case END_OF_RECORDSET:
 		return "End of recordset."
 
@@ -306,6 +333,12 @@ func ResultCodeToString(resultCode ResultCode) string {
 	case BIN_NAME_TOO_LONG:
 		return "Bin name length greater than 14 characters"
 
+	case ENTERPRISE_ONLY:
+		return "Feature not supported by the server; durable deletes require the enterprise edition"
+
+	case FILTERED_OUT:
+		return "Transaction filtered out by filter expression"
+
 	case QUERY_END:
 		return "Query end"
 
@@ -339,6 +372,9 @@ func ResultCodeToString(resultCode ResultCode) string {
 	case INVALID_CREDENTIAL:
 		return "Invalid credential"
 
+	case EXPIRED_SESSION:
+		return "Login session expired"
+
 	case INVALID_ROLE:
 		return "Invalid role"
 
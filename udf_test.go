@@ -16,12 +16,16 @@ package aerospike_test
 
 import (
 	"flag"
+	"io/ioutil"
 	"math"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	. "github.com/aerospike/aerospike-client-go"
+	. "github.com/aerospike/aerospike-client-go/types"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -100,12 +104,62 @@ var _ = Describe("UDF/Query tests", func() {
 		Expect(rec.Bins[bin2.Name]).To(Equal(bin1.Value.GetObject().(int) / 2))
 	})
 
+	It("must return the device size of a record", func() {
+		key, err = NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+		err = client.PutBins(wpolicy, key, bin1, bin2)
+		Expect(err).ToNot(HaveOccurred())
+
+		size, err := client.RecordSize(nil, key)
+		if ae, ok := err.(AerospikeError); ok && ae.ResultCode() == UNSUPPORTED_FEATURE {
+			Skip("record size is not supported by the server")
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(BeNumerically(">=", 0))
+
+		missing, err := NewKey(ns, set, randString(50))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.RecordSize(nil, missing)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("must list all udfs on the server", func() {
 		udfList, err := client.ListUDF(nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(len(udfList)).To(BeNumerically(">", 0))
 	})
 
+	It("must register a udf from a file on the server", func() {
+		dir, err := ioutil.TempDir("", "udf")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "udfFromFile.lua")
+		err = ioutil.WriteFile(path, []byte(udfBody), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		regTask, err := client.RegisterUDFFromFile(wpolicy, path, "udfFromFile.lua", LUA)
+		Expect(err).ToNot(HaveOccurred())
+
+		// wait until UDF is created
+		err = <-regTask.OnComplete()
+		Expect(err).ToNot(HaveOccurred())
+
+		udfList, err := client.ListUDF(nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		found := false
+		for _, udf := range udfList {
+			if udf.Filename == "udfFromFile.lua" {
+				found = true
+			}
+		}
+		Expect(found).To(BeTrue())
+
+		_, err = client.RegisterUDFFromFile(wpolicy, filepath.Join(dir, "missing.lua"), "missing.lua", LUA)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("must drop a udf on the server", func() {
 		regTask, err := client.RegisterUDF(wpolicy, []byte(udfBody), "udfToBeDropped.lua", LUA)
 		Expect(err).ToNot(HaveOccurred())
@@ -167,6 +221,31 @@ var _ = Describe("UDF/Query tests", func() {
 			}
 		})
 
+		It("must run a UDF on all records in a background scan", func() {
+			statement := NewStatement(ns, set)
+			exTask, err := client.ExecuteUDFOnScan(nil, statement, "udf1", "testFunc1", NewValue(2))
+			Expect(err).ToNot(HaveOccurred())
+
+			// wait until UDF is run on all records
+			Expect(<-exTask.OnComplete()).ToNot(HaveOccurred())
+
+			// read all data and make sure it is consistent
+			recordset, err := client.ScanAll(nil, ns, set)
+			Expect(err).ToNot(HaveOccurred())
+
+			for fullRec := range recordset.Records {
+				Expect(fullRec.Bins[bin2.Name]).To(Equal(bin1.Value.GetObject().(int) / 2))
+			}
+		})
+
+		It("must not run a UDF in a background scan for statements with filters", func() {
+			statement := NewStatement(ns, set)
+			statement.Addfilter(NewRangeFilter(bin1.Name, 0, math.MaxInt16))
+			_, err := client.ExecuteUDFOnScan(nil, statement, "udf1", "testFunc1", NewValue(2))
+			Expect(err).To(HaveOccurred())
+			Expect(err.(AerospikeError).ResultCode()).To(Equal(PARAMETER_ERROR))
+		})
+
 		It("must run a DeleteUDF on a range of records", func() {
 			idxTask, err := client.CreateIndex(wpolicy, ns, set, set+bin1.Name, bin1.Name, NUMERIC)
 			Expect(<-idxTask.OnComplete()).ToNot(HaveOccurred())
@@ -15,6 +15,8 @@
 package aerospike
 
 import (
+	"fmt"
+
 	. "github.com/aerospike/aerospike-client-go/types"
 	ParticleType "github.com/aerospike/aerospike-client-go/types/particle_type"
 	Buffer "github.com/aerospike/aerospike-client-go/utils/buffer"
@@ -26,6 +28,31 @@ type unpacker struct {
 	length int
 }
 
+// UnpackValue deserializes a value packed by PackValue, or by the server as an
+// element of a list or a map. Values are returned as they are in the bins of a Record.
+func UnpackValue(buf []byte) (val interface{}, err error) {
+	if len(buf) == 0 {
+		return nil, NewAerospikeError(PARSE_ERROR, "No bytes to unpack")
+	}
+
+	// the unpacker trusts the server to send valid data
+	defer func() {
+		if r := recover(); r != nil {
+			val, err = nil, NewAerospikeError(PARSE_ERROR, fmt.Sprintf("Invalid packed value: %v", r))
+		}
+	}()
+
+	upckr := newUnpacker(buf, 0, len(buf))
+	if val, err = upckr.unpackObject(); err != nil {
+		return nil, err
+	}
+
+	if upckr.offset != len(buf) {
+		return nil, NewAerospikeError(PARSE_ERROR, fmt.Sprintf("%d bytes left after the packed value", len(buf)-upckr.offset))
+	}
+	return val, nil
+}
+
 func newUnpacker(buffer []byte, offset int, length int) *unpacker {
 	return &unpacker{
 		buffer: buffer,
@@ -59,6 +86,14 @@ func (upckr *unpacker) UnpackList() ([]interface{}, error) {
 }
 
 func (upckr *unpacker) unpackList(count int) ([]interface{}, error) {
+	// ordered lists carry their order flag as an ext element in the first position.
+	if count > 0 && upckr.isExt() {
+		if _, err := upckr.unpackObject(); err != nil {
+			return nil, err
+		}
+		count--
+	}
+
 	out := make([]interface{}, 0, count)
 
 	for i := 0; i < count; i++ {
@@ -76,25 +111,87 @@ func (upckr *unpacker) UnpackMap() (map[interface{}]interface{}, error) {
 		return nil, nil
 	}
 
+	count, ok := upckr.unpackMapCount()
+	if !ok {
+		return make(map[interface{}]interface{}), nil
+	}
+	return upckr.unpackMap(count)
+}
+
+// UnpackMapPairs unpacks a map into key/value pairs,
+// keeping the order of the items in the buffer.
+func (upckr *unpacker) UnpackMapPairs() ([]MapPair, error) {
+	if upckr.length <= 0 {
+		return nil, nil
+	}
+
+	count, ok := upckr.unpackMapCount()
+	if !ok {
+		return []MapPair{}, nil
+	}
+
+	count, err := upckr.skipMapOrderFlag(count)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MapPair, 0, count)
+	for i := 0; i < count; i++ {
+		key, err := upckr.unpackObject()
+		if err != nil {
+			return nil, err
+		}
+		val, err := upckr.unpackObject()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, MapPair{Key: key, Value: val})
+	}
+	return out, nil
+}
+
+// unpackMapCount reads the map header and returns the number of items,
+// or false if the next object is not a map.
+func (upckr *unpacker) unpackMapCount() (int, bool) {
 	theType := upckr.buffer[upckr.offset] & 0xff
 	upckr.offset++
-	var count int
 
 	if (theType & 0xf0) == 0x80 {
-		count = int(theType & 0x0f)
+		return int(theType & 0x0f), true
 	} else if theType == 0xde {
-		count = int(uint16(Buffer.BytesToInt16(upckr.buffer, upckr.offset)))
+		count := int(uint16(Buffer.BytesToInt16(upckr.buffer, upckr.offset)))
 		upckr.offset += 2
+		return count, true
 	} else if theType == 0xdf {
-		count = int(uint32(Buffer.BytesToInt32(upckr.buffer, upckr.offset)))
+		count := int(uint32(Buffer.BytesToInt32(upckr.buffer, upckr.offset)))
 		upckr.offset += 4
-	} else {
-		return make(map[interface{}]interface{}), nil
+		return count, true
 	}
-	return upckr.unpackMap(count)
+	return 0, false
+}
+
+// skipMapOrderFlag skips the first entry of ordered maps, and returns the remaining
+// item count. Ordered maps written by the server and other clients carry their
+// order flag as an ext key with a nil value in the first entry.
+func (upckr *unpacker) skipMapOrderFlag(count int) (int, error) {
+	if count > 0 && upckr.isExt() {
+		if _, err := upckr.unpackObject(); err != nil {
+			return 0, err
+		}
+		if _, err := upckr.unpackObject(); err != nil {
+			return 0, err
+		}
+		count--
+	}
+	return count, nil
 }
 
 func (upckr *unpacker) unpackMap(count int) (map[interface{}]interface{}, error) {
+	count, err := upckr.skipMapOrderFlag(count)
+	if err != nil {
+		return nil, err
+	}
+
 	out := make(map[interface{}]interface{}, count)
 
 	for i := 0; i < count; i++ {
@@ -111,6 +208,30 @@ func (upckr *unpacker) unpackMap(count int) (map[interface{}]interface{}, error)
 	return out, nil
 }
 
+// isExt checks if the next object is a msgpack ext type.
+func (upckr *unpacker) isExt() bool {
+	switch upckr.buffer[upckr.offset] & 0xff {
+	case 0xc7, 0xc8, 0xc9, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return true
+	}
+	return false
+}
+
+// skipExt skips the ext type and its payload of the given length.
+// Ext types carry metadata which has no representation in Go values.
+func (upckr *unpacker) skipExt(length int) (interface{}, error) {
+	upckr.offset += 1 + length
+	return nil, nil
+}
+
+// unpackRawBytes unpacks standard msgpack binary data,
+// which has no particle type prefix.
+func (upckr *unpacker) unpackRawBytes(count int) (interface{}, error) {
+	val := upckr.buffer[upckr.offset : upckr.offset+count]
+	upckr.offset += count
+	return val, nil
+}
+
 func (upckr *unpacker) unpackBlob(count int) (interface{}, error) {
 	theType := upckr.buffer[upckr.offset] & 0xff
 	upckr.offset++
@@ -118,10 +239,16 @@ func (upckr *unpacker) unpackBlob(count int) (interface{}, error) {
 	var val interface{}
 
 	switch theType {
-	case ParticleType.STRING:
+	case ParticleType.STRING, ParticleType.GEOJSON:
 		val = string(upckr.buffer[upckr.offset : upckr.offset+count])
 		break
 
+	case ParticleType.HLL:
+		newObj := make([]byte, count)
+		copy(newObj, upckr.buffer[upckr.offset:upckr.offset+count])
+		val = NewHLLValue(newObj)
+		break
+
 	default:
 		val = upckr.buffer[upckr.offset : upckr.offset+count]
 		break
@@ -233,6 +360,56 @@ func (upckr *unpacker) unpackObject() (interface{}, error) {
 		upckr.offset += 4
 		return upckr.unpackMap(count)
 
+	case 0xd9:
+		count := int(upckr.buffer[upckr.offset] & 0xff)
+		upckr.offset++
+		return upckr.unpackBlob(count)
+
+	case 0xc4:
+		count := int(upckr.buffer[upckr.offset] & 0xff)
+		upckr.offset++
+		return upckr.unpackRawBytes(count)
+
+	case 0xc5:
+		count := int(uint16(Buffer.BytesToInt16(upckr.buffer, upckr.offset)))
+		upckr.offset += 2
+		return upckr.unpackRawBytes(count)
+
+	case 0xc6:
+		count := int(uint32(Buffer.BytesToInt32(upckr.buffer, upckr.offset)))
+		upckr.offset += 4
+		return upckr.unpackRawBytes(count)
+
+	case 0xd4:
+		return upckr.skipExt(1)
+
+	case 0xd5:
+		return upckr.skipExt(2)
+
+	case 0xd6:
+		return upckr.skipExt(4)
+
+	case 0xd7:
+		return upckr.skipExt(8)
+
+	case 0xd8:
+		return upckr.skipExt(16)
+
+	case 0xc7:
+		count := int(upckr.buffer[upckr.offset] & 0xff)
+		upckr.offset++
+		return upckr.skipExt(count)
+
+	case 0xc8:
+		count := int(uint16(Buffer.BytesToInt16(upckr.buffer, upckr.offset)))
+		upckr.offset += 2
+		return upckr.skipExt(count)
+
+	case 0xc9:
+		count := int(uint32(Buffer.BytesToInt32(upckr.buffer, upckr.offset)))
+		upckr.offset += 4
+		return upckr.skipExt(count)
+
 	default:
 		if (theType & 0xe0) == 0xa0 {
 			return upckr.unpackBlob(int(theType & 0x1f))
@@ -0,0 +1,41 @@
+// Copyright 2013-2014 Aerospike, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package aerospike
+
+// Pre-defined user roles.
+const (
+	// UserAdmin allows to manage users and their roles.
+	UserAdmin = "user-admin"
+
+	// SysAdmin allows to manage indexes, user defined functions and server configuration.
+	SysAdmin = "sys-admin"
+
+	// ReadWrite allows read and write transactions with the database.
+	ReadWrite = "read-write"
+
+	// ReadWriteUDF allows read and write transactions, and user defined functions with the database.
+	ReadWriteUDF = "read-write-udf"
+
+	// Read allows read transactions with the database.
+	Read = "read"
+)
+
+// UserRoles contains the roles of a user.
+type UserRoles struct {
+	// User name.
+	User string
+
+	// Roles is the list of the roles assigned to the user.
+	Roles []string
+}
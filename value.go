@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 	"unsafe"
 
 	// . "github.com/aerospike/aerospike-client-go/logger"
@@ -101,6 +103,10 @@ func NewValue(v interface{}) Value {
 		}
 	case int64:
 		return NewLongValue(int64(val))
+	case float32:
+		return NewFloatValue(float64(val))
+	case float64:
+		return NewFloatValue(val)
 	case []interface{}:
 		return NewListValue(val)
 	case map[interface{}]interface{}:
@@ -109,12 +115,31 @@ func NewValue(v interface{}) Value {
 		return val
 	case AerospikeBlob:
 		return NewBlobValue(val)
+	case time.Time:
+		return NewTimeValue(val)
+	case net.IP:
+		return NewBytesValue([]byte(val))
 	}
 
-	// check for array and map
+	// check for pointer, array and map
 	switch reflect.TypeOf(v).Kind() {
+	case reflect.Ptr:
+		// nil pointers are written as nil, which removes the bin
+		s := reflect.ValueOf(v)
+		if s.IsNil() {
+			return &NullValue{}
+		}
+		return NewValue(s.Elem().Interface())
+	case reflect.Float32, reflect.Float64:
+		// named float types
+		return NewFloatValue(reflect.ValueOf(v).Float())
 	case reflect.Array, reflect.Slice:
 		s := reflect.ValueOf(v)
+		if s.Kind() == reflect.Array && s.Type().Elem().Kind() == reflect.Uint8 {
+			// fixed size byte arrays, like UUIDs, are stored as blobs
+			return NewBytesValue(byteArrayToBytes(s))
+		}
+
 		l := s.Len()
 		arr := make([]interface{}, l)
 		for i := 0; i < l; i++ {
@@ -137,6 +162,13 @@ func NewValue(v interface{}) Value {
 	panic(NewAerospikeError(TYPE_NOT_SUPPORTED, "Value type '"+reflect.TypeOf(v).Name()+"' not supported"))
 }
 
+// byteArrayToBytes copies the contents of a fixed size byte array to a slice.
+func byteArrayToBytes(arr reflect.Value) []byte {
+	res := make([]byte, arr.Len())
+	reflect.Copy(reflect.ValueOf(res), arr)
+	return res
+}
+
 // NullValue is an empty value.
 type NullValue struct{}
 
@@ -292,6 +324,107 @@ func (vl *StringValue) String() string {
 
 ///////////////////////////////////////////////////////////////////////////////
 
+// GeoJSONValue encapsulates a GeoJSON string value.
+// Supported by Aerospike 3.7 servers and later.
+type GeoJSONValue struct {
+	value string
+}
+
+// NewGeoJSONValue generates a GeoJSONValue instance.
+func NewGeoJSONValue(value string) *GeoJSONValue {
+	return &GeoJSONValue{value: value}
+}
+
+func (vl *GeoJSONValue) estimateSize() int {
+	// flags + ncells + jsonstr
+	return 1 + 2 + len(vl.value)
+}
+
+func (vl *GeoJSONValue) write(buffer []byte, offset int) (int, error) {
+	buffer[offset] = 0                       // flags
+	Buffer.Int16ToBytes(0, buffer, offset+1) // ncells
+	return 1 + 2 + copy(buffer[offset+3:], vl.value), nil
+}
+
+func (vl *GeoJSONValue) pack(packer *packer) error {
+	packer.PackGeoJSON(vl.value)
+	return nil
+}
+
+// GetType returns wire protocol value type.
+func (vl *GeoJSONValue) GetType() int {
+	return ParticleType.GEOJSON
+}
+
+// GetObject returns original value as an interface{}.
+func (vl *GeoJSONValue) GetObject() interface{} {
+	return vl.value
+}
+
+func (vl *GeoJSONValue) reader() io.Reader {
+	return strings.NewReader(vl.value)
+}
+
+// String implements Stringer interface.
+func (vl *GeoJSONValue) String() string {
+	return vl.value
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
+// HLLValue encapsulates a HyperLogLog value, as stored in HLL bins.
+// HLL bins are read as HLLValue instances, which can be passed to HLL operations.
+// Supported by Aerospike 4.9 servers and later.
+type HLLValue struct {
+	bytes []byte
+}
+
+// NewHLLValue generates a HLLValue instance.
+func NewHLLValue(bytes []byte) *HLLValue {
+	return &HLLValue{bytes: bytes}
+}
+
+// Bytes returns the serialized HyperLogLog.
+func (vl *HLLValue) Bytes() []byte {
+	return vl.bytes
+}
+
+func (vl *HLLValue) estimateSize() int {
+	return len(vl.bytes)
+}
+
+func (vl *HLLValue) write(buffer []byte, offset int) (int, error) {
+	return copy(buffer[offset:], vl.bytes), nil
+}
+
+func (vl *HLLValue) pack(packer *packer) error {
+	packer.PackByteArrayBegin(len(vl.bytes) + 1)
+	packer.PackAByte(ParticleType.HLL)
+	packer.PackByteArray(vl.bytes, 0, len(vl.bytes))
+	return nil
+}
+
+// GetType returns wire protocol value type.
+func (vl *HLLValue) GetType() int {
+	return ParticleType.HLL
+}
+
+// GetObject returns original value as an interface{}.
+func (vl *HLLValue) GetObject() interface{} {
+	return vl.bytes
+}
+
+func (vl *HLLValue) reader() io.Reader {
+	return bytes.NewReader(vl.bytes)
+}
+
+// String implements Stringer interface.
+func (vl *HLLValue) String() string {
+	return Buffer.BytesToHexString(vl.bytes)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
 // IntegerValue encapsulates an integer value.
 type IntegerValue struct {
 	value int
@@ -355,6 +488,13 @@ func NewLongValue(value int64) *LongValue {
 	return &LongValue{value: value}
 }
 
+// NewTimeValue generates a LongValue holding t as nanoseconds since the Unix epoch.
+// The location and monotonic clock reading of t are not stored, and only
+// times between the years 1678 and 2262 can be represented.
+func NewTimeValue(t time.Time) *LongValue {
+	return NewLongValue(t.UnixNano())
+}
+
 func (vl *LongValue) estimateSize() int {
 	return 8
 }
@@ -394,6 +534,52 @@ func (vl *LongValue) String() string {
 
 ///////////////////////////////////////////////////////////////////////////////
 
+// FloatValue encapsulates a float64 value.
+// Supported by Aerospike 3.6 servers and later.
+type FloatValue struct {
+	value float64
+}
+
+// NewFloatValue generates a FloatValue instance.
+func NewFloatValue(value float64) *FloatValue {
+	return &FloatValue{value: value}
+}
+
+func (vl *FloatValue) estimateSize() int {
+	return 8
+}
+
+func (vl *FloatValue) write(buffer []byte, offset int) (int, error) {
+	Buffer.Float64ToBytes(vl.value, buffer, offset)
+	return 8, nil
+}
+
+func (vl *FloatValue) pack(packer *packer) error {
+	packer.PackFloat64(vl.value)
+	return nil
+}
+
+// GetType returns wire protocol value type.
+func (vl *FloatValue) GetType() int {
+	return ParticleType.FLOAT
+}
+
+// GetObject returns original value as an interface{}.
+func (vl *FloatValue) GetObject() interface{} {
+	return vl.value
+}
+
+func (vl *FloatValue) reader() io.Reader {
+	return bytes.NewReader(Buffer.Float64ToBytes(vl.value, nil, 0))
+}
+
+// String implements Stringer interface.
+func (vl *FloatValue) String() string {
+	return strconv.FormatFloat(vl.value, 'g', -1, 64)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+
 // ValueArray encapsulates an array of Value.
 // Supported by Aerospike 3 servers only.
 type ValueArray struct {
@@ -488,7 +674,9 @@ func (vl *ListValue) write(buffer []byte, offset int) (int, error) {
 }
 
 func (vl *ListValue) pack(packer *packer) error {
-	// return packer.PackList(vl.list)
+	if packer.msgpack {
+		return packer.PackList(vl.list)
+	}
 	_, err := packer.buffer.Write(vl.bytes)
 	return err
 }
@@ -522,6 +710,7 @@ func (vl *ListValue) String() string {
 // Supported by Aerospike 3 servers only.
 type MapValue struct {
 	vmap  map[interface{}]interface{}
+	order MapOrder
 	bytes []byte
 }
 
@@ -536,6 +725,27 @@ func NewMapValue(vmap map[interface{}]interface{}) *MapValue {
 	return res
 }
 
+// NewOrderedMapValue generates a MapValue instance which is stored on the server
+// with the given order. Ordered maps are packed the same way the other Aerospike
+// clients pack them, with the entries sorted by key.
+func NewOrderedMapValue(vmap map[interface{}]interface{}, order MapOrder) *MapValue {
+	res := &MapValue{
+		vmap:  vmap,
+		order: order,
+	}
+
+	packer := newPacker()
+	packer.PackOrderedMap(vmap, order)
+	res.bytes = packer.buffer.Bytes()
+
+	return res
+}
+
+// Order returns the order the map is stored with on the server.
+func (vl *MapValue) Order() MapOrder {
+	return vl.order
+}
+
 func (vl *MapValue) estimateSize() int {
 	return len(vl.bytes)
 }
@@ -545,7 +755,9 @@ func (vl *MapValue) write(buffer []byte, offset int) (int, error) {
 }
 
 func (vl *MapValue) pack(packer *packer) error {
-	// return packer.PackMap(vl.vmap)
+	if packer.msgpack {
+		return packer.PackOrderedMap(vl.vmap, vl.order)
+	}
 	_, err := packer.buffer.Write(vl.bytes)
 	return err
 }
@@ -581,6 +793,9 @@ func bytesToParticle(ptype int, buf []byte, offset int, length int) (interface{}
 	case ParticleType.INTEGER:
 		return Buffer.BytesToNumber(buf, offset, length), nil
 
+	case ParticleType.FLOAT:
+		return Buffer.BytesToFloat64(buf, offset), nil
+
 	case ParticleType.STRING:
 		return string(buf[offset : offset+length]), nil
 
@@ -589,12 +804,23 @@ func bytesToParticle(ptype int, buf []byte, offset int, length int) (interface{}
 		copy(newObj, buf[offset:offset+length])
 		return newObj, nil
 
+	case ParticleType.HLL:
+		newObj := make([]byte, length)
+		copy(newObj, buf[offset:offset+length])
+		return NewHLLValue(newObj), nil
+
 	case ParticleType.LIST:
 		return newUnpacker(buf, offset, length).UnpackList()
 
 	case ParticleType.MAP:
 		return newUnpacker(buf, offset, length).UnpackMap()
 
+	case ParticleType.GEOJSON:
+		// skip flags and cells
+		ncells := int(Buffer.BytesToInt16(buf, offset+1))
+		headerSize := 1 + 2 + ncells*8
+		return string(buf[offset+headerSize : offset+length]), nil
+
 	}
 	return nil, nil
 }
@@ -606,7 +832,7 @@ func bytesToKeyValue(pType int, buf []byte, offset int, len int) (Value, error)
 		return NewStringValue(string(buf[offset : offset+len])), nil
 
 	case ParticleType.INTEGER:
-		return NewLongValue(Buffer.VarBytesToInt64(buf, offset, len)), nil
+		return NewValue(Buffer.BytesToNumber(buf, offset, len)), nil
 
 	case ParticleType.BLOB:
 		bytes := make([]byte, len, len)
@@ -16,7 +16,11 @@ package aerospike
 
 import (
 	"math"
+	"net"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 	"unsafe"
 
 	. "github.com/onsi/ginkgo"
@@ -63,6 +67,41 @@ var _ = Describe("Value Test", func() {
 			Expect(v.estimateSize()).To(Equal(0))
 			Expect(v.GetType()).To(Equal(ParticleType.NULL))
 		})
+
+		It("should create a NullValue from nil pointers, and dereference other pointers", func() {
+			var s *string
+			Expect(NewValue(s)).To(Equal(&NullValue{}))
+
+			i := 42
+			Expect(NewValue(&i)).To(Equal(NewIntegerValue(42)))
+		})
+	})
+
+	Context("FloatValues", func() {
+		It("should create a FloatValue from float32 and float64 values", func() {
+			for _, f := range []interface{}{float32(1.5), float64(1.5)} {
+				v := NewValue(f)
+
+				Expect(v).To(Equal(NewFloatValue(1.5)))
+				Expect(v.GetObject()).To(Equal(float64(1.5)))
+				Expect(v.estimateSize()).To(Equal(8))
+				Expect(v.GetType()).To(Equal(ParticleType.FLOAT))
+			}
+		})
+
+		It("should write and read back a float64 without losing precision", func() {
+			f := 0.1 + 0.2
+			v := NewFloatValue(f)
+
+			buf := make([]byte, v.estimateSize())
+			n, err := v.write(buf, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n).To(Equal(8))
+
+			res, err := bytesToParticle(ParticleType.FLOAT, buf, 0, n)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(f))
+		})
 	})
 
 	Context("StringValues", func() {
@@ -95,6 +134,47 @@ var _ = Describe("Value Test", func() {
 			Expect(bval).To(BeAssignableToTypeOf(&BytesValue{}))
 			Expect(bval.GetObject()).To(Equal([]byte(person.name)))
 		})
+
+		It("should create a BytesValue from net.IP and fixed size byte arrays", func() {
+			ip := net.ParseIP("192.168.1.10").To4()
+			v := NewValue(ip)
+			Expect(v.GetType()).To(Equal(ParticleType.BLOB))
+			Expect(v.GetObject()).To(Equal([]byte(ip)))
+
+			uuid := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+			v = NewValue(uuid)
+			Expect(v.GetType()).To(Equal(ParticleType.BLOB))
+			Expect(v.GetObject()).To(Equal(uuid[:]))
+
+			rec := newRecord(nil, nil, BinMap{"ip": v.GetObject(), "addr": []byte(ip)}, nil, 1, 0)
+			res, ok := rec.GetUUID("ip")
+			Expect(ok).To(BeTrue())
+			Expect(res).To(Equal(uuid))
+
+			addr, ok := rec.GetIP("addr")
+			Expect(ok).To(BeTrue())
+			Expect(addr.Equal(ip)).To(BeTrue())
+		})
+
+		It("should pack net.IP and fixed size byte arrays in lists as blobs", func() {
+			ip := net.ParseIP("::1")
+			uuid := [16]byte{1, 2, 3}
+
+			buf, err := packAnyArray([]interface{}{ip, uuid})
+			Expect(err).ToNot(HaveOccurred())
+
+			list, err := newUnpacker(buf, 0, len(buf)).UnpackList()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(list).To(Equal([]interface{}{[]byte(ip), uuid[:]}))
+		})
+	})
+
+	Context("Time Values", func() {
+		It("should create a valid LongValue from time.Time", func() {
+			t := time.Date(2015, 1, 2, 3, 4, 5, 6, time.UTC)
+			v := NewValue(t)
+			isValidLongValue(t.UnixNano(), v)
+		})
 	})
 
 	Context("Numeric Values", func() {
@@ -196,4 +276,115 @@ var _ = Describe("Value Test", func() {
 		})
 
 	}) // numeric values context
+
+	Context("Map Values", func() {
+
+		It("should pack ordered maps with the order flag and sorted keys", func() {
+			m := map[interface{}]interface{}{3: "c", 1: "a", 2: "b"}
+			v := NewOrderedMapValue(m, KEY_ORDERED)
+			Expect(v.Order()).To(Equal(KEY_ORDERED))
+			Expect(v.GetObject()).To(Equal(m))
+
+			b := v.bytes
+			// map header with 4 entries, ext8 header with the order flag and nil value
+			Expect(b[:5]).To(Equal([]byte{0x84, 0xc7, 0x00, byte(KEY_ORDERED), 0xc0}))
+			// keys are sorted
+			Expect(b[5]).To(Equal(byte(1)))
+
+			res, err := newUnpacker(b, 0, len(b)).UnpackMap()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{1: "a", 2: "b", 3: "c"}))
+		})
+
+		It("should unpack ordered maps into key/value pairs in their order", func() {
+			m := map[interface{}]interface{}{3: "c", 1: "a", 2: "b"}
+			b := NewOrderedMapValue(m, KEY_ORDERED).bytes
+
+			res, err := newUnpacker(b, 0, len(b)).UnpackMapPairs()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]MapPair{{Key: 1, Value: "a"}, {Key: 2, Value: "b"}, {Key: 3, Value: "c"}}))
+		})
+
+		It("should pack unordered maps as plain maps", func() {
+			m := map[interface{}]interface{}{1: "a"}
+			Expect(NewOrderedMapValue(m, UNORDERED).bytes).To(Equal(NewMapValue(m).bytes))
+		})
+
+		It("should unpack standard msgpack types written by other clients", func() {
+			// list with an ext order flag, a str8 string, a bin8 blob and a fixext element
+			b := []byte{0x94, 0xd4, 0x00, 0x01, 0xd9, 0x03, byte(ParticleType.STRING), 'a', 'b', 0xc4, 0x02, 0x01, 0x02, 0xd5, 0x00, 0x01, 0x02}
+			res, err := newUnpacker(b, 0, len(b)).UnpackList()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal([]interface{}{"ab", []byte{0x01, 0x02}, nil}))
+		})
+
+		It("should sort map keys of every type in a total order", func() {
+			type custom struct{ a int }
+			keys := []interface{}{
+				custom{2}, 1.5, []byte{2}, [2]byte{1, 0},
+				map[string]int{"b": 1}, map[string]int{"a": 2}, map[string]int{"a": 1},
+				[2]int{1, 2}, []interface{}{1}, []interface{}{1, 1},
+				"b", NewStringValue("a"), 2, uint8(1), true, false, nil, custom{1},
+			}
+			sort.Sort(mapKeys(keys))
+			Expect(keys).To(Equal([]interface{}{
+				nil, false, true, uint8(1), 2, NewStringValue("a"), "b",
+				[]interface{}{1}, []interface{}{1, 1}, [2]int{1, 2},
+				map[string]int{"a": 1}, map[string]int{"a": 2}, map[string]int{"b": 1},
+				[2]byte{1, 0}, []byte{2}, 1.5, custom{1}, custom{2},
+			}))
+
+			for i := range keys {
+				Expect(compareMapKeys(keys[i], keys[i])).To(Equal(0))
+				for j := i + 1; j < len(keys); j++ {
+					Expect(compareMapKeys(keys[i], keys[j])).To(Equal(-1))
+					Expect(compareMapKeys(keys[j], keys[i])).To(Equal(1))
+				}
+			}
+		})
+
+		It("should repack lists and maps the way the other clients pack them", func() {
+			long := strings.Repeat("s", 40)
+			m := map[interface{}]interface{}{"b": long, "a": []interface{}{NewMapValue(map[interface{}]interface{}{2: 0, 1: 0})}}
+
+			b := msgpackValue(NewMapValue(m)).(*MapValue).bytes
+			Expect(b).To(Equal(append([]byte{
+				0x82,
+				0xa2, byte(ParticleType.STRING), 'a', 0x91, 0x82, 0x01, 0x00, 0x02, 0x00,
+				0xa2, byte(ParticleType.STRING), 'b', 0xd9, 41, byte(ParticleType.STRING)},
+				long...),
+			))
+
+			res, err := newUnpacker(b, 0, len(b)).UnpackMap()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res).To(Equal(map[interface{}]interface{}{"b": long, "a": []interface{}{map[interface{}]interface{}{1: 0, 2: 0}}}))
+
+			// ordered maps keep their order flag
+			ordered := msgpackValue(NewOrderedMapValue(map[interface{}]interface{}{1: long}, KEY_VALUE_ORDERED)).(*MapValue)
+			Expect(ordered.Order()).To(Equal(KEY_VALUE_ORDERED))
+			Expect(ordered.bytes[:7]).To(Equal([]byte{0x82, 0xc7, 0x00, byte(KEY_VALUE_ORDERED), 0xc0, 0x01, 0xd9}))
+
+			list := msgpackValue(NewListValue([]interface{}{[]byte(long)})).(*ListValue)
+			Expect(list.bytes[:3]).To(Equal([]byte{0x91, 0xd9, 41}))
+			Expect(list.GetObject()).To(Equal([]interface{}{[]byte(long)}))
+
+			// other values are not repacked
+			s := NewStringValue(long)
+			Expect(msgpackValue(s)).To(BeIdenticalTo(s))
+		})
+
+		It("should not change the bins and operations of the caller", func() {
+			bins := []*Bin{NewBin("m", map[interface{}]interface{}{"a": 1}), NewBin("i", 1)}
+			res := msgpackBins(bins)
+			Expect(res[0]).ToNot(BeIdenticalTo(bins[0]))
+			Expect(res[0].Name).To(Equal("m"))
+			Expect(res[1].Value).To(BeIdenticalTo(bins[1].Value))
+
+			ops := []*Operation{PutOp(bins[0]), GetOp()}
+			resOps := msgpackOperations(ops)
+			Expect(resOps[0]).ToNot(BeIdenticalTo(ops[0]))
+			Expect(ops[0].BinValue).To(BeIdenticalTo(bins[0].Value))
+			Expect(resOps[1]).To(BeIdenticalTo(ops[1]))
+		})
+	})
 })
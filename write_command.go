@@ -74,5 +74,11 @@ func (cmd *writeCommand) parseResult(ifc command, conn *Connection) error {
 }
 
 func (cmd *writeCommand) Execute() error {
+	if err := cmd.cluster.validateExpiration(cmd.policy.Expiration); err != nil {
+		return err
+	}
+	if err := cmd.cluster.validateBins(cmd.bins); err != nil {
+		return err
+	}
 	return cmd.execute(cmd)
 }
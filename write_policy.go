@@ -14,6 +14,18 @@
 
 package aerospike
 
+const (
+	// TTLServerDefault will default the record expiration to the namespace
+	// configuration variable "default-ttl" on the server.
+	TTLServerDefault = 0
+	// TTLNeverExpire will never expire the record.
+	// Requires Aerospike 2 server versions >= 2.7.2 or Aerospike 3 server versions >= 3.1.4.
+	TTLNeverExpire = -1
+	// TTLDontUpdate will not change the record's existing expiration on update.
+	// Requires Aerospike server versions >= 3.10.1.
+	TTLDontUpdate = -2
+)
+
 // WritePolicy encapsulates parameters for policy attributes used in write operations.
 // This object is passed into methods where database writes can occur.
 type WritePolicy struct {
@@ -35,15 +47,65 @@ type WritePolicy struct {
 	// Expiration determimes record expiration in seconds. Also known as TTL (Time-To-Live).
 	// Seconds record will live before being removed by the server.
 	// Expiration values:
-	// -1: Never expire for Aerospike 2 server versions >= 2.7.2 and Aerospike 3 server
+	// TTLServerDefault (0): Default to namespace configuration variable "default-ttl" on the server.
+	// TTLNeverExpire (-1): Never expire for Aerospike 2 server versions >= 2.7.2 and Aerospike 3 server
 	// versions >= 3.1.4.  Do not use -1 for older servers.
-	// 0: Default to namespace configuration variable "default-ttl" on the server.
+	// TTLDontUpdate (-2): Do not change the record's existing expiration on update.
+	// Requires Aerospike server versions >= 3.10.1.
 	// > 0: Actual expiration in seconds.
 	Expiration int32
 
 	// Send user defined key in addition to hash digest on a record put.
+	// The server stores the key with the record, and returns it in Record.Key
+	// of scans and queries. Applies to Put, Touch, Operate and Execute commands.
 	// The default is to not send the user defined key.
 	SendKey bool
+
+	// NoRetryInDoubt prevents retrying the command once it may have been applied
+	// on the server, i.e. when the connection failed or timed out after the command
+	// was sent. Retrying such a command could apply it twice, like an Add operation
+	// incrementing a counter twice. The returned error is then marked as in doubt;
+	// see AerospikeError.InDoubt.
+	NoRetryInDoubt bool
+
+	// DurableDelete makes the server leave a tombstone when the command deletes
+	// the record, so that the record is not restored by a cold restart.
+	// It applies to Delete, and to Operate and Put commands which remove the
+	// last bin of the record. Servers which do not support durable deletes
+	// return an ENTERPRISE_ONLY error.
+	// Reads of a tombstone return KEY_NOT_FOUND_ERROR, as for records which
+	// never existed; the server does not report tombstones to the client.
+	DurableDelete bool
+
+	// Xdr marks the writes of the command as made by XDR. XDR does not ship
+	// such writes to the other datacenters, unless forwarding is enabled on the
+	// server, so that writes made by reconciliation tools which emulate XDR are
+	// not shipped back and forth between datacenters.
+	// It applies to Put, Delete, Touch, Execute and Operate commands with write
+	// operations.
+	Xdr bool
+
+	// RespondPerEachOp asks the server to return a result for every operation in
+	// an Operate command, in the order of the operations. Writes which return no
+	// value, like PutOp, return nil. Multiple results for the same bin are returned
+	// as OpResults; without this flag, only the last result of a bin is returned.
+	RespondPerEachOp bool
+
+	// FilterExpression holds predicate expressions evaluated by the server on the
+	// record before Put, Delete and Operate commands are applied. If they evaluate
+	// to false, the record is not changed and a FILTERED_OUT error is returned.
+	// Expressions must be passed in postfix notation. See PredExp.
+	// Requires Aerospike server 4.7 or later.
+	FilterExpression []PredExp
+
+	// UseMsgpack packs list and map bins the way the Java and Python clients
+	// pack them: strings and blobs with the shortest msgpack header, and map
+	// entries sorted by key, whether the map is ordered or not. Maps keep their
+	// order flag, so ordered and unordered maps are still told apart.
+	// The same bin written by clients in different languages then has the
+	// same bytes on the server. It applies to Put and to the PutOp operations
+	// of Operate commands. Packing is slower, since the entries are sorted.
+	UseMsgpack bool
 }
 
 // NewWritePolicy initializes a new WritePolicy instance with default parameters.